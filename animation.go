@@ -0,0 +1,481 @@
+package furex
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// FlexProperty is a property of a view that can be animated.
+type FlexProperty uint8
+
+const (
+	PropertyLeft FlexProperty = iota
+	PropertyTop
+	PropertyWidth
+	PropertyHeight
+	PropertyMarginLeft
+	PropertyMarginTop
+	PropertyMarginRight
+	PropertyMarginBottom
+	PropertyOpacity
+	PropertyScale
+	PropertyColor
+	PropertyBackgroundColor
+	PropertyBorderColor
+	// PropertyAll is every property in a transition.
+	PropertyAll
+)
+
+var propertyNames = [...]string{
+	PropertyLeft:            "left",
+	PropertyTop:             "top",
+	PropertyWidth:           "width",
+	PropertyHeight:          "height",
+	PropertyMarginLeft:      "margin-left",
+	PropertyMarginTop:       "margin-top",
+	PropertyMarginRight:     "margin-right",
+	PropertyMarginBottom:    "margin-bottom",
+	PropertyOpacity:         "opacity",
+	PropertyScale:           "scale",
+	PropertyColor:           "color",
+	PropertyBackgroundColor: "background-color",
+	PropertyBorderColor:     "border-color",
+	PropertyAll:             "all",
+}
+
+func (p FlexProperty) String() string {
+	if int(p) < len(propertyNames) {
+		return propertyNames[p]
+	}
+	return fmt.Sprintf("unknown property: %d", p)
+}
+
+// isColor returns true if the values of the property are colors.
+func (p FlexProperty) isColor() bool {
+	return p == PropertyColor || p == PropertyBackgroundColor || p == PropertyBorderColor
+}
+
+// Easing maps the progress of an animation from 0 to 1 to the progress of its value.
+type Easing interface {
+	Ease(t float64) float64
+}
+
+// EasingFunc is a function used as an Easing.
+type EasingFunc func(t float64) float64
+
+func (f EasingFunc) Ease(t float64) float64 {
+	return f(t)
+}
+
+// CubicBezier is the easing of a cubic Bézier curve from (0, 0) to (1, 1)
+// with the control points (X1, Y1) and (X2, Y2), like cubic-bezier() in CSS.
+type CubicBezier struct {
+	X1, Y1, X2, Y2 float64
+}
+
+func (c CubicBezier) Ease(t float64) float64 {
+	if t <= 0 || t >= 1 || (c.X1 == c.Y1 && c.X2 == c.Y2) {
+		return t
+	}
+	bezier := func(p1, p2, s float64) float64 {
+		return 3*p1*s*(1-s)*(1-s) + 3*p2*s*s*(1-s) + s*s*s
+	}
+	// Find the parameter of the curve at x = t by bisection,
+	// the curve is monotonic in x as X1 and X2 are in [0, 1].
+	lo, hi := 0.0, 1.0
+	s := t
+	for i := 0; i < 32; i++ {
+		x := bezier(c.X1, c.X2, s)
+		if math.Abs(x-t) < 1e-7 {
+			break
+		}
+		if x < t {
+			lo = s
+		} else {
+			hi = s
+		}
+		s = (lo + hi) / 2
+	}
+	return bezier(c.Y1, c.Y2, s)
+}
+
+// The easings of CSS.
+var (
+	EaseLinear    Easing = CubicBezier{0, 0, 1, 1}
+	Ease          Easing = CubicBezier{0.25, 0.1, 0.25, 1}
+	EaseIn        Easing = CubicBezier{0.42, 0, 1, 1}
+	EaseOut       Easing = CubicBezier{0, 0, 0.58, 1}
+	EaseInOut     Easing = CubicBezier{0.42, 0, 0.58, 1}
+	easingsByName        = map[string]Easing{
+		"linear":      EaseLinear,
+		"ease":        Ease,
+		"ease-in":     EaseIn,
+		"ease-out":    EaseOut,
+		"ease-in-out": EaseInOut,
+	}
+)
+
+// Tween animates a property of a view from its current value to a value.
+type Tween struct {
+	Property FlexProperty
+	// To is the value a numeric property is animated to.
+	To float64
+	// ToColor is the value a color property is animated to.
+	ToColor color.Color
+	// From and FromColor are the values the property is animated from.
+	// The property is animated from its current value if they are nil.
+	From      *float64
+	FromColor color.Color
+	Duration  time.Duration
+	// Delay is the time before the tween starts.
+	Delay time.Duration
+	// Easing is the easing of the tween, it is linear if nil.
+	Easing Easing
+	// Repeat is the number of times the tween is played again after the first time,
+	// it is repeated forever if negative.
+	Repeat int
+	// Yoyo plays every other repetition backwards.
+	Yoyo bool
+	// OnComplete is called when the tween ends. It is not called if the tween is stopped.
+	OnComplete func(v *View)
+}
+
+// Transition is the 'transition' property, which animates a property when it is
+// changed with its setter, such as SetLeft for PropertyLeft.
+type Transition struct {
+	Property FlexProperty
+	Duration time.Duration
+	Delay    time.Duration
+	// Easing is the easing of the transition, it is Ease if nil.
+	Easing Easing
+}
+
+// animValue is the value of a property. Colors are premultiplied RGBA from 0 to 255,
+// so a transparent color fades in the color it is animated to, like in CSS.
+type animValue [4]float64
+
+func colorValue(clr color.Color) animValue {
+	if clr == nil {
+		return animValue{}
+	}
+	r, g, b, a := clr.RGBA()
+	return animValue{float64(r) / 257, float64(g) / 257, float64(b) / 257, float64(a) / 257}
+}
+
+func (a animValue) color() color.Color {
+	if a[3] <= 0 {
+		return color.NRGBA{}
+	}
+	c := func(f float64) uint8 { return uint8(math.Round(clamp(f, 0, 255))) }
+	alpha := clamp(a[3], 0, 255)
+	return color.NRGBA{c(a[0] * 255 / alpha), c(a[1] * 255 / alpha), c(a[2] * 255 / alpha), c(alpha)}
+}
+
+func lerp(a, b animValue, t float64) animValue {
+	var v animValue
+	for i := range v {
+		v[i] = a[i] + (b[i]-a[i])*t
+	}
+	return v
+}
+
+// tween is a running tween of a view.
+type tween struct {
+	Tween
+	from, to animValue
+	elapsed  time.Duration
+	started  bool
+}
+
+// value returns the value of the tween and true if it has ended.
+func (t *tween) value() (animValue, bool) {
+	if t.Duration <= 0 {
+		return t.end(), true
+	}
+	p := float64(t.elapsed-t.Delay) / float64(t.Duration)
+	i := math.Floor(p)
+	if t.Repeat >= 0 && i > float64(t.Repeat) {
+		return t.end(), true
+	}
+	p -= i
+	if t.Yoyo && int(i)%2 == 1 {
+		p = 1 - p
+	}
+	if t.Easing != nil {
+		p = t.Easing.Ease(p)
+	}
+	return lerp(t.from, t.to, p), false
+}
+
+// end returns the value at the end of the last repetition.
+func (t *tween) end() animValue {
+	if t.Yoyo && t.Repeat%2 == 1 {
+		return t.from
+	}
+	return t.to
+}
+
+// Animate starts the tween, replacing the running tween of the same property.
+func (v *View) Animate(t Tween) {
+	tw := &tween{Tween: t}
+	if t.Property.isColor() {
+		tw.to = colorValue(t.ToColor)
+	} else {
+		tw.to = animValue{t.To}
+	}
+	v.StopAnimation(t.Property)
+	v.tweens = append(v.tweens, tw)
+}
+
+// StopAnimation stops the running tween of the property, leaving it at its current value.
+func (v *View) StopAnimation(prop FlexProperty) {
+	tweens := v.tweens[:0:0]
+	for _, t := range v.tweens {
+		if t.Property != prop && prop != PropertyAll {
+			tweens = append(tweens, t)
+		}
+	}
+	v.tweens = tweens
+}
+
+// IsAnimating returns true if a tween of the property is running,
+// or any tween if the property is PropertyAll.
+func (v *View) IsAnimating(prop FlexProperty) bool {
+	return v.runningTween(prop) != nil
+}
+
+func (v *View) runningTween(prop FlexProperty) *tween {
+	for _, t := range v.tweens {
+		if t.Property == prop || prop == PropertyAll {
+			return t
+		}
+	}
+	return nil
+}
+
+// tick returns the time of a tick of the game.
+func tick() time.Duration {
+	tps := ebiten.TPS()
+	if tps <= 0 {
+		tps = ebiten.DefaultTPS
+	}
+	return time.Second / time.Duration(tps)
+}
+
+// updateAnimations advances the running tweens and keyframe animations
+// of the view and its descendants by a tick.
+func (v *View) updateAnimations() {
+	v.updateTweens()
+	v.updateKeyframeAnimations()
+	for _, c := range v.children {
+		c.updateAnimations()
+	}
+}
+
+// updateTweens advances the running tweens of the view by a tick.
+func (v *View) updateTweens() {
+	if len(v.tweens) == 0 {
+		return
+	}
+	dt := tick()
+	var ended []*tween
+	for _, t := range append([]*tween(nil), v.tweens...) {
+		t.elapsed += dt
+		if t.elapsed < t.Delay {
+			continue
+		}
+		if !t.started {
+			t.started = true
+			t.from = v.propertyValue(t.Property)
+			if t.Property.isColor() && t.FromColor != nil {
+				t.from = colorValue(t.FromColor)
+			} else if !t.Property.isColor() && t.From != nil {
+				t.from = animValue{*t.From}
+			}
+		}
+		val, done := t.value()
+		v.setPropertyValue(t.Property, val)
+		if done {
+			ended = append(ended, t)
+		}
+	}
+	for _, t := range ended {
+		if v.runningTween(t.Property) == t {
+			v.StopAnimation(t.Property)
+		}
+	}
+	for _, t := range ended {
+		if t.OnComplete != nil {
+			t.OnComplete(v)
+		}
+	}
+}
+
+// transition starts the transition of the property to the value set by a setter.
+// It returns false if the view has no transition of the property,
+// which the setter sets then, stopping the running tween of the property.
+func (v *View) transition(prop FlexProperty, to animValue) bool {
+	var tr *Transition
+	for i := range v.Attrs.Transitions {
+		if p := v.Attrs.Transitions[i].Property; p == prop || p == PropertyAll {
+			tr = &v.Attrs.Transitions[i]
+		}
+	}
+	running := v.runningTween(prop)
+	if tr == nil || (tr.Duration <= 0 && tr.Delay <= 0) {
+		if running != nil {
+			v.StopAnimation(prop)
+		}
+		return false
+	}
+	if running != nil && running.to == to {
+		return true
+	}
+	if running == nil && v.propertyValue(prop) == to {
+		// The used value doesn't change, but a length given in percent or
+		// with calc is replaced with the pixels of the setter.
+		v.setPropertyValue(prop, to)
+		return true
+	}
+	easing := tr.Easing
+	if easing == nil {
+		easing = Ease
+	}
+	v.StopAnimation(prop)
+	v.tweens = append(v.tweens, &tween{
+		Tween: Tween{Property: prop, Duration: tr.Duration, Delay: tr.Delay, Easing: easing},
+		to:    to,
+	})
+	return true
+}
+
+// propertyValue returns the current value of the property. The position, size
+// and margins which are not given in pixels are taken from the layout.
+func (v *View) propertyValue(prop FlexProperty) animValue {
+	a := &v.Attrs
+	switch prop {
+	case PropertyLeft:
+		if a.Left.Unit == LengthPx {
+			return animValue{a.Left.Value}
+		}
+		if !v.hasParent {
+			return animValue{float64(v.frame.Min.X)}
+		}
+		if v.IsAbsolute() && v.used.Left == nil {
+			// The view is placed by its right inset or at its static position.
+			return animValue{float64(v.frame.Min.X - v.parent.frame.Min.X - v.used.MarginLeft)}
+		}
+		return animValue{float64(v.relativeOffset().X)}
+	case PropertyTop:
+		if a.Top.Unit == LengthPx {
+			return animValue{a.Top.Value}
+		}
+		if !v.hasParent {
+			return animValue{float64(v.frame.Min.Y)}
+		}
+		if v.IsAbsolute() && v.used.Top == nil {
+			// The view is placed by its bottom inset or at its static position.
+			return animValue{float64(v.frame.Min.Y - v.parent.frame.Min.Y - v.used.MarginTop)}
+		}
+		return animValue{float64(v.relativeOffset().Y)}
+	case PropertyWidth:
+		if a.Width.Unit == LengthPx && a.Width.Value != 0 {
+			return animValue{a.Width.Value}
+		}
+		return animValue{float64(v.frame.Dx())}
+	case PropertyHeight:
+		if a.Height.Unit == LengthPx && a.Height.Value != 0 {
+			return animValue{a.Height.Value}
+		}
+		return animValue{float64(v.frame.Dy())}
+	case PropertyMarginLeft:
+		return animValue{usedPx(a.MarginLeft, v.used.MarginLeft)}
+	case PropertyMarginTop:
+		return animValue{usedPx(a.MarginTop, v.used.MarginTop)}
+	case PropertyMarginRight:
+		return animValue{usedPx(a.MarginRight, v.used.MarginRight)}
+	case PropertyMarginBottom:
+		return animValue{usedPx(a.MarginBottom, v.used.MarginBottom)}
+	case PropertyOpacity:
+		if a.Opacity == nil {
+			return animValue{1}
+		}
+		return animValue{*a.Opacity}
+	case PropertyScale:
+		if a.Scale == nil {
+			return animValue{1}
+		}
+		return animValue{*a.Scale}
+	case PropertyColor:
+		if a.Color == nil {
+			// The text color is animated from the inherited color.
+			return colorValue(v.textStyle().color)
+		}
+		return colorValue(a.Color)
+	case PropertyBackgroundColor:
+		return colorValue(a.BackgroundColor)
+	case PropertyBorderColor:
+		return colorValue(a.BorderColor)
+	}
+	return animValue{}
+}
+
+// usedPx returns the length in pixels if it is given in pixels,
+// or the used value otherwise.
+func usedPx(l Length, used int) float64 {
+	if l.Unit == LengthPx {
+		return l.Value
+	}
+	return float64(used)
+}
+
+// setPropertyValue sets the value of the property without a transition.
+// The position, size and margins are set in pixels, replacing their lengths.
+func (v *View) setPropertyValue(prop FlexProperty, val animValue) {
+	a := &v.Attrs
+	setPx := func(l *Length) {
+		if px := Px(math.Round(val[0])); *l != px {
+			*l = px
+			v.Layout()
+		}
+	}
+	switch prop {
+	case PropertyLeft:
+		setPx(&a.Left)
+	case PropertyTop:
+		setPx(&a.Top)
+	case PropertyWidth:
+		setPx(&a.Width)
+	case PropertyHeight:
+		setPx(&a.Height)
+	case PropertyMarginLeft:
+		setPx(&a.MarginLeft)
+	case PropertyMarginTop:
+		setPx(&a.MarginTop)
+	case PropertyMarginRight:
+		setPx(&a.MarginRight)
+	case PropertyMarginBottom:
+		setPx(&a.MarginBottom)
+	case PropertyOpacity:
+		o := val[0]
+		a.Opacity = &o
+		v.invalidateComposite()
+	case PropertyScale:
+		s := val[0]
+		a.Scale = &s
+		v.invalidateComposite()
+	case PropertyColor:
+		a.Color = val.color()
+		v.invalidateTree()
+	case PropertyBackgroundColor:
+		a.BackgroundColor = val.color()
+		v.Invalidate()
+	case PropertyBorderColor:
+		a.BorderColor = val.color()
+		v.Invalidate()
+	}
+}
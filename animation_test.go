@@ -0,0 +1,241 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func updateTimes(v *View, n int) {
+	for i := 0; i < n; i++ {
+		v.Update()
+	}
+}
+
+func TestTween(t *testing.T) {
+	child := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Position: PositionAbsolute}}
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100)}}
+	root.AddChild(child)
+	root.Update()
+
+	completed := 0
+	child.Animate(Tween{
+		Property:   PropertyLeft,
+		To:         100,
+		Duration:   10 * tick(),
+		OnComplete: func(v *View) { completed++ },
+	})
+	require.True(t, child.IsAnimating(PropertyLeft))
+	updateTimes(root, 5)
+	require.Equal(t, Px(50), child.Attrs.Left)
+	require.Equal(t, image.Rect(50, 0, 60, 10), child.frame)
+	updateTimes(root, 5)
+	require.Equal(t, Px(100), child.Attrs.Left)
+	require.False(t, child.IsAnimating(PropertyAll))
+	require.Equal(t, 1, completed)
+	updateTimes(root, 5)
+	require.Equal(t, 1, completed)
+
+	// The tween starts from From after the delay.
+	child.Animate(Tween{
+		Property: PropertyWidth,
+		From:     floatPtr(0),
+		To:       20,
+		Duration: 4 * tick(),
+		Delay:    2 * tick(),
+	})
+	updateTimes(root, 1)
+	require.Equal(t, Px(10), child.Attrs.Width)
+	updateTimes(root, 1)
+	require.Equal(t, Px(0), child.Attrs.Width)
+	updateTimes(root, 3)
+	require.Equal(t, Px(15), child.Attrs.Width)
+	require.Equal(t, image.Rect(100, 0, 115, 10), child.frame)
+
+	// Stopped tweens leave the property at its current value.
+	child.StopAnimation(PropertyWidth)
+	updateTimes(root, 5)
+	require.Equal(t, Px(15), child.Attrs.Width)
+}
+
+func TestTweenLengths(t *testing.T) {
+	child := &View{Attrs: ViewAttrs{
+		Height: Px(10),
+		Width:  Pct(50), MarginLeft: Em(1),
+	}}
+	pct := &View{Attrs: ViewAttrs{
+		WidthInPct:  25,
+		Height:      Px(10),
+		Position:    PositionAbsolute,
+		Right:       Px(0),
+		Transitions: []Transition{{Property: PropertyAll, Duration: 2 * tick(), Easing: EaseLinear}},
+	}}
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(child, pct)
+	root.Update()
+	require.Equal(t, image.Rect(16, 0, 116, 10), child.frame)
+	require.Equal(t, image.Rect(150, 0, 200, 10), pct.frame)
+
+	// The tweens start from the laid out values and replace the lengths.
+	child.Animate(Tween{Property: PropertyWidth, To: 200, Duration: 4 * tick()})
+	child.Animate(Tween{Property: PropertyMarginLeft, To: 0, Duration: 2 * tick()})
+	updateTimes(root, 1)
+	assert.Equal(t, image.Rect(8, 0, 133, 10), child.frame)
+	updateTimes(root, 1)
+	assert.Equal(t, image.Rect(0, 0, 150, 10), child.frame)
+	assert.Equal(t, Px(150), child.Attrs.Width)
+	assert.Equal(t, Px(0), child.Attrs.MarginLeft)
+
+	pct.SetWidth(100)
+	pct.SetLeft(0)
+	updateTimes(root, 1)
+	assert.Equal(t, image.Rect(75, 0, 150, 10), pct.frame)
+	updateTimes(root, 1)
+	assert.Equal(t, image.Rect(0, 0, 100, 10), pct.frame)
+	assert.Equal(t, Px(100), pct.Attrs.Width)
+}
+
+func TestTweenRepeat(t *testing.T) {
+	v := &View{}
+	v.Animate(Tween{Property: PropertyOpacity, To: 0, Duration: 4 * tick(), Repeat: 2, Yoyo: true})
+	var opacities []float64
+	for i := 0; i < 14; i++ {
+		v.Update()
+		opacities = append(opacities, *v.Attrs.Opacity)
+	}
+	assert.InDeltaSlice(t, []float64{
+		0.75, 0.5, 0.25, 0,
+		0.25, 0.5, 0.75, 1,
+		0.75, 0.5, 0.25, 0,
+		0, 0,
+	}, opacities, 1e-9)
+	require.False(t, v.IsAnimating(PropertyOpacity))
+
+	// Tweens with a negative repeat play forever.
+	v.Animate(Tween{Property: PropertyScale, From: floatPtr(1), To: 2, Duration: 2 * tick(), Repeat: -1})
+	updateTimes(v, 101)
+	require.Equal(t, 1.5, *v.Attrs.Scale)
+	require.True(t, v.IsAnimating(PropertyScale))
+}
+
+func TestTweenColor(t *testing.T) {
+	v := &View{Attrs: ViewAttrs{BackgroundColor: color.NRGBA{0xff, 0, 0, 0xff}}}
+	v.Animate(Tween{Property: PropertyBackgroundColor, ToColor: color.NRGBA{0, 0, 0xff, 0x7f}, Duration: 2 * tick()})
+	v.Update()
+	require.Equal(t, color.NRGBA{0xaa, 0, 0x55, 0xbf}, v.Attrs.BackgroundColor)
+	v.Update()
+	require.Equal(t, color.NRGBA{0, 0, 0xff, 0x7f}, v.Attrs.BackgroundColor)
+
+	// The text color is animated from the inherited color.
+	parent := &View{Attrs: ViewAttrs{Color: color.NRGBA{0, 0xff, 0, 0xff}}}
+	parent.AddChild(v)
+	v.Animate(Tween{Property: PropertyColor, ToColor: color.NRGBA{0, 0, 0, 0xff}, Duration: 2 * tick()})
+	parent.Update()
+	require.Equal(t, color.NRGBA{0, 0x80, 0, 0xff}, v.Attrs.Color)
+}
+
+func TestTransition(t *testing.T) {
+	child := &View{Attrs: ViewAttrs{
+		Width:  Px(10),
+		Height: Px(10),
+		Transitions: []Transition{
+			{Property: PropertyLeft, Duration: 4 * tick(), Easing: EaseLinear},
+			{Property: PropertyOpacity, Duration: 2 * tick(), Delay: 2 * tick(), Easing: EaseLinear},
+		},
+	}}
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100)}}
+	root.AddChild(child)
+	root.Update()
+
+	child.SetLeft(40)
+	require.Equal(t, Auto, child.Attrs.Left)
+	updateTimes(root, 2)
+	require.Equal(t, Px(20), child.Attrs.Left)
+	// Setting the same value again doesn't restart the transition.
+	child.SetLeft(40)
+	updateTimes(root, 1)
+	require.Equal(t, Px(30), child.Attrs.Left)
+	// Setting another value transitions from the current value.
+	child.SetLeft(10)
+	updateTimes(root, 2)
+	require.Equal(t, Px(20), child.Attrs.Left)
+	updateTimes(root, 2)
+	require.Equal(t, Px(10), child.Attrs.Left)
+	require.False(t, child.IsAnimating(PropertyLeft))
+
+	child.SetOpacity(0)
+	updateTimes(root, 1)
+	require.Nil(t, child.Attrs.Opacity)
+	updateTimes(root, 1)
+	require.Equal(t, 1.0, *child.Attrs.Opacity)
+	updateTimes(root, 1)
+	require.Equal(t, 0.5, *child.Attrs.Opacity)
+	updateTimes(root, 1)
+	require.Equal(t, 0.0, *child.Attrs.Opacity)
+
+	// Properties without a transition are set at once, stopping their tweens.
+	child.Animate(Tween{Property: PropertyWidth, To: 100, Duration: 4 * tick()})
+	root.Update()
+	child.SetWidth(50)
+	require.Equal(t, Px(50), child.Attrs.Width)
+	require.False(t, child.IsAnimating(PropertyWidth))
+
+	// All properties transition with PropertyAll.
+	child.SetTransitions(Transition{Property: PropertyAll, Duration: 2 * tick(), Easing: EaseLinear})
+	child.SetBorderColor(color.NRGBA{0, 0, 0xff, 0xff})
+	child.SetMarginTop(10)
+	root.Update()
+	require.Equal(t, color.NRGBA{0, 0, 0xff, 0x80}, child.Attrs.BorderColor)
+	require.Equal(t, Px(5), child.Attrs.MarginTop)
+
+	// Lengths in percent are replaced with the pixels of the setter, at once if
+	// they are used at the same size and at the end of the transition otherwise.
+	child.SetWidth(50)
+	child.Attrs.Width = Pct(25)
+	root.Update()
+	child.SetWidth(50)
+	require.Equal(t, Px(50), child.Attrs.Width)
+	require.False(t, child.IsAnimating(PropertyWidth))
+	child.Attrs.Width = Pct(50)
+	root.Update()
+	child.SetWidth(50)
+	updateTimes(root, 1)
+	require.Equal(t, Px(75), child.Attrs.Width)
+	updateTimes(root, 1)
+	require.Equal(t, Px(50), child.Attrs.Width)
+}
+
+func TestEasing(t *testing.T) {
+	assert.InDelta(t, 0.3, EaseLinear.Ease(0.3), 1e-9)
+	assert.InDelta(t, 0.5, EaseInOut.Ease(0.5), 1e-6)
+	assert.Less(t, EaseIn.Ease(0.5), 0.5)
+	assert.Greater(t, EaseOut.Ease(0.5), 0.5)
+	assert.InDelta(t, 0.8024, Ease.Ease(0.5), 1e-3)
+	assert.Equal(t, 1.0, Ease.Ease(1))
+	assert.Equal(t, 0.25, EasingFunc(func(t float64) float64 { return t * t }).Ease(0.5))
+}
+
+func TestScaleTransform(t *testing.T) {
+	child := &View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20), Scale: floatPtr(0.5)}}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), BackgroundColor: color.White}}
+	root.AddChild(child)
+	root.Update()
+
+	_, ok := root.transform()
+	require.False(t, ok)
+	m, ok := child.transform()
+	require.True(t, ok)
+	x, y := m.Apply(0, 0)
+	require.Equal(t, []float64{5, 5}, []float64{x, y})
+
+	// Descendants are scaled around the centers of their scaled ancestors.
+	root.SetScale(2)
+	m, _ = child.transform()
+	x, y = m.Apply(0, 0)
+	require.Equal(t, []float64{-40, -40}, []float64{x, y})
+	root.Draw(ebiten.NewImage(100, 100))
+}
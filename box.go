@@ -0,0 +1,272 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Shadow is the 'box-shadow' property, a shadow drawn under the view.
+type Shadow struct {
+	OffsetX float64
+	OffsetY float64
+	// Blur is the width of the edge the shadow fades out over.
+	Blur float64
+	// Spread expands the shadow beyond the frame of the view, or shrinks it if negative.
+	Spread float64
+	Color  color.Color
+}
+
+// maxShadowSteps is the number of layers a blurred shadow is drawn in at most.
+const maxShadowSteps = 8
+
+var (
+	whiteImage     *ebiten.Image
+	whiteImageOnce sync.Once
+)
+
+// whitePixel returns a white image of a pixel to fill paths with.
+func whitePixel() *ebiten.Image {
+	whiteImageOnce.Do(func() {
+		img := ebiten.NewImage(3, 3)
+		img.Fill(color.White)
+		whiteImage = img.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+	})
+	return whiteImage
+}
+
+// hasBox returns true if the view has a background, a border or a shadow to draw.
+func (v *View) hasBox() bool {
+	a := &v.Attrs
+	return a.BackgroundColor != nil || a.BackgroundImage != "" || a.BorderImage != nil ||
+		(a.BorderWidth > 0 && a.BorderColor != nil) || (a.BoxShadow != nil && a.BoxShadow.Color != nil)
+}
+
+// opacity returns the opacity the view is drawn in,
+// which is its opacity multiplied by the opacities of its ancestors.
+// The opacity of a cached view is applied when its cache is drawn, not on it.
+func (v *View) opacity() float64 {
+	o := 1.0
+	for p := v; p != nil && !p.cache.rendering; p = p.parent {
+		if p.Attrs.Opacity != nil {
+			o *= clamp(*p.Attrs.Opacity, 0, 1)
+		}
+	}
+	return o
+}
+
+// fade returns the color with its alpha multiplied by alpha.
+func fade(clr color.Color, alpha float64) color.Color {
+	if alpha >= 1 {
+		return clr
+	}
+	r, g, b, a := clr.RGBA()
+	return color.RGBA64{
+		uint16(float64(r) * alpha), uint16(float64(g) * alpha),
+		uint16(float64(b) * alpha), uint16(float64(a) * alpha),
+	}
+}
+
+// drawContent draws the box of the view, then its text or its handler, in its opacity.
+// Handlers of translucent views draw on an offscreen image of their frame,
+// which is drawn on the screen in the opacity. Transformed views draw everything
+// on an offscreen image, which is drawn on the screen with their matrix.
+func (v *View) drawContent(screen *ebiten.Image, frame image.Rectangle) {
+	alpha := v.opacity()
+	if alpha <= 0 {
+		return
+	}
+	m, ok := v.transform()
+	if !ok || screen == nil {
+		v.paint(screen, frame, alpha)
+		return
+	}
+	bounds := v.paintBounds(frame)
+	if bounds.Empty() {
+		return
+	}
+	layer := v.offscreen(bounds)
+	v.paint(layer, frame, 1)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(bounds.Min.X), float64(bounds.Min.Y))
+	op.GeoM.Concat(m)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(layer, op)
+}
+
+// paint draws the box of the view, then its text or its handler, in the opacity.
+func (v *View) paint(screen *ebiten.Image, frame image.Rectangle, alpha float64) {
+	v.drawBox(screen, alpha)
+	if v.hasText() {
+		v.drawText(screen, alpha)
+		return
+	}
+	if v.Handler.Draw == nil {
+		return
+	}
+	if alpha >= 1 || screen == nil || frame.Empty() {
+		v.Handler.Draw(screen, frame, v)
+		return
+	}
+	layer := v.offscreen(frame)
+	v.Handler.Draw(layer, frame, v)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(frame.Min.X), float64(frame.Min.Y))
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	screen.DrawImage(layer, op)
+}
+
+// paintBounds returns the bounds of the frame with the shadow of the view.
+func (v *View) paintBounds(frame image.Rectangle) image.Rectangle {
+	s := v.Attrs.BoxShadow
+	if s == nil || s.Color == nil {
+		return frame
+	}
+	ext := int(math.Ceil((math.Max(0, s.Spread) + s.Blur) * GlobalScale))
+	shadow := frame.Inset(-ext).Add(image.Pt(int(math.Round(s.OffsetX*GlobalScale)), int(math.Round(s.OffsetY*GlobalScale))))
+	return frame.Union(shadow)
+}
+
+// offscreen returns the cleared offscreen image of the view with the bounds.
+func (v *View) offscreen(bounds image.Rectangle) *ebiten.Image {
+	if v.layer != nil && v.layer.Bounds() != bounds {
+		v.layer.Dispose()
+		v.layer = nil
+	}
+	if v.layer == nil {
+		v.layer = ebiten.NewImageWithOptions(bounds, nil)
+	}
+	v.layer.Clear()
+	return v.layer
+}
+
+// drawBox draws the shadow, the background and the border of the view.
+// The border is drawn inside the frame and doesn't take space in layout.
+// A border image is drawn in place of the border.
+func (v *View) drawBox(screen *ebiten.Image, alpha float64) {
+	if screen == nil || !v.hasBox() {
+		return
+	}
+	a := &v.Attrs
+	frame := scaleFrame(v.frame)
+	x, y := float32(frame.Min.X), float32(frame.Min.Y)
+	w, h := float32(frame.Dx()), float32(frame.Dy())
+	r := float32(math.Min(a.BorderRadius*GlobalScale, math.Min(float64(w), float64(h))/2))
+	antialias := r > 0
+
+	if s := a.BoxShadow; s != nil && s.Color != nil {
+		drawShadow(screen, s, x, y, w, h, r, alpha)
+	}
+	if a.BackgroundColor != nil || a.BackgroundImage != "" {
+		p := &vector.Path{}
+		appendRoundedRect(p, x, y, w, h, r)
+		if a.BackgroundColor != nil {
+			fillPath(screen, p, fade(a.BackgroundColor, alpha), ebiten.NonZero, antialias)
+		}
+		if img := lookupImage(a.BackgroundImage); img != nil {
+			fillPathWithImage(screen, p, img, frame, alpha, antialias)
+		}
+	}
+	if a.BorderImage != nil {
+		v.drawBorderImage(screen, alpha)
+		return
+	}
+	if bw := float32(float64(a.BorderWidth) * GlobalScale); bw > 0 && a.BorderColor != nil {
+		p := &vector.Path{}
+		appendRoundedRect(p, x, y, w, h, r)
+		if w > 2*bw && h > 2*bw {
+			appendRoundedRect(p, x+bw, y+bw, w-2*bw, h-2*bw, float32(math.Max(0, float64(r-bw))))
+		}
+		fillPath(screen, p, fade(a.BorderColor, alpha), ebiten.EvenOdd, antialias)
+	}
+}
+
+// drawShadow draws the shadow of the box. A blurred shadow is drawn in layers
+// from the outer edge of the blur to the inner edge, so it fades out to the outside.
+func drawShadow(screen *ebiten.Image, s *Shadow, x, y, w, h, r float32, alpha float64) {
+	spread := float32(s.Spread * GlobalScale)
+	x += float32(s.OffsetX*GlobalScale) - spread
+	y += float32(s.OffsetY*GlobalScale) - spread
+	w, h = w+2*spread, h+2*spread
+	if r > 0 {
+		r = float32(math.Max(0, float64(r+spread)))
+	}
+	blur := float32(s.Blur * GlobalScale)
+	steps := 1
+	if blur > 0 {
+		steps = int(math.Min(maxShadowSteps, math.Ceil(float64(blur))))
+	}
+	clr := fade(s.Color, alpha/float64(steps))
+	for i := 0; i < steps; i++ {
+		d := blur/2 - blur*(float32(i)+0.5)/float32(steps)
+		if w+2*d <= 0 || h+2*d <= 0 {
+			break
+		}
+		p := &vector.Path{}
+		appendRoundedRect(p, x-d, y-d, w+2*d, h+2*d, float32(math.Max(0, float64(r+d))))
+		fillPath(screen, p, clr, ebiten.NonZero, true)
+	}
+}
+
+// appendRoundedRect appends a rectangle with corners rounded in the radius to the path.
+func appendRoundedRect(p *vector.Path, x, y, w, h, r float32) {
+	if r <= 0 {
+		p.MoveTo(x, y)
+		p.LineTo(x+w, y)
+		p.LineTo(x+w, y+h)
+		p.LineTo(x, y+h)
+		p.Close()
+		return
+	}
+	r = float32(math.Min(float64(r), math.Min(float64(w), float64(h))/2))
+	p.MoveTo(x+r, y)
+	p.LineTo(x+w-r, y)
+	p.ArcTo(x+w, y, x+w, y+r, r)
+	p.LineTo(x+w, y+h-r)
+	p.ArcTo(x+w, y+h, x+w-r, y+h, r)
+	p.LineTo(x+r, y+h)
+	p.ArcTo(x, y+h, x, y+h-r, r)
+	p.LineTo(x, y+r)
+	p.ArcTo(x, y, x+r, y, r)
+	p.Close()
+}
+
+// fillPath fills the path in the color.
+func fillPath(dst *ebiten.Image, p *vector.Path, clr color.Color, rule ebiten.FillRule, antialias bool) {
+	vs, is := p.AppendVerticesAndIndicesForFilling(nil, nil)
+	r, g, b, a := clr.RGBA()
+	for i := range vs {
+		vs[i].SrcX, vs[i].SrcY = 1, 1
+		vs[i].ColorR = float32(r) / 0xffff
+		vs[i].ColorG = float32(g) / 0xffff
+		vs[i].ColorB = float32(b) / 0xffff
+		vs[i].ColorA = float32(a) / 0xffff
+	}
+	op := &ebiten.DrawTrianglesOptions{}
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	op.FillRule = rule
+	op.AntiAlias = antialias
+	dst.DrawTriangles(vs, is, whitePixel(), op)
+}
+
+// fillPathWithImage fills the path with the image stretched over the rectangle.
+func fillPathWithImage(dst *ebiten.Image, p *vector.Path, img *ebiten.Image, rect image.Rectangle, alpha float64, antialias bool) {
+	vs, is := p.AppendVerticesAndIndicesForFilling(nil, nil)
+	b := img.Bounds()
+	sx := float32(b.Dx()) / float32(rect.Dx())
+	sy := float32(b.Dy()) / float32(rect.Dy())
+	for i := range vs {
+		vs[i].SrcX = float32(b.Min.X) + (vs[i].DstX-float32(rect.Min.X))*sx
+		vs[i].SrcY = float32(b.Min.Y) + (vs[i].DstY-float32(rect.Min.Y))*sy
+		vs[i].ColorR, vs[i].ColorG, vs[i].ColorB, vs[i].ColorA = 1, 1, 1, float32(alpha)
+	}
+	op := &ebiten.DrawTrianglesOptions{}
+	op.FillRule = ebiten.NonZero
+	op.AntiAlias = antialias
+	dst.DrawTriangles(vs, is, img, op)
+}
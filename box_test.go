@@ -0,0 +1,107 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpacity(t *testing.T) {
+	child := &View{Attrs: ViewAttrs{Opacity: floatPtr(0.5)}}
+	parent := &View{}
+	root := &View{Attrs: ViewAttrs{Opacity: floatPtr(0.5)}}
+	root.AddChild(parent.AddChild(child))
+
+	assert.Equal(t, 0.25, child.opacity())
+	assert.Equal(t, 0.5, parent.opacity())
+	root.SetOpacity(2)
+	assert.Equal(t, 0.5, child.opacity())
+
+	assert.Equal(t, color.Color(color.RGBA{0xff, 0, 0, 0xff}), fade(color.RGBA{0xff, 0, 0, 0xff}, 1))
+	assert.Equal(t, color.Color(color.RGBA64{0x7fff, 0, 0, 0x7fff}), fade(color.RGBA{0xff, 0, 0, 0xff}, 0.5))
+}
+
+func TestDrawBox(t *testing.T) {
+	RegisterImage("panel", ebiten.NewImage(4, 4))
+	defer delete(images, "panel")
+
+	var drawn []*ebiten.Image
+	handler := ViewHandler{Draw: func(screen *ebiten.Image, frame image.Rectangle, v *View) {
+		drawn = append(drawn, screen)
+	}}
+	box := &View{
+		Attrs: ViewAttrs{
+			Width:           Px(20),
+			Height:          Px(20),
+			BackgroundColor: color.White,
+			BackgroundImage: "panel",
+			BorderWidth:     2,
+			BorderColor:     color.Black,
+			BorderRadius:    4,
+			BoxShadow:       &Shadow{OffsetX: 2, OffsetY: 2, Blur: 4, Color: color.Black},
+		},
+		Handler: handler,
+	}
+	plain := &View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20), BackgroundColor: color.White}}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(box, plain)
+	require.True(t, box.hasBox())
+	require.True(t, root.shouldDrawChild(plain))
+
+	screen := ebiten.NewImage(100, 100)
+	root.Update()
+	root.Draw(screen)
+	require.Equal(t, []*ebiten.Image{screen}, drawn)
+	require.Nil(t, box.layer)
+
+	// The handler of a translucent view draws on an offscreen image of its frame.
+	drawn = nil
+	box.SetOpacity(0.5)
+	root.Draw(screen)
+	require.Len(t, drawn, 1)
+	require.Equal(t, box.frame, drawn[0].Bounds())
+	require.Equal(t, box.layer, drawn[0])
+
+	// Transparent views are not drawn.
+	drawn = nil
+	box.SetOpacity(0)
+	root.Draw(screen)
+	require.Empty(t, drawn)
+}
+
+func TestRoundedRect(t *testing.T) {
+	tests := []struct {
+		name   string
+		radius float32
+		corner bool
+	}{
+		{name: "square", radius: 0, corner: true},
+		{name: "rounded", radius: 5, corner: false},
+		{name: "radius larger than the rect", radius: 50, corner: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &vector.Path{}
+			appendRoundedRect(p, 10, 10, 20, 30, tt.radius)
+			vs, _ := p.AppendVerticesAndIndicesForFilling(nil, nil)
+			corner := false
+			for _, v := range vs {
+				require.True(t, v.DstX >= 10-0.01 && v.DstX <= 30+0.01, "x: %f", v.DstX)
+				require.True(t, v.DstY >= 10-0.01 && v.DstY <= 40+0.01, "y: %f", v.DstY)
+				if v.DstX == 10 && v.DstY == 10 {
+					corner = true
+				}
+			}
+			require.Equal(t, tt.corner, corner)
+		})
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,165 @@
+package furex
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// renderCache is the offscreen image a view with the cache attribute and its
+// descendants are drawn on, which is drawn on the screen until it is invalidated.
+type renderCache struct {
+	image *ebiten.Image
+	valid bool
+	// origin is the position of the frame of the view when the cache was drawn.
+	origin image.Point
+	// layout is the frames of the view and its descendants relative to origin
+	// when the cache was drawn. The cache is drawn again if they change.
+	layout, scratch []image.Rectangle
+	// rendering is true while the view is drawn on its cache, which is drawn
+	// without the opacity, the transform and the clip of the view and its ancestors.
+	rendering bool
+}
+
+// Invalidate marks the render caches of the view and its ancestors to be drawn again.
+// Handlers of views in a cached subtree call it when what they draw changes.
+func (v *View) Invalidate() {
+	for p := v; p != nil; p = p.parent {
+		p.cache.valid = false
+	}
+}
+
+// invalidateTree invalidates the render caches of the view, its ancestors and its
+// descendants, for a change of a property the descendants inherit.
+func (v *View) invalidateTree() {
+	v.Invalidate()
+	v.invalidateDescendants()
+}
+
+func (v *View) invalidateDescendants() {
+	for _, c := range v.children {
+		c.cache.valid = false
+		c.invalidateDescendants()
+	}
+}
+
+// invalidateComposite invalidates the render caches of the ancestors of the view
+// for a change of its opacity or its transform, which its own cache is drawn with.
+func (v *View) invalidateComposite() {
+	if v.parent != nil {
+		v.parent.Invalidate()
+	}
+}
+
+// SetCache sets whether the view and its descendants are drawn on an offscreen image
+// once, which is drawn on the screen until the view is laid out or invalidated.
+func (v *View) SetCache(cache bool) {
+	v.Attrs.Cache = cache
+	if !cache {
+		v.disposeCache()
+	}
+}
+
+func (v *View) disposeCache() {
+	if v.cache.image != nil {
+		v.cache.image.Dispose()
+	}
+	v.cache = renderCache{}
+}
+
+// drawCached draws the view and its descendants from its render cache,
+// drawing them on the cache first if it is invalid.
+func (v *View) drawCached(screen *ebiten.Image) {
+	if screen == nil {
+		v.drawSubtree(screen)
+		return
+	}
+	alpha := v.opacity()
+	if alpha <= 0 {
+		return
+	}
+	c := &v.cache
+	c.scratch = v.appendLayout(c.scratch[:0], v.frame.Min)
+	if !c.valid || !equalRects(c.layout, c.scratch) {
+		c.layout, c.scratch = c.scratch, c.layout
+		c.origin = v.frame.Min
+		c.rendering = true
+		bounds := v.subtreeBounds()
+		if c.image != nil && c.image.Bounds() != bounds {
+			c.image.Dispose()
+			c.image = nil
+		}
+		if !bounds.Empty() {
+			if c.image == nil {
+				c.image = ebiten.NewImageWithOptions(bounds, nil)
+			}
+			c.image.Clear()
+			v.drawSubtree(c.image)
+		}
+		c.rendering = false
+		c.valid = true
+	}
+	if c.image == nil {
+		return
+	}
+	// The cache moves with the view without being drawn again.
+	op := &ebiten.DrawImageOptions{}
+	b := c.image.Bounds()
+	op.GeoM.Translate(float64(b.Min.X)+float64(v.frame.Min.X-c.origin.X)*GlobalScale,
+		float64(b.Min.Y)+float64(v.frame.Min.Y-c.origin.Y)*GlobalScale)
+	if m, ok := v.transform(); ok {
+		op.GeoM.Concat(m)
+		op.Filter = ebiten.FilterLinear
+	}
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	screen.DrawImage(c.image, op)
+}
+
+// drawSubtree draws the view and its descendants.
+func (v *View) drawSubtree(screen *ebiten.Image) {
+	if v.hasParent {
+		v.parent.drawChild(screen, v)
+	} else {
+		v.handleDrawRoot(screen, scaleFrame(v.frame))
+	}
+	if !v.Attrs.Hidden && v.Attrs.Display != DisplayNone {
+		v.childrenDraw(screen)
+	}
+}
+
+// subtreeBounds returns the bounds the view and its descendants are drawn in.
+func (v *View) subtreeBounds() image.Rectangle {
+	r := v.screenRect(v.paintBounds(scaleFrame(v.frame)))
+	var children image.Rectangle
+	for _, c := range v.children {
+		if c.Attrs.Hidden || c.Attrs.Display == DisplayNone {
+			continue
+		}
+		children = children.Union(c.subtreeBounds())
+	}
+	if v.Attrs.Overflow != OverflowVisible {
+		children = children.Intersect(v.screenRect(scaleFrame(v.frame)))
+	}
+	return r.Union(children)
+}
+
+// appendLayout appends the frames of the view and its descendants relative to origin.
+func (v *View) appendLayout(dst []image.Rectangle, origin image.Point) []image.Rectangle {
+	dst = append(dst, v.frame.Sub(origin))
+	for _, c := range v.children {
+		dst = c.appendLayout(dst, origin)
+	}
+	return dst
+}
+
+func equalRects(a, b []image.Rectangle) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
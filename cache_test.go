@@ -0,0 +1,139 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	draws := 0
+	var frame image.Rectangle
+	child := &View{
+		Attrs: ViewAttrs{Width: Px(20), Height: Px(20)},
+		Handler: ViewHandler{Draw: func(screen *ebiten.Image, f image.Rectangle, v *View) {
+			draws++
+			frame = f
+		}},
+	}
+	cached := &View{Attrs: ViewAttrs{Width: Px(50), Height: Px(50), Cache: true}}
+	cached.AddChild(child)
+	parent := &View{Attrs: ViewAttrs{Width: Px(50), Height: Px(50), Position: PositionAbsolute, Left: Px(10), Top: Px(10)}}
+	parent.AddChild(cached)
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	root.AddChild(parent)
+	screen := ebiten.NewImage(100, 100)
+
+	root.Update()
+	root.Draw(screen)
+	require.Equal(t, 1, draws)
+	require.Equal(t, image.Rect(10, 10, 30, 30), frame)
+	require.NotNil(t, cached.cache.image)
+	require.Equal(t, image.Rect(10, 10, 60, 60), cached.cache.image.Bounds())
+
+	// The cache is drawn without drawing the subtree again.
+	root.Update()
+	root.Draw(screen)
+	require.Equal(t, 1, draws)
+
+	cached.Invalidate()
+	root.Draw(screen)
+	require.Equal(t, 2, draws)
+
+	// Invalidating a descendant invalidates the cache.
+	child.Invalidate()
+	root.Draw(screen)
+	require.Equal(t, 3, draws)
+
+	// The cache moves with the view.
+	parent.SetLeft(30)
+	root.Update()
+	root.Draw(screen)
+	assert.Equal(t, 3, draws)
+	assert.Equal(t, image.Rect(30, 10, 50, 30), child.frame)
+
+	// A change of the layout of the subtree draws it again.
+	child.SetWidth(30)
+	root.Update()
+	root.Draw(screen)
+	assert.Equal(t, 4, draws)
+	assert.Equal(t, image.Rect(30, 10, 60, 30), frame)
+
+	cached.SetBackgroundColor(color.White)
+	root.Draw(screen)
+	assert.Equal(t, 5, draws)
+
+	// The color is inherited by the descendants.
+	root.SetColor(color.Black)
+	root.Draw(screen)
+	assert.Equal(t, 6, draws)
+
+	// The opacity and the transform of the view are applied when the cache is drawn.
+	cached.SetOpacity(0.5)
+	cached.SetTransform(TransformFunc{Func: TransformRotate, X: 1})
+	root.Draw(screen)
+	assert.Equal(t, 6, draws)
+
+	cached.SetCache(false)
+	require.Nil(t, cached.cache.image)
+	root.Draw(screen)
+	root.Draw(screen)
+	assert.Equal(t, 8, draws)
+}
+
+func TestCacheDrawsWithoutOpacityAndTransform(t *testing.T) {
+	var opacity float64
+	var transformed bool
+	var clipped bool
+	child := &View{
+		Attrs: ViewAttrs{Width: Px(20), Height: Px(20)},
+		Handler: ViewHandler{Draw: func(screen *ebiten.Image, f image.Rectangle, v *View) {
+			opacity = v.opacity()
+			_, transformed = v.transform()
+			_, clipped = v.clipRect()
+		}},
+	}
+	opacity50 := 0.5
+	cached := &View{Attrs: ViewAttrs{
+		Width: Px(50), Height: Px(50), Cache: true, Opacity: &opacity50,
+		Transform: []TransformFunc{{Func: TransformScale, X: 2, Y: 2}},
+	}}
+	cached.AddChild(child)
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), Overflow: OverflowHidden, Opacity: &opacity50}}
+	root.AddChild(cached)
+
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+	assert.Equal(t, 1.0, opacity)
+	assert.False(t, transformed)
+	assert.False(t, clipped)
+
+	// The cache is drawn in the opacity and the transform of the view and its ancestors.
+	assert.Equal(t, 0.25, cached.opacity())
+	_, ok := cached.transform()
+	assert.True(t, ok)
+}
+
+func TestCacheRoot(t *testing.T) {
+	h := NewMockHandler()
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), Cache: true}}
+	root.AddChild(&View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20)}, Handler: h.ViewHandler})
+	screen := ebiten.NewImage(100, 100)
+
+	root.Update()
+	root.Draw(screen)
+	require.True(t, h.IsDrawn)
+
+	h.IsDrawn = false
+	root.Draw(screen)
+	require.False(t, h.IsDrawn)
+
+	root.AddChild(&View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20)}})
+	root.Update()
+	root.Draw(screen)
+	require.True(t, h.IsDrawn)
+}
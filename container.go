@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"sort"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -17,24 +18,263 @@ type containerEmbed struct {
 	// bounds is the bounds of the container
 	bounds   image.Rectangle
 	children []*View
+	// isDirty is true if the children of the view need to be laid out again.
 	isDirty  bool
 	touchIDs []ebiten.TouchID
+	// laidOut is the input of the last layout of the view.
+	laidOut layoutInput
 
 	calculatedWidth  int
 	calculatedHeight int
+
+	scroll     scrollState
+	textLayout textLayout
+	// layer is the offscreen image the handler of a translucent view draws on,
+	// or the whole scaled view.
+	layer *ebiten.Image
+	// cache is the render cache of a view with the cache attribute.
+	cache renderCache
+	// tweens are the running tweens of the view.
+	tweens []*tween
+	// keyframeAnimations are the running animations of ViewAttrs.Animations.
+	keyframeAnimations []*keyframeAnimation
+	// hitOrderCache is the cached paint order of the descendants for events,
+	// and hitOrderKeys the stacking keys of its views when it was built.
+	hitOrderCache []paintItem
+	hitOrderKeys  []stackingKey
+	// activated is the point of the screen the navigation activation button
+	// is held down on, held by the root view.
+	activated *image.Point
+}
+
+// orderedChildren returns the children in order-modified document order,
+// which is the order they are laid out, drawn and hit-tested in.
+// Children with the same order keep the order they were added in.
+func (ct *containerEmbed) orderedChildren() []*View {
+	ordered := true
+	for i := 1; i < len(ct.children); i++ {
+		if ct.children[i].Attrs.Order < ct.children[i-1].Attrs.Order {
+			ordered = false
+			break
+		}
+	}
+	if ordered {
+		return ct.children
+	}
+	children := make([]*View, len(ct.children))
+	copy(children, ct.children)
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].Attrs.Order < children[j].Attrs.Order
+	})
+	return children
+}
+
+// paintItem is a view in paint order. If scrollbars is true,
+// the item is the scrollbars of the view, which are drawn over its content.
+type paintItem struct {
+	view       *View
+	scrollbars bool
+}
+
+// paintOrder returns the descendants of the view in the order they are drawn.
+// The view and each descendant with a non-zero z-index form a stacking context,
+// whose descendants are drawn together: first the stacking contexts with a negative
+// z-index, then the other descendants in order-modified document order, then the
+// stacking contexts with a positive z-index. Stacking contexts with the same z-index
+// keep their order. Events are dispatched in the reverse order, so the topmost view
+// receives them first. If draw is true, descendants of hidden views are skipped
+// and the scrollbars of scroll containers are added after their content.
+func (v *View) paintOrder(draw bool) []paintItem {
+	var flow []paintItem
+	var contexts []*View
+	v.collectStacking(&flow, &contexts, draw)
+	sort.SliceStable(contexts, func(i, j int) bool {
+		return contexts[i].Attrs.ZIndex < contexts[j].Attrs.ZIndex
+	})
+	items := make([]paintItem, 0, len(flow)+len(contexts))
+	i := 0
+	for ; i < len(contexts) && contexts[i].Attrs.ZIndex < 0; i++ {
+		items = contexts[i].appendStackingContext(items, draw)
+	}
+	items = append(items, flow...)
+	for ; i < len(contexts); i++ {
+		items = contexts[i].appendStackingContext(items, draw)
+	}
+	if draw && v.hasScrollbars() {
+		items = append(items, paintItem{view: v, scrollbars: true})
+	}
+	return items
+}
+
+// stackingKey is what the position of a view in paint order depends on.
+type stackingKey struct {
+	order, zIndex int
+}
+
+// hitOrder returns the paint order of the descendants for events, which are
+// dispatched in the reverse order. It is cached until the children of the view
+// or of a descendant change, or the order or the z-index of a descendant is
+// changed, which is also the case when the attributes are written directly.
+func (v *View) hitOrder() []paintItem {
+	if v.hitOrderCache != nil && v.hitOrderValid() {
+		return v.hitOrderCache
+	}
+	v.hitOrderCache = v.paintOrder(false)
+	v.hitOrderKeys = v.hitOrderKeys[:0]
+	for _, item := range v.hitOrderCache {
+		v.hitOrderKeys = append(v.hitOrderKeys, item.view.stackingKey())
+	}
+	return v.hitOrderCache
+}
+
+// hitOrderValid returns true if the stacking keys of the views of the cached
+// hit-test order are unchanged.
+func (v *View) hitOrderValid() bool {
+	for i, item := range v.hitOrderCache {
+		if item.view.stackingKey() != v.hitOrderKeys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *View) stackingKey() stackingKey {
+	return stackingKey{order: v.Attrs.Order, zIndex: v.Attrs.ZIndex}
+}
+
+// invalidateHitOrder discards the cached hit-test order of the view and its ancestors.
+func (v *View) invalidateHitOrder() {
+	for p := v; p != nil; p = p.parent {
+		p.hitOrderCache = nil
+	}
+}
+
+// appendStackingContext appends the view and its descendants in paint order.
+// Views drawn from their render cache are drawn with their descendants.
+func (v *View) appendStackingContext(items []paintItem, draw bool) []paintItem {
+	items = append(items, paintItem{view: v})
+	if draw && v.Attrs.Cache {
+		return items
+	}
+	return append(items, v.paintOrder(draw)...)
+}
+
+// collectStacking appends the descendants of the view that belong to its stacking
+// context to flow and the stacking contexts nested in it to contexts.
+func (v *View) collectStacking(flow *[]paintItem, contexts *[]*View, draw bool) {
+	if draw && (v.Attrs.Hidden || v.Attrs.Display == DisplayNone) {
+		return
+	}
+	for _, c := range v.orderedChildren() {
+		if c.Attrs.ZIndex != 0 {
+			*contexts = append(*contexts, c)
+			continue
+		}
+		*flow = append(*flow, paintItem{view: c})
+		if draw && c.Attrs.Cache {
+			continue
+		}
+		c.collectStacking(flow, contexts, draw)
+		if draw && c.hasScrollbars() && !c.Attrs.Hidden && c.Attrs.Display != DisplayNone {
+			*flow = append(*flow, paintItem{view: c, scrollbars: true})
+		}
+	}
 }
 
-// processEvent processes touch and mouse events, it can only be called by root view
+// processEvent processes touch, mouse, key and gamepad events, it can only be called by root view
 func (ct *View) processEvent() {
 	ct.handleTouchEvents(&ct.frame)
 	ct.handleMouseEvents(&ct.frame)
+	ct.handleKeyEvents()
+	ct.handleGamepadEvents()
+}
+
+// viewAt returns the topmost view at (x, y), or the view itself if none of its descendants is there.
+func (ct *View) viewAt(x, y int) *View {
+	views := ct.hitOrder()
+	for i := len(views) - 1; i >= 0; i-- {
+		c := views[i].view
+		lx, ly := c.localPoint(x, y)
+		if c.Attrs.Display != DisplayNone && isInside(c.parent.childFrame(c), lx, ly) && c.isInsideClip(x, y) {
+			return c
+		}
+	}
+	return ct
 }
 
 // Draw draws it's children
 func (ct *View) childrenDraw(screen *ebiten.Image) {
-	for _, c := range ct.children {
-		ct.drawChild(screen, c)
+	for _, item := range ct.paintOrder(true) {
+		c := item.view
+		clipped, ok := c.clipScreen(screen)
+		if !ok {
+			continue
+		}
+		if item.scrollbars {
+			c.drawScrollbars(clipped)
+			continue
+		}
+		if c.Attrs.Cache {
+			c.drawCached(clipped)
+			continue
+		}
+		c.parent.drawChild(clipped, c)
+	}
+}
+
+// clipRect returns the rectangle the view is clipped to by its ancestors with
+// hidden or scroll overflow, in screen coordinates. It returns false if the view
+// is not clipped.
+func (v *View) clipRect() (image.Rectangle, bool) {
+	var clip image.Rectangle
+	clipped := false
+	for p := v.parent; p != nil; p = p.parent {
+		if p.Attrs.Overflow != OverflowVisible {
+			r := p.screenRect(scaleFrame(p.frame))
+			if clipped {
+				clip = clip.Intersect(r)
+			} else {
+				clip, clipped = r, true
+			}
+		}
+		if p.cache.rendering {
+			// Views drawn on their cache are clipped when the cache is drawn.
+			break
+		}
+	}
+	return clip, clipped
+}
+
+// isInsideClip returns true if (x, y) is not clipped out of the view
+// by its ancestors, in their transformed frames.
+func (v *View) isInsideClip(x, y int) bool {
+	for p := v.parent; p != nil; p = p.parent {
+		if p.Attrs.Overflow == OverflowVisible {
+			continue
+		}
+		r := scaleFrame(p.frame)
+		lx, ly := p.localPoint(x, y)
+		if r.Empty() || !isInside(&r, lx, ly) {
+			return false
+		}
 	}
+	return true
+}
+
+// clipScreen returns the part of the screen the view can draw on.
+// It returns false if the view is entirely clipped out.
+func (v *View) clipScreen(screen *ebiten.Image) (*ebiten.Image, bool) {
+	clip, ok := v.clipRect()
+	if !ok {
+		return screen, true
+	}
+	if !clip.Overlaps(v.screenRect(v.paintBounds(scaleFrame(v.frame)))) {
+		return nil, false
+	}
+	if screen == nil {
+		return nil, true
+	}
+	return screen.SubImage(clip).(*ebiten.Image), true
 }
 
 func (ct *View) drawChild(screen *ebiten.Image, child *View) {
@@ -42,7 +282,6 @@ func (ct *View) drawChild(screen *ebiten.Image, child *View) {
 	if ct.shouldDrawChild(child) {
 		ct.handleDraw(screen, b, child)
 	}
-	child.Draw(screen)
 	ct.debugDraw(screen, b, child)
 }
 
@@ -51,11 +290,12 @@ func (ct *View) computeBounds(child *View) image.Rectangle {
 }
 
 func (ct *View) handleDraw(screen *ebiten.Image, b image.Rectangle, child *View) {
-	child.Handler.Draw(screen, b, child)
+	child.drawContent(screen, b)
 }
 
 func (ct *View) shouldDrawChild(child *View) bool {
-	return !child.Attrs.Hidden && child.Attrs.Display != DisplayNone && child.Handler.Draw != nil
+	return !child.Attrs.Hidden && child.Attrs.Display != DisplayNone &&
+		(child.Handler.Draw != nil || child.hasText() || child.hasBox())
 }
 
 func (ct *View) debugDraw(screen *ebiten.Image, b image.Rectangle, child *View) {
@@ -71,19 +311,27 @@ func (ct *View) debugDraw(screen *ebiten.Image, b image.Rectangle, child *View)
 
 // HandleJustPressedTouchID handles touch event. LayoutFrame is the frame of the container in flex layout.
 func (ct *View) HandleJustPressedTouchID(layoutFrame *image.Rectangle, touchID ebiten.TouchID, x, y int) bool {
-	for c := len(ct.children) - 1; c >= 0; c-- {
-		child := ct.children[c]
-		childFrame := ct.childFrame(child)
-		if child.HandleJustPressedTouchID(childFrame, touchID, x, y) {
+	views := ct.hitOrder()
+	for i := len(views) - 1; i >= 0; i-- {
+		c := views[i].view
+		if !c.isInsideClip(x, y) {
+			continue
+		}
+		if c.onJustPressedTouchID(c.parent.childFrame(c), touchID, x, y) {
 			return true
 		}
 	}
+	return ct.onJustPressedTouchID(layoutFrame, touchID, x, y)
+}
+
+func (ct *View) onJustPressedTouchID(layoutFrame *image.Rectangle, touchID ebiten.TouchID, x, y int) bool {
 	if ct.Attrs.Display == DisplayNone {
 		return false
 	}
 	if layoutFrame == nil {
 		layoutFrame = &ct.frame
 	}
+	x, y = ct.localPoint(x, y)
 	ct.checkSwipeHandlerStart(layoutFrame, touchID, x, y)
 	if isInside(layoutFrame, x, y) {
 		if ct.Handler.HandleJustPressedTouchID(touchID, x, y) {
@@ -95,61 +343,95 @@ func (ct *View) HandleJustPressedTouchID(layoutFrame *image.Rectangle, touchID e
 }
 
 func (ct *View) HandleJustReleasedTouchID(layoutFrame *image.Rectangle, touchID ebiten.TouchID, x, y int) {
-	for c := len(ct.children) - 1; c >= 0; c-- {
-		child := ct.children[c]
-		childFrame := ct.childFrame(child)
-		child.HandleJustReleasedTouchID(childFrame, touchID, x, y)
+	views := ct.hitOrder()
+	for i := len(views) - 1; i >= 0; i-- {
+		c := views[i].view
+		c.onJustReleasedTouchID(c.parent.childFrame(c), touchID, x, y)
 	}
+	ct.onJustReleasedTouchID(layoutFrame, touchID, x, y)
+}
+
+func (ct *View) onJustReleasedTouchID(layoutFrame *image.Rectangle, touchID ebiten.TouchID, x, y int) {
 	if layoutFrame == nil {
 		layoutFrame = &ct.frame
 	}
-	ct.checkSwipeHandlerEnd(touchID, x, y)
+	lx, ly := ct.localPoint(x, y)
+	ct.checkSwipeHandlerEnd(touchID, lx, ly)
 	if ct.Status.handledTouchID == touchID {
-		ct.Handler.HandleJustReleasedTouchID(touchID, x, y, !isInside(layoutFrame, x, y))
+		isCancel := !isInside(layoutFrame, lx, ly) || !ct.isInsideClip(x, y)
+		ct.Handler.HandleJustReleasedTouchID(touchID, lx, ly, isCancel)
 		ct.Status.handledTouchID = -1
 	}
 }
 
 func (ct *View) handleMouse(layoutFrame *image.Rectangle, x, y int) bool {
-	for c := len(ct.children) - 1; c >= 0; c-- {
-		child := ct.children[c]
-		childFrame := ct.childFrame(child)
-		if child.handleMouse(childFrame, x, y) {
+	views := ct.hitOrder()
+	for i := len(views) - 1; i >= 0; i-- {
+		c := views[i].view
+		if !c.isInsideClip(x, y) {
+			continue
+		}
+		if c.onMouse(c.parent.childFrame(c), x, y) {
 			return true
 		}
 	}
+	return ct.onMouse(layoutFrame, x, y)
+}
+
+func (ct *View) onMouse(layoutFrame *image.Rectangle, x, y int) bool {
 	if ct.Attrs.Display == DisplayNone {
 		return false
 	}
 	if layoutFrame == nil {
 		layoutFrame = &ct.frame
 	}
+	x, y = ct.localPoint(x, y)
 	if isInside(layoutFrame, x, y) {
 		return ct.Handler.HandleMouse(x, y)
 	}
 	return false
 }
 
+// handleMouseEnterLeave dispatches mouse enter and leave events.
+// A view is not entered when one of its descendants is entered.
 func (ct *View) handleMouseEnterLeave(layoutFrame *image.Rectangle, x, y int) bool {
-	result := false
-	for c := len(ct.children) - 1; c >= 0; c-- {
-		child := ct.children[c]
-		childFrame := ct.childFrame(child)
-		if child.handleMouseEnterLeave(childFrame, x, y) {
-			result = true
+	entered := map[*View]bool{}
+	views := ct.hitOrder()
+	for i := len(views) - 1; i >= 0; i-- {
+		c := views[i].view
+		if c.onMouseEnterLeave(c.parent.childFrame(c), x, y, entered[c]) {
+			for p := c.parent; ; p = p.parent {
+				entered[p] = true
+				if p == ct {
+					break
+				}
+			}
 		}
 	}
+	if ct.onMouseEnterLeave(layoutFrame, x, y, entered[ct]) {
+		return true
+	}
+	return entered[ct]
+}
+
+func (ct *View) onMouseEnterLeave(layoutFrame *image.Rectangle, x, y int, descendantEntered bool) bool {
 	if ct.Attrs.Display == DisplayNone {
-		return result
+		return false
+	}
+	if layoutFrame == nil {
+		layoutFrame = &ct.frame
 	}
-	if !result && !ct.Status.isMouseEntered && isInside(layoutFrame, x, y) {
-		if ct.Handler.HandleMouseEnter(x, y) {
+	lx, ly := ct.localPoint(x, y)
+	inside := isInside(layoutFrame, lx, ly) && ct.isInsideClip(x, y)
+	result := false
+	if !descendantEntered && !ct.Status.isMouseEntered && inside {
+		if ct.Handler.HandleMouseEnter(lx, ly) {
 			result = true
 			ct.Status.isMouseEntered = true
 		}
 	}
 
-	if ct.Status.isMouseEntered && !isInside(layoutFrame, x, y) {
+	if ct.Status.isMouseEntered && !inside {
 		ct.Status.isMouseEntered = false
 		ct.Handler.HandleMouseLeave()
 	}
@@ -157,20 +439,27 @@ func (ct *View) handleMouseEnterLeave(layoutFrame *image.Rectangle, x, y int) bo
 }
 
 func (ct *View) handleMouseButtonLeftPressed(layoutFrame *image.Rectangle, x, y int) bool {
-	for c := len(ct.children) - 1; c >= 0; c-- {
-		child := ct.children[c]
-		childFrame := ct.childFrame(child)
-		if child.handleMouseButtonLeftPressed(childFrame, x, y) {
+	views := ct.hitOrder()
+	for i := len(views) - 1; i >= 0; i-- {
+		c := views[i].view
+		if !c.isInsideClip(x, y) {
+			continue
+		}
+		if c.onMouseButtonLeftPressed(c.parent.childFrame(c), x, y) {
 			return true
 		}
 	}
+	return ct.onMouseButtonLeftPressed(layoutFrame, x, y)
+}
+
+func (ct *View) onMouseButtonLeftPressed(layoutFrame *image.Rectangle, x, y int) bool {
 	if ct.Attrs.Display == DisplayNone {
 		return false
 	}
 	if layoutFrame == nil {
 		layoutFrame = &ct.frame
 	}
-
+	x, y = ct.localPoint(x, y)
 	if isInside(layoutFrame, x, y) {
 		ct.Status.isMousePressed = true
 		return ct.Handler.HandleJustPressedMouseButtonLeft(*layoutFrame, x, y)
@@ -179,15 +468,20 @@ func (ct *View) handleMouseButtonLeftPressed(layoutFrame *image.Rectangle, x, y
 }
 
 func (ct *View) handleMouseButtonLeftReleased(layoutFrame *image.Rectangle, x, y int) {
-	for c := len(ct.children) - 1; c >= 0; c-- {
-		child := ct.children[c]
-		childFrame := ct.childFrame(child)
-		child.handleMouseButtonLeftReleased(childFrame, x, y)
+	views := ct.hitOrder()
+	for i := len(views) - 1; i >= 0; i-- {
+		c := views[i].view
+		c.onMouseButtonLeftReleased(c.parent.childFrame(c), x, y)
 	}
+	ct.onMouseButtonLeftReleased(layoutFrame, x, y)
+}
+
+func (ct *View) onMouseButtonLeftReleased(layoutFrame *image.Rectangle, x, y int) {
 	if layoutFrame == nil {
 		layoutFrame = &ct.frame
 	}
 	if ct.Status.isMousePressed {
+		x, y = ct.localPoint(x, y)
 		ct.Handler.HandleJustReleasedMouseButtonLeft(*layoutFrame, x, y)
 		ct.Status.isMousePressed = false
 	}
@@ -206,7 +500,9 @@ func (ct *View) handleTouchEvents(layoutFrame *image.Rectangle) {
 			x, y := ebiten.TouchPosition(touchID)
 			recordTouchPosition(touchID, x, y)
 
+			ct.focusAt(x, y)
 			ct.HandleJustPressedTouchID(layoutFrame, touchID, x, y)
+			ct.startScrollDrag(touchID, x, y)
 			ct.touchIDs = append(ct.touchIDs, touchID)
 		}
 	}
@@ -216,9 +512,11 @@ func (ct *View) handleTouchEvents(layoutFrame *image.Rectangle) {
 		if inpututil.IsTouchJustReleased(touchIDs[t]) {
 			pos := lastTouchPosition(touchIDs[t])
 			ct.HandleJustReleasedTouchID(layoutFrame, touchIDs[t], pos.X, pos.Y)
+			ct.endScrollDrag(touchIDs[t])
 		} else {
 			x, y := ebiten.TouchPosition(touchIDs[t])
 			recordTouchPosition(touchIDs[t], x, y)
+			ct.dragScroll(touchIDs[t], x, y)
 		}
 	}
 }
@@ -227,7 +525,11 @@ func (ct *View) handleMouseEvents(layoutFrame *image.Rectangle) {
 	x, y := ebiten.CursorPosition()
 	ct.handleMouse(layoutFrame, x, y)
 	ct.handleMouseEnterLeave(layoutFrame, x, y)
+	if wx, wy := ebiten.Wheel(); wx != 0 || wy != 0 {
+		ct.handleWheel(x, y, wx, wy)
+	}
 	if inpututil.IsMouseButtonJustPressed((ebiten.MouseButtonLeft)) {
+		ct.focusAt(x, y)
 		ct.handleMouseButtonLeftPressed(layoutFrame, x, y)
 	}
 	if inpututil.IsMouseButtonJustReleased((ebiten.MouseButtonLeft)) {
@@ -235,16 +537,21 @@ func (ct *View) handleMouseEvents(layoutFrame *image.Rectangle) {
 	}
 }
 
+// setFrame sets the frame of the view given by the layout of its parent.
+// The view is laid out again if its size changes, and its descendants
+// move with it if only its position changes.
 func (ct *View) setFrame(frame image.Rectangle) {
+	if frame.Size() != ct.frame.Size() {
+		ct.isDirty = true
+	} else if d := frame.Min.Sub(ct.frame.Min); d != (image.Point{}) {
+		for _, c := range ct.children {
+			c.translate(d)
+		}
+	}
 	ct.frame = frame
-	ct.isDirty = true
 }
 
 func (ct *View) childFrame(c *View) *image.Rectangle {
-	if c.IsAbsolute() {
-		bounds := scaleFrame(c.bounds)
-		return &bounds
-	}
 	bounds := scaleFrame(c.bounds.Add(ct.frame.Min))
 	return &bounds
 }
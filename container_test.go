@@ -4,6 +4,7 @@ import (
 	"image"
 	"testing"
 
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,3 +33,262 @@ func TestIsInside(t *testing.T) {
 		require.Equal(t, tt.want, isInside(&tt.r, tt.x, tt.y))
 	}
 }
+
+func TestOrderDrawAndHitTest(t *testing.T) {
+	var drawn []string
+	record := func(name string) *mockHandler {
+		h := NewMockHandler()
+		h.ViewHandler.Draw = func(screen *ebiten.Image, frame image.Rectangle, v *View) {
+			drawn = append(drawn, name)
+		}
+		return h
+	}
+	front, back := record("front"), record("back")
+
+	flex := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(100),
+			Height:     Px(100),
+			Direction:  Row,
+			AlignItems: AlignItemStart,
+		},
+	}
+	flex.AddChild(
+		&View{
+			Attrs:   ViewAttrs{Width: Px(50), Height: Px(50), Order: 1},
+			Handler: front.ViewHandler,
+		},
+		&View{
+			Attrs:   ViewAttrs{Width: Px(50), Height: Px(50), MarginRight: Px(-25)},
+			Handler: back.ViewHandler,
+		},
+	)
+	flex.Update()
+	flex.Draw(nil)
+
+	require.Equal(t, []string{"back", "front"}, drawn)
+
+	flex.handleMouseButtonLeftPressed(nil, 30, 10)
+	require.True(t, front.IsClickPressed)
+	require.False(t, back.IsClickPressed)
+}
+
+func TestZIndexDrawAndHitTest(t *testing.T) {
+	var drawn []string
+	record := func(name string) *mockHandler {
+		h := NewMockHandler()
+		h.ViewHandler.Draw = func(screen *ebiten.Image, frame image.Rectangle, v *View) {
+			drawn = append(drawn, name)
+		}
+		return h
+	}
+	parent, below, inner, popup, popupChild, sibling := record("parent"), record("below"),
+		record("inner"), record("popup"), record("popupChild"), record("sibling")
+
+	flex := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(100),
+			Height:     Px(100),
+			Direction:  Row,
+			AlignItems: AlignItemStart,
+		},
+	}
+	flex.AddChild(
+		(&View{
+			Attrs:   ViewAttrs{Width: Px(50), Height: Px(50)},
+			Handler: parent.ViewHandler,
+		}).AddChild(
+			&View{
+				Attrs:   ViewAttrs{Width: Px(50), Height: Px(50), ZIndex: -1},
+				Handler: below.ViewHandler,
+			},
+			(&View{
+				Attrs:   ViewAttrs{Position: PositionAbsolute, Left: Px(40), Width: Px(20), Height: Px(20), ZIndex: 2},
+				Handler: popup.ViewHandler,
+			}).AddChild(&View{
+				Attrs:   ViewAttrs{Width: Px(20), Height: Px(20), ZIndex: -5},
+				Handler: popupChild.ViewHandler,
+			}),
+			&View{
+				Attrs:   ViewAttrs{Position: PositionAbsolute, Width: Px(10), Height: Px(10)},
+				Handler: inner.ViewHandler,
+			},
+		),
+		&View{
+			Attrs:   ViewAttrs{Width: Px(50), Height: Px(50)},
+			Handler: sibling.ViewHandler,
+		},
+	)
+	flex.Update()
+	flex.Draw(nil)
+
+	require.Equal(t, []string{"below", "parent", "inner", "sibling", "popup", "popupChild"}, drawn)
+
+	// The popup is drawn over the sibling that comes after its parent.
+	flex.handleMouseButtonLeftPressed(nil, 55, 10)
+	require.True(t, popupChild.IsClickPressed)
+	require.False(t, sibling.IsClickPressed)
+
+	// Views with a negative z-index are below their parent.
+	flex.handleMouseButtonLeftPressed(nil, 20, 20)
+	require.True(t, parent.IsClickPressed)
+	require.False(t, below.IsClickPressed)
+
+	flex.handleMouseButtonLeftPressed(nil, 5, 5)
+	require.True(t, inner.IsClickPressed)
+}
+
+func TestZIndexHidden(t *testing.T) {
+	var drawn []string
+	record := func(name string) *mockHandler {
+		h := NewMockHandler()
+		h.ViewHandler.Draw = func(screen *ebiten.Image, frame image.Rectangle, v *View) {
+			drawn = append(drawn, name)
+		}
+		return h
+	}
+	visible, hidden := record("visible"), record("hidden")
+
+	flex := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	flex.AddChild(
+		(&View{Attrs: ViewAttrs{Width: Px(50), Height: Px(50), Hidden: true}}).AddChild(
+			&View{
+				Attrs:   ViewAttrs{Width: Px(10), Height: Px(10), ZIndex: 1},
+				Handler: hidden.ViewHandler,
+			},
+		),
+		&View{
+			Attrs:   ViewAttrs{Width: Px(50), Height: Px(50), ZIndex: 1},
+			Handler: visible.ViewHandler,
+		},
+	)
+	flex.Update()
+	flex.Draw(nil)
+
+	require.Equal(t, []string{"visible"}, drawn)
+}
+
+func TestHitOrderCache(t *testing.T) {
+	a := &View{Attrs: ViewAttrs{Position: PositionAbsolute, Width: Px(50), Height: Px(50)}}
+	b := &View{Attrs: ViewAttrs{Position: PositionAbsolute, Width: Px(50), Height: Px(50)}}
+	inner := &View{Attrs: ViewAttrs{Width: Px(50), Height: Px(50)}}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	root.AddChild(a, b.AddChild(inner))
+	root.Update()
+	require.Same(t, inner, root.viewAt(10, 10))
+
+	// The order is cached between events.
+	require.Same(t, &root.hitOrder()[0], &root.hitOrder()[0])
+
+	a.SetZIndex(1)
+	require.Same(t, a, root.viewAt(10, 10))
+	a.SetZIndex(0)
+	b.SetOrder(-1)
+	require.Same(t, a, root.viewAt(10, 10))
+
+	// Changes of the children of a descendant change the order of the root.
+	top := &View{Attrs: ViewAttrs{Position: PositionAbsolute, Width: Px(20), Height: Px(20), ZIndex: 1}}
+	inner.AddChild(top)
+	root.Update()
+	require.Same(t, top, root.viewAt(10, 10))
+	inner.RemoveChild(top)
+	require.Same(t, a, root.viewAt(10, 10))
+
+	// Attributes written directly change the order too.
+	b.Attrs.Order = 0
+	require.Same(t, inner, root.viewAt(10, 10))
+	a.Attrs.ZIndex = 1
+	require.Same(t, a, root.viewAt(10, 10))
+}
+
+func TestOverflowHidden(t *testing.T) {
+	var screens []image.Rectangle
+	sprite := NewMockHandler()
+	sprite.ViewHandler.Draw = func(screen *ebiten.Image, frame image.Rectangle, v *View) {
+		screens = append(screens, screen.Bounds())
+	}
+	pushed, popup, outside, moved := NewMockHandler(), NewMockHandler(), NewMockHandler(), NewMockHandler()
+	outside.ViewHandler.Draw = func(screen *ebiten.Image, frame image.Rectangle, v *View) {
+		outside.IsDrawn = true
+	}
+	moved.ViewHandler.Draw = func(screen *ebiten.Image, frame image.Rectangle, v *View) {
+		moved.IsDrawn = true
+	}
+
+	panel := &View{
+		Attrs: ViewAttrs{
+			Left:       Px(10),
+			Top:        Px(10),
+			Width:      Px(50),
+			Height:     Px(50),
+			Position:   PositionAbsolute,
+			AlignItems: AlignItemStart,
+			Overflow:   OverflowHidden,
+		},
+	}
+	panel.AddChild(
+		&View{
+			Attrs:   ViewAttrs{Width: Px(80), Height: Px(20)},
+			Handler: sprite.ViewHandler,
+		},
+		&View{
+			Attrs:   ViewAttrs{Width: Px(20), Height: Px(20), MarginLeft: Px(-40)},
+			Handler: pushed.ViewHandler,
+		},
+		(&View{
+			Attrs: ViewAttrs{Width: Px(20), Height: Px(20), MarginLeft: Px(-40), Overflow: OverflowHidden},
+		}).AddChild(&View{
+			Attrs:   ViewAttrs{Position: PositionAbsolute, Left: Px(10), Width: Px(30), Height: Px(30), ZIndex: 1},
+			Handler: popup.ViewHandler,
+		}),
+		&View{
+			Attrs:   ViewAttrs{Position: PositionAbsolute, Top: Px(60), Width: Px(10), Height: Px(10)},
+			Handler: outside.ViewHandler,
+		},
+		&View{
+			Attrs: ViewAttrs{
+				Position: PositionAbsolute, Top: Px(60), Width: Px(10), Height: Px(10),
+				Transform: []TransformFunc{{Func: TransformTranslate, Y: -40}},
+			},
+			Handler: moved.ViewHandler,
+		},
+	)
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	root.AddChild(panel)
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 100))
+
+	// The sprite is wider than the panel, so it is drawn clipped to the panel.
+	require.Equal(t, []image.Rectangle{image.Rect(10, 10, 60, 60)}, screens)
+	// Views entirely outside of the panel are not drawn.
+	require.False(t, outside.IsDrawn)
+	// Views transformed into the panel are drawn.
+	require.True(t, moved.IsDrawn)
+
+	// The child pushed out of the panel by its negative margin
+	// receives events only inside the panel.
+	require.Equal(t, image.Rect(50, 10, 70, 30), pushed.Frame)
+	root.handleMouseButtonLeftPressed(nil, 65, 20)
+	require.False(t, pushed.IsClickPressed)
+	root.HandleJustPressedTouchID(nil, 1, 65, 20)
+	require.False(t, pushed.IsPressed)
+	root.handleMouse(nil, 65, 20)
+	require.False(t, pushed.IsMouseMoved)
+
+	// Clips of nested views are intersected, even for views in stacking contexts.
+	require.Equal(t, image.Rect(40, 10, 70, 40), popup.Frame)
+	clip, ok := panel.children[2].children[0].clipRect()
+	require.True(t, ok)
+	require.Equal(t, image.Rect(30, 10, 50, 30), clip)
+	root.handleMouseButtonLeftPressed(nil, 45, 35)
+	require.False(t, popup.IsClickPressed)
+	root.handleMouseButtonLeftPressed(nil, 45, 25)
+	require.True(t, popup.IsClickPressed)
+
+	// A touch released outside of the clip is canceled.
+	root.HandleJustPressedTouchID(nil, 2, 45, 25)
+	require.True(t, popup.IsPressed)
+	root.HandleJustReleasedTouchID(nil, 2, 55, 25)
+	require.True(t, popup.IsReleased)
+	require.True(t, popup.IsCancel)
+}
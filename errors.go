@@ -1,6 +1,18 @@
 package furex
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidHTML is the error of HTML which can't be parsed into a view,
+	// such as HTML without exactly one root view.
+	ErrInvalidHTML = errors.New("invalid html")
+	// ErrUnknownComponent is the error of a tag which is not a component.
+	ErrUnknownComponent = errors.New("unknown component")
+)
 
 type ErrorList struct {
 	errors []error
@@ -23,3 +35,50 @@ func (e *ErrorList) Error() string {
 func (e *ErrorList) HasErrors() bool {
 	return len(e.errors) > 0
 }
+
+// Errors returns the errors in the order they were added.
+func (e *ErrorList) Errors() []error {
+	return e.errors
+}
+
+// Is returns true if any of the errors is target, so errors.Is finds the errors in the list.
+func (e *ErrorList) Is(target error) bool {
+	for _, err := range e.errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseError is an error of the HTML given to ParseE.
+type ParseError struct {
+	// Line and Column are the position of the tag or the @keyframes rule in the
+	// HTML, starting at 1. They are 0 if the error has no position.
+	Line   int
+	Column int
+	// Tag is the name of the tag the error is about, if any.
+	Tag string
+	// Property is the name of the style property the error is about, if any.
+	Property string
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	sb := &strings.Builder{}
+	if e.Line > 0 {
+		fmt.Fprintf(sb, "%d:%d: ", e.Line, e.Column)
+	}
+	if e.Tag != "" {
+		fmt.Fprintf(sb, "<%s> ", e.Tag)
+	}
+	if e.Property != "" {
+		fmt.Fprintf(sb, "%s: ", e.Property)
+	}
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -104,6 +104,34 @@ func (f FlexAlignItem) String() string {
 	}
 }
 
+// FlexAlignSelf overrides the container's align-items for a single item.
+type FlexAlignSelf uint8
+
+const (
+	AlignSelfAuto FlexAlignSelf = iota // use the container's align-items
+	AlignSelfStart
+	AlignSelfEnd
+	AlignSelfCenter
+	AlignSelfStretch
+)
+
+func (f FlexAlignSelf) String() string {
+	switch f {
+	case AlignSelfAuto:
+		return "auto"
+	case AlignSelfStart:
+		return "flex-start"
+	case AlignSelfEnd:
+		return "flex-end"
+	case AlignSelfCenter:
+		return "center"
+	case AlignSelfStretch:
+		return "stretch"
+	default:
+		return fmt.Sprintf("unknown align-self: %d", f)
+	}
+}
+
 // FlexWrap controls whether the container is single- or multi-line,
 // and the direction in which the lines are laid out.
 type FlexWrap uint8
@@ -164,6 +192,9 @@ type FlexPosition uint8
 const (
 	PositionStatic FlexPosition = iota
 	PositionAbsolute
+	// PositionRelative lays out the view as a static one,
+	// then moves it by its left, top, right and bottom insets.
+	PositionRelative
 )
 
 func (p FlexPosition) String() string {
@@ -172,6 +203,8 @@ func (p FlexPosition) String() string {
 		return "static"
 	case PositionAbsolute:
 		return "absolute"
+	case PositionRelative:
+		return "relative"
 	}
 	return fmt.Sprintf("unknown position: %d", p)
 }
@@ -194,41 +227,73 @@ func (d FlexDisplay) String() string {
 	return fmt.Sprintf("unknown display: %d", d)
 }
 
+// FlexOverflow is the 'overflow' property
+type FlexOverflow uint8
+
+const (
+	// OverflowVisible draws the children of the view outside its frame.
+	OverflowVisible FlexOverflow = iota
+	// OverflowHidden clips the drawing of the children of the view to its frame,
+	// and the parts of them outside of it don't receive touch and mouse events.
+	// The view can be scrolled with ScrollTo, but not by the user.
+	OverflowHidden
+	// OverflowScroll clips the children of the view to its frame
+	// and lets the user scroll them with the mouse wheel or by dragging.
+	OverflowScroll
+)
+
+func (o FlexOverflow) String() string {
+	switch o {
+	case OverflowVisible:
+		return "visible"
+	case OverflowHidden:
+		return "hidden"
+	case OverflowScroll:
+		return "scroll"
+	}
+	return fmt.Sprintf("unknown overflow: %d", o)
+}
+
 // layout is the main routine that implements a subset of flexbox layout
 // https://www.w3.org/TR/css-flexbox-1/#layout-algorithm
 func (f *View) layout(width, height int, container *containerEmbed) {
+	// The flex items are laid out inside the content box,
+	// so the padding is taken out of the available space.
+	paddingX := f.used.PaddingLeft + f.used.PaddingRight
+	paddingY := f.used.PaddingTop + f.used.PaddingBottom
+	width, height = width-paddingX, height-paddingY
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	contentOrigin := f.frame.Min.Add(image.Pt(f.used.PaddingLeft, f.used.PaddingTop))
+	ctx := f.lengthContext()
+
 	// 9.2. Line Length Determination
 	// Determine the available main and cross space for the flex items.
 	containerMainSize := float64(f.mainSize(width, height))
 	containerCrossSize := float64(f.crossSize(width, height))
+	mainGap, crossGap := f.mainGap(), f.crossGap()
 
 	// Determine the flex base size and hypothetical main size of each item:
 	var children []element
-	for _, c := range container.children {
+	for _, c := range container.orderedChildren() {
 		if c.Attrs.Display == DisplayNone {
 			continue
 		}
 		if c.Attrs.Position == PositionAbsolute {
-			x := container.frame.Min.X
-			if c.Attrs.Left != 0 {
-				x = container.frame.Min.X + c.Attrs.Left
-			} else if c.Attrs.Right != nil {
-				x = container.frame.Max.X - *c.Attrs.Right - c.Attrs.Width
-			}
-			y := container.frame.Min.Y
-			if c.Attrs.Top != 0 {
-				y = container.frame.Min.Y + c.Attrs.Top
-			} else if c.Attrs.Bottom != nil {
-				y = container.frame.Max.Y - *c.Attrs.Bottom - c.Attrs.Height
-			}
-			c.frame = image.Rect(x, y, x+c.Attrs.Width, y+c.Attrs.Height)
-			c.bounds = image.Rect(0, 0, c.Attrs.Width, c.Attrs.Height)
+			f.layoutAbsolute(c, contentOrigin, ctx)
 			continue
 		}
+		if c.isMeasured() {
+			f.measureItem(c, width, height)
+		}
 		children = append(children, element{
-			widthInPct:   c.Attrs.WidthInPct,
-			heightInPct:  c.Attrs.HeightInPct,
-			flexBaseSize: float64(f.flexBaseSize(c)),
+			widthInPct:   c.used.WidthInPct,
+			heightInPct:  c.used.HeightInPct,
+			flexBaseSize: f.flexBaseSize(c, containerMainSize, ctx),
 			node:         c,
 		})
 	}
@@ -239,7 +304,7 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 		// Calculate the remaining width after taking out the fixed width items.
 		remFree := width
 		for _, c := range children {
-			remFree -= (c.node.Attrs.Width + c.node.Attrs.MarginLeft + c.node.Attrs.MarginRight)
+			remFree -= (c.node.used.Width + c.node.used.MarginLeft + c.node.used.MarginRight)
 		}
 		// If there is remaining space, distribute it among the flexible items.
 		if remFree > 0 {
@@ -247,7 +312,7 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 				if c.widthInPct > 0 {
 					v := float64(width) * c.widthInPct / 100.
 					children[i].node.calculatedWidth = int(math.Min(v, float64(remFree)))
-					children[i].flexBaseSize = float64(f.flexBaseSize(children[i].node))
+					children[i].flexBaseSize = f.flexBaseSize(children[i].node, containerMainSize, ctx)
 				}
 			}
 		}
@@ -255,14 +320,14 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 		for _, c := range children {
 			if c.heightInPct > 0 {
 				// Calculate the new width based on the item's width percentage.
-				c.node.calculatedHeight = int(float64(height) * c.node.Attrs.HeightInPct / 100)
+				c.node.calculatedHeight = int(float64(height) * c.node.used.HeightInPct / 100)
 			}
 		}
 	case Column:
 		// Calculate the remaining height after taking out the fixed width items.
 		remFree := height
 		for _, c := range children {
-			remFree -= (c.node.Attrs.Height + c.node.Attrs.MarginTop + c.node.Attrs.MarginBottom)
+			remFree -= (c.node.used.Height + c.node.used.MarginTop + c.node.used.MarginBottom)
 		}
 		// If there is remaining space, distribute it among the flexible items.
 		if remFree > 0 {
@@ -270,7 +335,7 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 				if c.heightInPct > 0 {
 					v := float64(height) * c.heightInPct / 100.
 					children[i].node.calculatedHeight = int(math.Min(v, float64(remFree)))
-					children[i].flexBaseSize = float64(f.flexBaseSize(children[i].node))
+					children[i].flexBaseSize = f.flexBaseSize(children[i].node, containerMainSize, ctx)
 				}
 			}
 		}
@@ -278,13 +343,21 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 		for _, c := range children {
 			if c.widthInPct > 0 {
 				// Calculate the new width based on the item's width percentage.
-				c.node.calculatedWidth = int(float64(width) * c.node.Attrs.WidthInPct / 100)
+				c.node.calculatedWidth = int(float64(width) * c.node.used.WidthInPct / 100)
 			}
 		}
 	default:
 		panic(fmt.Sprint("flex: bad direction ", f.Attrs.Direction))
 	}
 
+	// Determine the hypothetical main size of each item,
+	// which is the flex base size clamped by the min and max main size.
+	for i := range children {
+		child := &children[i]
+		child.minMainSize, child.maxMainSize = f.mainConstraints(child.node, width, height, ctx)
+		child.hypotheticalMainSize = clamp(child.flexBaseSize, child.minMainSize, child.maxMainSize)
+	}
+
 	// §9.3. Main Size Determination
 	// Collect flex items into flex lines
 	var lines []flexLine
@@ -295,9 +368,10 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 			child := &children[i]
 			child.mainMargin = f.mainMargin(child.node)
 			line.child[i] = child
-			line.mainSize += child.flexBaseSize +
+			line.mainSize += child.hypotheticalMainSize +
 				(child.mainMargin[0] + child.mainMargin[1])
 		}
+		line.mainSize += line.gaps(mainGap)
 		lines = []flexLine{line}
 	} else {
 		// Multi line
@@ -306,14 +380,17 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 			child := &children[i]
 			child.mainMargin = f.mainMargin(child.node)
 
-			// hypotheticalMainSize = flexBaseSize + main margin
-			hypotheticalMainSize := child.flexBaseSize +
+			// outer hypothetical main size = hypothetical main size + main margin
+			hypotheticalMainSize := child.hypotheticalMainSize +
 				(child.mainMargin[0] + child.mainMargin[1])
 
-			if line.mainSize > 0 && line.mainSize+hypotheticalMainSize > containerMainSize {
+			if len(line.child) > 0 && line.mainSize+mainGap+hypotheticalMainSize > containerMainSize {
 				lines = append(lines, line)
 				line = flexLine{}
 			}
+			if len(line.child) > 0 {
+				line.mainSize += mainGap
+			}
 			line.child = append(line.child, child)
 			line.mainSize += hypotheticalMainSize
 		}
@@ -329,32 +406,30 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 
 		grow := line.mainSize < containerMainSize // §9.7.1
 
-		// §9.7.2 freeze inflexible children.
+		// §9.7.2 freeze inflexible children at their hypothetical main size.
+		// Children whose min or max main size already works against the
+		// direction of flexing are inflexible too.
 		for _, child := range line.child {
-			mainSize := float64(f.mainSize(child.node.width(), child.node.height()))
+			child.mainSize = child.hypotheticalMainSize
 			if grow {
-				if child.node.Attrs.Grow == 0 {
+				if child.node.Attrs.Grow == 0 ||
+					child.flexBaseSize > child.hypotheticalMainSize {
 					child.frozen = true
-					child.mainSize = mainSize
 				}
 			} else {
-				if child.node.Attrs.Shrink == 0 {
+				if child.node.Attrs.Shrink == 0 ||
+					child.flexBaseSize < child.hypotheticalMainSize {
 					child.frozen = true
-					child.mainSize = mainSize
 				}
 			}
 		}
 
 		// §9.7.3 calculate initial free space
-		freeSpace := float64(f.mainSize(width, height))
-		for _, child := range line.child {
-			freeSpace -= (float64(f.flexBaseSize(child.node)) +
-				(child.mainMargin[0] + child.mainMargin[1]))
-		}
+		freeSpace := line.freeSpace(containerMainSize, mainGap)
 
 		// §9.7.4 flex loop
 		for {
-			// Check for flexible items.
+			// a. Check for flexible items.
 			allFrozen := true
 			for _, child := range line.child {
 				if !child.frozen {
@@ -366,20 +441,17 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 				break
 			}
 
-			// Calculate remaining free space.
-			remFreeSpace := float64(f.mainSize(width, height))
+			// b. Calculate remaining free space.
+			remFreeSpace := line.freeSpace(containerMainSize, mainGap)
 			unfrozenFlexFactor := 0.0
 			for _, child := range line.child {
-				mainMargin := child.mainMargin[0] + child.mainMargin[1]
 				if child.frozen {
-					remFreeSpace -= (child.mainSize + mainMargin)
+					continue
+				}
+				if grow {
+					unfrozenFlexFactor += child.node.Attrs.Grow
 				} else {
-					remFreeSpace -= (float64(f.mainSize(child.node.width(), child.node.height())) + mainMargin)
-					if grow {
-						unfrozenFlexFactor += child.node.Attrs.Grow
-					} else {
-						unfrozenFlexFactor += child.node.Attrs.Shrink
-					}
+					unfrozenFlexFactor += child.node.Attrs.Shrink
 				}
 			}
 
@@ -390,16 +462,14 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 				}
 			}
 
-			// Distribute free space proportional to flex factors.
+			// c. Distribute free space proportional to flex factors.
 			if grow {
 				for _, child := range line.child {
 					if child.frozen {
 						continue
 					}
 					r := child.node.Attrs.Grow / unfrozenFlexFactor
-					child.mainSize = float64(f.mainSize(
-						child.node.width(), child.node.height(),
-					)) + r*remFreeSpace
+					child.mainSize = child.flexBaseSize + r*remFreeSpace
 				}
 			} else {
 				sumScaledShrinkFactor := 0.0
@@ -407,40 +477,77 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 					if child.frozen {
 						continue
 					}
-					scaledShrinkFactor := float64(f.mainSize(
-						child.node.width(), child.node.height(),
-					)) * child.node.Attrs.Shrink
-					sumScaledShrinkFactor += scaledShrinkFactor
+					sumScaledShrinkFactor += child.flexBaseSize * child.node.Attrs.Shrink
 				}
 				for _, child := range line.child {
 					if child.frozen {
 						continue
 					}
-					scaledShrinkFactor := float64(f.mainSize(
-						child.node.width(), child.node.height(),
-					)) * child.node.Attrs.Shrink
-					r := float64(scaledShrinkFactor) / sumScaledShrinkFactor
-					child.mainSize = float64(f.mainSize(
-						child.node.width(), child.node.height(),
-					)) - r*math.Abs(float64(remFreeSpace))
+					child.mainSize = child.flexBaseSize
+					if sumScaledShrinkFactor > 0 {
+						r := child.flexBaseSize * child.node.Attrs.Shrink / sumScaledShrinkFactor
+						child.mainSize -= r * math.Abs(remFreeSpace)
+					}
 				}
 			}
 
+			// d. Fix min/max violations.
+			totalViolation := 0.0
 			for _, child := range line.child {
-				child.frozen = true
+				if child.frozen {
+					continue
+				}
+				clamped := clamp(child.mainSize, child.minMainSize, child.maxMainSize)
+				child.violation = clamped - child.mainSize
+				child.mainSize = clamped
+				totalViolation += child.violation
 			}
 
+			// e. Freeze over-flexed items. If the total violation is zero every
+			// item is frozen, otherwise only the items violating in the same
+			// direction as the total are.
+			for _, child := range line.child {
+				if child.frozen {
+					continue
+				}
+				switch {
+				case totalViolation == 0:
+					child.frozen = true
+				case totalViolation > 0 && child.violation > 0:
+					child.frozen = true
+				case totalViolation < 0 && child.violation < 0:
+					child.frozen = true
+				}
+			}
 		}
 	}
 
 	// §9.4. Cross Size Determination
+	// §9.4.7 Determine the hypothetical cross size of each item by performing
+	// layout with the used main size. Items measured by their handler are
+	// measured again at their used width, so wrapping content gets its height.
+	if f.Attrs.Direction == Row {
+		for l := range lines {
+			for _, c := range lines[l].child {
+				if !c.node.isMeasured() || c.node.isHeightFixed() ||
+					round(c.mainSize) == c.node.calculatedWidth {
+					continue
+				}
+				c.node.measure(round(c.mainSize),
+					height-c.node.used.MarginTop-c.node.used.MarginBottom,
+					MeasureModeExactly)
+			}
+		}
+	}
+
 	// Determine the hypothetical cross size of each item
 	for l := range lines {
 		for _, c := range lines[l].child {
 			c.crossMargin = f.crossMargin(c.node)
-			c.crossSize = float64(
+			c.minCrossSize, c.maxCrossSize = f.crossConstraints(c.node, width, height, ctx)
+			c.crossSize = clamp(float64(
 				f.crossSize(c.node.width(), c.node.height()),
-			)
+			), c.minCrossSize, c.maxCrossSize)
 		}
 	}
 
@@ -466,6 +573,9 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 	off := 0.0
 	for l := range lines {
 		line := &lines[l]
+		if l > 0 {
+			off += crossGap
+		}
 		line.crossOffset = off
 		off += line.crossSize
 	}
@@ -485,11 +595,12 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 	for l := range lines {
 		line := &lines[l]
 		for _, child := range line.child {
-			if f.Attrs.AlignItems == AlignItemStretch &&
+			if f.alignItem(child.node) == AlignItemStretch &&
 				!f.isCrossSizeFixed(child.node) &&
 				child.crossSize < line.crossSize {
 				crossMargin := child.crossMargin[0] + child.crossMargin[1]
-				child.crossSize = line.crossSize - crossMargin
+				child.crossSize = clamp(line.crossSize-crossMargin,
+					child.minCrossSize, child.maxCrossSize)
 			}
 		}
 	}
@@ -502,7 +613,7 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 			total += child.mainSize +
 				(child.mainMargin[0] + child.mainMargin[1])
 		}
-		remFree := containerMainSize - total
+		remFree := containerMainSize - total - line.gaps(mainGap)
 		off, spacing := 0.0, 0.0
 		switch f.Attrs.Justify {
 		case JustifyStart:
@@ -518,7 +629,7 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 		}
 		for _, child := range line.child {
 			child.mainOffset = off + (child.mainMargin[0])
-			off += spacing + child.mainSize +
+			off += spacing + mainGap + child.mainSize +
 				(child.mainMargin[0] + child.mainMargin[1])
 		}
 	}
@@ -533,7 +644,7 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 			}
 			diff := line.crossSize - child.crossSize -
 				(child.crossMargin[0] + child.crossMargin[1])
-			switch f.Attrs.AlignItems {
+			switch f.alignItem(child.node) {
 			case AlignItemStart:
 				// already laid out correctly
 			case AlignItemEnd:
@@ -595,7 +706,9 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 			} else {
 				// Negative free space, divide by scaled flex shrink factor (floored at 1).
 				flexShrinkFactor := math.Max(1, child.node.Attrs.Shrink)
-				maxContentFlexFraction = (flexBaseSize - maxContentSize) / (flexBaseSize * flexShrinkFactor)
+				if flexBaseSize > 0 {
+					maxContentFlexFraction = (maxContentSize - flexBaseSize) / (flexBaseSize * flexShrinkFactor)
+				}
 			}
 			child.maxContentFlexFraction = maxContentFlexFraction
 			if maxContentFlexFraction > largestMaxContentFlexFraction {
@@ -604,26 +717,20 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 		}
 
 		// 2. Add each item’s flex base size to the product of its flex grow/shrink factor and the largest max-content flex fraction.
+		// 3. Determine line size and update intrinsicMainSize.
+		lineSize := line.gaps(mainGap)
 		for _, child := range line.child {
-			var newMainSize float64
 			if largestMaxContentFlexFraction > 0 {
-				newMainSize = child.flexBaseSize + (child.node.Attrs.Grow * largestMaxContentFlexFraction)
+				lineSize += child.flexBaseSize + (child.node.Attrs.Grow * largestMaxContentFlexFraction)
 			} else {
-				newMainSize = child.flexBaseSize - (child.node.Attrs.Shrink * child.flexBaseSize * largestMaxContentFlexFraction)
+				lineSize += child.flexBaseSize + (child.node.Attrs.Shrink * child.flexBaseSize * largestMaxContentFlexFraction)
 			}
-			child.mainSize = newMainSize
-		}
-
-		// 3. Determine line size and update intrinsicMainSize.
-		lineSize := 0.0
-		for _, child := range line.child {
-			lineSize += child.mainSize
 		}
 		if lineSize > intrinsicMainSize {
 			intrinsicMainSize = lineSize
 		}
 	}
-	f.setMainSize(int(intrinsicMainSize))
+	f.setMainSize(int(intrinsicMainSize) + f.mainSize(paddingX, paddingY))
 
 	// §9.9.2. Flex Container Intrinsic Cross Sizes
 	// The min-content/max-content cross size of a single-line flex container
@@ -649,7 +756,7 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 			intrinsicCrossSize = max - min
 		}
 	}
-	f.setCrossSize(int(intrinsicCrossSize))
+	f.setCrossSize(int(intrinsicCrossSize) + f.crossSize(paddingX, paddingY))
 
 	// TODO: Calculate min-content/max-content cross size for multi-line flex container.
 	// For a multi-line flex container, the min-content/max-content cross size is
@@ -670,18 +777,20 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 					round(child.mainOffset),
 					round(child.crossOffset),
 					round(child.mainOffset+child.mainSize),
-					round(child.crossOffset+child.crossSize))
-				child.node.setFrame(child.node.bounds.Add(f.frame.Min))
+					round(child.crossOffset+child.crossSize)).Add(contentOrigin.Sub(f.frame.Min))
 			case Column:
 				child.node.bounds = image.Rect(
 					round(child.crossOffset),
 					round(child.mainOffset),
 					round(child.crossOffset+child.crossSize),
-					round(child.mainOffset+child.mainSize))
-				child.node.setFrame(child.node.bounds.Add(f.frame.Min))
+					round(child.mainOffset+child.mainSize)).Add(contentOrigin.Sub(f.frame.Min))
 			default:
 				panic(fmt.Sprint("flex: bad direction ", f.Attrs.Direction))
 			}
+			if child.node.Attrs.Position == PositionRelative {
+				child.node.bounds = child.node.bounds.Add(child.node.relativeOffset())
+			}
+			child.node.setFrame(child.node.bounds.Add(f.frame.Min))
 		}
 	}
 }
@@ -689,13 +798,19 @@ func (f *View) layout(width, height int, container *containerEmbed) {
 type element struct {
 	node                   *View
 	flexBaseSize           float64
+	hypotheticalMainSize   float64
+	minMainSize            float64
+	maxMainSize            float64
 	mainSize               float64
 	mainOffset             float64
 	mainMargin             []float64
+	minCrossSize           float64
+	maxCrossSize           float64
 	crossSize              float64
 	crossOffset            float64
 	crossMargin            []float64
 	frozen                 bool
+	violation              float64
 	maxContentFlexFraction float64
 	widthInPct             float64
 	heightInPct            float64
@@ -708,6 +823,30 @@ type flexLine struct {
 	child       []*element
 }
 
+// freeSpace returns the free space of the line: the container main size minus
+// the gaps and the outer sizes of the items, using the target main size for
+// frozen items and the flex base size for the others (§9.7.3).
+func (l *flexLine) freeSpace(containerMainSize, gap float64) float64 {
+	free := containerMainSize - l.gaps(gap)
+	for _, child := range l.child {
+		free -= child.mainMargin[0] + child.mainMargin[1]
+		if child.frozen {
+			free -= child.mainSize
+		} else {
+			free -= child.flexBaseSize
+		}
+	}
+	return free
+}
+
+// gaps returns the total size of the gaps between the items of the line.
+func (l *flexLine) gaps(gap float64) float64 {
+	if len(l.child) < 2 {
+		return 0
+	}
+	return gap * float64(len(l.child)-1)
+}
+
 func (f *View) mainSize(x, y int) int {
 	switch f.Attrs.Direction {
 	case Row:
@@ -741,6 +880,51 @@ func (f *View) setMainSize(v int) {
 	}
 }
 
+// alignItem returns the cross axis alignment of the item,
+// taking its align-self into account.
+func (f *View) alignItem(c *View) FlexAlignItem {
+	switch c.Attrs.AlignSelf {
+	case AlignSelfStart:
+		return AlignItemStart
+	case AlignSelfEnd:
+		return AlignItemEnd
+	case AlignSelfCenter:
+		return AlignItemCenter
+	case AlignSelfStretch:
+		return AlignItemStretch
+	default:
+		return f.Attrs.AlignItems
+	}
+}
+
+// mainConstraints returns the min and max main size of the item c.
+// ctx is the length context of f.
+func (f *View) mainConstraints(c *View, width, height int, ctx lengthContext) (float64, float64) {
+	ctx = ctx.child(c)
+	switch f.Attrs.Direction {
+	case Row:
+		return c.widthConstraints(float64(width), ctx)
+	case Column:
+		return c.heightConstraints(float64(height), ctx)
+	default:
+		panic(fmt.Sprint("flex: bad direction ", f.Attrs.Direction))
+	}
+}
+
+// crossConstraints returns the min and max cross size of the item c.
+// ctx is the length context of f.
+func (f *View) crossConstraints(c *View, width, height int, ctx lengthContext) (float64, float64) {
+	ctx = ctx.child(c)
+	switch f.Attrs.Direction {
+	case Row:
+		return c.heightConstraints(float64(height), ctx)
+	case Column:
+		return c.widthConstraints(float64(width), ctx)
+	default:
+		panic(fmt.Sprint("flex: bad direction ", f.Attrs.Direction))
+	}
+}
+
 func (f *View) isCrossSizeFixed(v *View) bool {
 	switch f.Attrs.Direction {
 	case Row:
@@ -763,16 +947,40 @@ func (f *View) crossSize(x, y int) int {
 	}
 }
 
+// mainGap returns the gap between items along the main axis.
+func (f *View) mainGap() float64 {
+	switch f.Attrs.Direction {
+	case Row:
+		return float64(f.Attrs.ColumnGap)
+	case Column:
+		return float64(f.Attrs.RowGap)
+	default:
+		panic(fmt.Sprint("flex: bad direction ", f.Attrs.Direction))
+	}
+}
+
+// crossGap returns the gap between flex lines.
+func (f *View) crossGap() float64 {
+	switch f.Attrs.Direction {
+	case Row:
+		return float64(f.Attrs.RowGap)
+	case Column:
+		return float64(f.Attrs.ColumnGap)
+	default:
+		panic(fmt.Sprint("flex: bad direction ", f.Attrs.Direction))
+	}
+}
+
 func (f *View) mainMargin(c *View) []float64 {
 	switch f.Attrs.Direction {
 	case Row:
 		return []float64{
-			float64(c.Attrs.MarginLeft),
-			float64(c.Attrs.MarginRight)}
+			float64(c.used.MarginLeft),
+			float64(c.used.MarginRight)}
 	case Column:
 		return []float64{
-			float64(c.Attrs.MarginTop),
-			float64(c.Attrs.MarginBottom)}
+			float64(c.used.MarginTop),
+			float64(c.used.MarginBottom)}
 	default:
 		panic("unreachable")
 	}
@@ -782,27 +990,135 @@ func (f *View) crossMargin(c *View) []float64 {
 	switch f.Attrs.Direction {
 	case Row:
 		return []float64{
-			float64(c.Attrs.MarginTop),
-			float64(c.Attrs.MarginBottom)}
+			float64(c.used.MarginTop),
+			float64(c.used.MarginBottom)}
 	case Column:
 		return []float64{
-			float64(c.Attrs.MarginLeft),
-			float64(c.Attrs.MarginRight)}
+			float64(c.used.MarginLeft),
+			float64(c.used.MarginRight)}
 	default:
 		panic("unreachable")
 	}
 }
 
-func (f *View) flexBaseSize(c *View) int {
-	w := c.Attrs.Width
-	if w == 0 {
-		w = c.calculatedWidth
+// layoutAbsolute positions the absolutely positioned child c in the padding box of f
+// and lays out its children. As in CSS, a child with both left and right insets and
+// no width is stretched between them, a child with neither stays at its static
+// position, and a child without size is sized to its content. ctx is the length context of f.
+func (f *View) layoutAbsolute(c *View, contentOrigin image.Point, ctx lengthContext) {
+	ctx = ctx.child(c)
+	cb := f.frame
+	u := &c.used
+
+	w, h := u.Width, u.Height
+	if w == 0 && u.WidthInPct != 0 {
+		w = round(float64(cb.Dx()) * u.WidthInPct / 100)
+	}
+	if h == 0 && u.HeightInPct != 0 {
+		h = round(float64(cb.Dy()) * u.HeightInPct / 100)
+	}
+	stretchX := w == 0 && u.Left != nil && u.Right != nil
+	stretchY := h == 0 && u.Top != nil && u.Bottom != nil
+	if stretchX {
+		w = cb.Dx() - *u.Left - *u.Right - u.MarginLeft - u.MarginRight
+	}
+	if stretchY {
+		h = cb.Dy() - *u.Top - *u.Bottom - u.MarginTop - u.MarginBottom
+	}
+	if w == 0 || h == 0 {
+		// Shrink to fit the content.
+		if c.isMeasured() {
+			mode := MeasureModeAtMost
+			availableW := cb.Dx() - u.MarginLeft - u.MarginRight
+			if w != 0 {
+				availableW, mode = w, MeasureModeExactly
+			}
+			c.measure(availableW, cb.Dy()-u.MarginTop-u.MarginBottom, mode)
+		} else {
+			c.layoutIfNeeded(ctx)
+		}
+		if w == 0 {
+			w = c.calculatedWidth
+		}
+		if h == 0 {
+			h = c.calculatedHeight
+		}
+	}
+	minW, maxW := c.widthConstraints(float64(cb.Dx()), ctx)
+	minH, maxH := c.heightConstraints(float64(cb.Dy()), ctx)
+	w = round(clamp(float64(w), minW, maxW))
+	h = round(clamp(float64(h), minH, maxH))
+
+	x := contentOrigin.X + u.MarginLeft
+	if u.Left != nil {
+		x = cb.Min.X + *u.Left + u.MarginLeft
+	} else if u.Right != nil {
+		x = cb.Max.X - *u.Right - u.MarginRight - w
+	}
+	y := contentOrigin.Y + u.MarginTop
+	if u.Top != nil {
+		y = cb.Min.Y + *u.Top + u.MarginTop
+	} else if u.Bottom != nil {
+		y = cb.Max.Y - *u.Bottom - u.MarginBottom - h
+	}
+
+	c.bounds = image.Rect(x, y, x+w, y+h).Sub(f.frame.Min)
+	c.setFrame(c.bounds.Add(f.frame.Min))
+	c.layoutIfNeeded(ctx)
+}
+
+// measureItem asks the handler of the item for the size of its content,
+// which is used as the content size of the item in place of the size of its children.
+func (f *View) measureItem(c *View, width, height int) {
+	availableW := width - c.used.MarginLeft - c.used.MarginRight
+	availableH := height - c.used.MarginTop - c.used.MarginBottom
+	// Auto sized containers don't know their width until their items are measured.
+	definite := f.isWidthFixed() || !f.hasParent
+	mode := MeasureModeAtMost
+	switch {
+	case c.used.Width != 0:
+		availableW, mode = c.used.Width, MeasureModeExactly
+	case c.used.WidthInPct != 0:
+		availableW, mode = int(float64(width)*c.used.WidthInPct/100), MeasureModeExactly
+	case !definite:
+		mode = MeasureModeUndefined
+	case f.Attrs.Direction == Column && f.alignItem(c) == AlignItemStretch:
+		mode = MeasureModeExactly
+	}
+	if c.used.Height != 0 {
+		availableH = c.used.Height
+	}
+	c.measure(availableW, availableH, mode)
+}
+
+// flexBaseSize determines the flex base size of the item (§9.2.3).
+// ctx is the length context of f.
+func (f *View) flexBaseSize(c *View, containerMainSize float64, ctx lengthContext) float64 {
+	ctx = ctx.child(c)
+	// A. If the item has a definite flex basis, that is the flex base size.
+	if v, ok := c.Attrs.Basis.resolve(containerMainSize, ctx); ok {
+		if v < 0 {
+			return 0
+		}
+		return v
+	}
+	// E. Otherwise the item is sized to its content. For flex-basis: auto,
+	// the main size of the item is used instead when it is specified.
+	if c.Attrs.Basis.Unit == LengthContent {
+		return float64(f.mainSize(c.calculatedWidth, c.calculatedHeight))
+	}
+	return float64(f.mainSize(c.width(), c.height()))
+}
+
+// clamp clamps v between min and max. If min is greater than max, min wins.
+func clamp(v, min, max float64) float64 {
+	if v > max {
+		v = max
 	}
-	h := c.Attrs.Height
-	if h == 0 {
-		h = c.calculatedHeight
+	if v < min {
+		v = min
 	}
-	return f.mainSize(w, h)
+	return v
 }
 
 func round(f float64) int {
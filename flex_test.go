@@ -15,8 +15,8 @@ func TestFlexAlignments(t *testing.T) {
 	w, h := 100, 100
 	child := &View{
 		Attrs: ViewAttrs{
-			Width:  50,
-			Height: 50,
+			Width:  Px(50),
+			Height: Px(50),
 		},
 	}
 
@@ -29,8 +29,8 @@ func TestFlexAlignments(t *testing.T) {
 			name: "Column - Center, Center",
 			flex: &View{
 				Attrs: ViewAttrs{
-					Width:      w,
-					Height:     h,
+					Width:      Px(float64(w)),
+					Height:     Px(float64(h)),
 					Direction:  Column,
 					Justify:    JustifyCenter,
 					AlignItems: AlignItemCenter,
@@ -42,8 +42,8 @@ func TestFlexAlignments(t *testing.T) {
 			name: "Column - Start, End",
 			flex: &View{
 				Attrs: ViewAttrs{
-					Width:      w,
-					Height:     h,
+					Width:      Px(float64(w)),
+					Height:     Px(float64(h)),
 					Direction:  Column,
 					Justify:    JustifyStart,
 					AlignItems: AlignItemEnd,
@@ -55,8 +55,8 @@ func TestFlexAlignments(t *testing.T) {
 			name: "Row - Center, Center",
 			flex: &View{
 				Attrs: ViewAttrs{
-					Width:      w,
-					Height:     h,
+					Width:      Px(float64(w)),
+					Height:     Px(float64(h)),
 					Direction:  Row,
 					Justify:    JustifyCenter,
 					AlignItems: AlignItemCenter,
@@ -68,8 +68,8 @@ func TestFlexAlignments(t *testing.T) {
 			name: "Row - End, Start",
 			flex: &View{
 				Attrs: ViewAttrs{
-					Width:      w,
-					Height:     h,
+					Width:      Px(float64(w)),
+					Height:     Px(float64(h)),
 					Direction:  Row,
 					Justify:    JustifyEnd,
 					AlignItems: AlignItemStart,
@@ -89,8 +89,8 @@ func TestFlexAlignments(t *testing.T) {
 func TestFlexWrap(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      200,
-			Height:     200,
+			Width:      Px(200),
+			Height:     Px(200),
 			Direction:  Row,
 			Justify:    JustifyStart,
 			AlignItems: AlignItemStart,
@@ -101,7 +101,7 @@ func TestFlexWrap(t *testing.T) {
 	mocks := [3]*mockHandler{}
 	for i := 0; i < 3; i++ {
 		mocks[i] = NewMockHandler()
-		flex.AddChild(&View{Attrs: ViewAttrs{Width: 100, Height: 100}, Handler: mocks[i].ViewHandler})
+		flex.AddChild(&View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}, Handler: mocks[i].ViewHandler})
 	}
 
 	flex.Update()
@@ -130,10 +130,10 @@ func TestAbsolutePos(t *testing.T) {
 	left, top := 20, 30
 	f1 := &View{
 		Attrs: ViewAttrs{
-			Width:      100,
-			Height:     200,
-			Left:       left,
-			Top:        top,
+			Width:      Px(100),
+			Height:     Px(200),
+			Left:       Px(float64(left)),
+			Top:        Px(float64(top)),
 			Position:   PositionAbsolute,
 			Direction:  Row,
 			Justify:    JustifyCenter,
@@ -144,7 +144,7 @@ func TestAbsolutePos(t *testing.T) {
 
 	mock := NewMockHandler()
 
-	f2 := &View{Attrs: ViewAttrs{Width: 30, Height: 40}, Handler: mock.ViewHandler}
+	f2 := &View{Attrs: ViewAttrs{Width: Px(30), Height: Px(40)}, Handler: mock.ViewHandler}
 	f1.AddChild(f2)
 	f1.Update()
 	f1.Draw(nil)
@@ -177,8 +177,8 @@ func TestAbsolutePos(t *testing.T) {
 func TestAbsolutePosRightBottom(t *testing.T) {
 	mock := NewMockHandler()
 
-	f1 := (&View{Attrs: ViewAttrs{Width: 100, Height: 100}}).addChild(
-		&View{Attrs: ViewAttrs{Position: PositionAbsolute, Width: 10, Height: 10, Right: Int(40), Bottom: Int(50)}, Handler: mock.ViewHandler},
+	f1 := (&View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}).addChild(
+		&View{Attrs: ViewAttrs{Position: PositionAbsolute, Width: Px(10), Height: Px(10), Right: Px(40), Bottom: Px(50)}, Handler: mock.ViewHandler},
 	)
 
 	f1.Update()
@@ -190,8 +190,8 @@ func TestAbsolutePosRightBottom(t *testing.T) {
 func TestAbsolutePosNested(t *testing.T) {
 	f1 := &View{
 		Attrs: ViewAttrs{
-			Width:      150,
-			Height:     200,
+			Width:      Px(150),
+			Height:     Px(200),
 			Direction:  Row,
 			Justify:    JustifyStart,
 			AlignItems: AlignItemCenter,
@@ -201,10 +201,10 @@ func TestAbsolutePosNested(t *testing.T) {
 
 	f2 := &View{
 		Attrs: ViewAttrs{
-			Width:      50,
-			Height:     150,
-			Left:       100,
-			Top:        50,
+			Width:      Px(50),
+			Height:     Px(150),
+			Left:       Px(100),
+			Top:        Px(50),
 			Position:   PositionAbsolute,
 			Direction:  Row,
 			Justify:    JustifyCenter,
@@ -219,8 +219,8 @@ func TestAbsolutePosNested(t *testing.T) {
 
 	f2.AddChild(&View{
 		Attrs: ViewAttrs{
-			Width:  30,
-			Height: 40,
+			Width:  Px(30),
+			Height: Px(40),
 		},
 		Handler: mock.ViewHandler,
 	})
@@ -254,24 +254,192 @@ func TestAbsolutePosNested(t *testing.T) {
 	require.Equal(t, image.Rect(x, y, x+w, y+h), mock.Frame)
 }
 
+func TestAbsoluteInsets(t *testing.T) {
+	var tests = []struct {
+		Name string
+		Item ViewAttrs
+		Want image.Rectangle
+	}{
+		{
+			Name: "stretch between left and right",
+			Item: ViewAttrs{Left: Px(10), Right: Px(20), Top: Px(5), Height: Px(10)},
+			Want: image.Rect(10, 5, 80, 15),
+		},
+		{
+			Name: "stretch between top and bottom with margins",
+			Item: ViewAttrs{Top: Px(10), Bottom: Px(10), Width: Px(10), MarginTop: Px(5), MarginBottom: Px(5)},
+			Want: image.Rect(0, 15, 10, 85),
+		},
+		{
+			Name: "zero insets",
+			Item: ViewAttrs{
+				Right:  Px(0),
+				Bottom: Px(0),
+				Left:   Px(0), Top: Px(0),
+			},
+			Want: image.Rect(0, 0, 100, 100),
+		},
+		{
+			Name: "width wins over right",
+			Item: ViewAttrs{Left: Px(10), Right: Px(10), Width: Px(30), Height: Px(10)},
+			Want: image.Rect(10, 0, 40, 10),
+		},
+		{
+			Name: "percent",
+			Item: ViewAttrs{
+				Height: Px(10),
+				Left:   Pct(50), Top: Pct(25), Width: Pct(20),
+			},
+			Want: image.Rect(50, 25, 70, 35),
+		},
+		{
+			Name: "max width",
+			Item: ViewAttrs{
+				Right:    Px(0),
+				Height:   Px(10),
+				MaxWidth: Px(40),
+				Left:     Px(0),
+			},
+			Want: image.Rect(0, 0, 40, 10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mock := NewMockHandler()
+			tt.Item.Position = PositionAbsolute
+			flex := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+			flex.AddChild(&View{Attrs: tt.Item, Handler: mock.ViewHandler})
+			flex.Update()
+			flex.Draw(nil)
+
+			assert.Equal(t, tt.Want, mock.Frame)
+		})
+	}
+}
+
+func TestAbsoluteContentSize(t *testing.T) {
+	mock1, mock2 := NewMockHandler(), NewMockHandler()
+	flex := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	flex.AddChild(
+		(&View{
+			Attrs: ViewAttrs{
+				Position:    PositionAbsolute,
+				Right:       Px(10),
+				Bottom:      Px(10),
+				PaddingLeft: Px(5),
+			},
+			Handler: mock1.ViewHandler,
+		}).AddChild(&View{
+			Attrs:   ViewAttrs{Width: Px(30), Height: Px(20)},
+			Handler: mock2.ViewHandler,
+		}),
+	)
+	flex.Update()
+	flex.Draw(nil)
+
+	assert.Equal(t, image.Rect(55, 70, 90, 90), mock1.Frame)
+	assert.Equal(t, image.Rect(60, 70, 90, 90), mock2.Frame)
+}
+
+func TestAbsoluteStretchedChildren(t *testing.T) {
+	mock := NewMockHandler()
+	flex := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	flex.AddChild(
+		(&View{
+			Attrs: ViewAttrs{
+				Position:   PositionAbsolute,
+				Left:       Px(20),
+				Right:      Px(20),
+				Top:        Px(20),
+				Bottom:     Px(20),
+				Justify:    JustifyCenter,
+				AlignItems: AlignItemCenter,
+			},
+		}).AddChild(&View{
+			Attrs:   ViewAttrs{Width: Px(10), Height: Px(10)},
+			Handler: mock.ViewHandler,
+		}),
+	)
+	flex.Update()
+
+	// The children are laid out against the resolved size before drawing.
+	assert.Equal(t, image.Rect(45, 45, 55, 55), flex.children[0].children[0].frame)
+	flex.Draw(nil)
+	assert.Equal(t, image.Rect(45, 45, 55, 55), mock.Frame)
+}
+
+func TestAbsoluteMovesWithParent(t *testing.T) {
+	mock := NewMockHandler()
+	container := &View{Attrs: ViewAttrs{Width: Px(50), Height: Px(50)}}
+	container.AddChild(&View{
+		Attrs: ViewAttrs{
+			Position: PositionAbsolute,
+			Left:     Px(10),
+			Top:      Px(10),
+			Width:    Px(10),
+			Height:   Px(10),
+		},
+		Handler: mock.ViewHandler,
+	})
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(200), AlignItems: AlignItemStart}}
+	root.AddChild(container)
+	root.Update()
+	root.Draw(nil)
+	require.Equal(t, image.Rect(10, 10, 20, 20), mock.Frame)
+
+	container.SetMarginLeft(50)
+	root.Update()
+	root.Draw(nil)
+	require.Equal(t, image.Rect(60, 10, 70, 20), mock.Frame)
+
+	root.handleMouseButtonLeftPressed(nil, 65, 15)
+	require.True(t, mock.IsClickPressed)
+}
+
+func TestRelativePos(t *testing.T) {
+	mock1, mock2, mock3 := NewMockHandler(), NewMockHandler(), NewMockHandler()
+	flex := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart}}
+	flex.AddChild(
+		&View{
+			Attrs:   ViewAttrs{Width: Px(20), Height: Px(20), Position: PositionRelative, Left: Px(5), Top: Px(7)},
+			Handler: mock1.ViewHandler,
+		},
+		&View{
+			Attrs:   ViewAttrs{Width: Px(20), Height: Px(20)},
+			Handler: mock2.ViewHandler,
+		},
+		&View{
+			Attrs:   ViewAttrs{Width: Px(20), Height: Px(20), Position: PositionRelative, Right: Px(5), Bottom: Px(5)},
+			Handler: mock3.ViewHandler,
+		},
+	)
+	flex.Update()
+	flex.Draw(nil)
+
+	assert.Equal(t, image.Rect(5, 7, 25, 27), mock1.Frame)
+	assert.Equal(t, image.Rect(20, 0, 40, 20), mock2.Frame)
+	assert.Equal(t, image.Rect(35, -5, 55, 15), mock3.Frame)
+}
+
 func TestNesting(t *testing.T) {
 	parent := &View{
 		Attrs: ViewAttrs{
-			Width:      300,
-			Height:     500,
+			Width:      Px(300),
+			Height:     Px(500),
 			Direction:  Column,
 			Justify:    JustifyCenter,
 			AlignItems: AlignItemCenter,
-			Left:       100,
-			Top:        50,
+			Left:       Px(100),
+			Top:        Px(50),
 			Position:   PositionAbsolute,
 		},
 	}
 
 	child := &View{
 		Attrs: ViewAttrs{
-			Width:      100,
-			Height:     200,
+			Width:      Px(100),
+			Height:     Px(200),
 			Direction:  Column,
 			Justify:    JustifyEnd,
 			AlignItems: AlignItemEnd,
@@ -284,8 +452,8 @@ func TestNesting(t *testing.T) {
 
 	child.AddChild(&View{
 		Attrs: ViewAttrs{
-			Width:  30,
-			Height: 40,
+			Width:  Px(30),
+			Height: Px(40),
 		},
 		Handler: item.ViewHandler,
 	})
@@ -335,8 +503,8 @@ func TestMargin(t *testing.T) {
 		{
 			Flex: &View{
 				Attrs: ViewAttrs{
-					Width:      100,
-					Height:     100,
+					Width:      Px(100),
+					Height:     Px(100),
 					Direction:  Row,
 					Justify:    JustifyCenter,
 					AlignItems: AlignItemCenter,
@@ -344,9 +512,9 @@ func TestMargin(t *testing.T) {
 			},
 			View: &View{
 				Attrs: ViewAttrs{
-					Width:      50,
-					Height:     50,
-					MarginLeft: 20,
+					Width:      Px(50),
+					Height:     Px(50),
+					MarginLeft: Px(20),
 				},
 			},
 			Want: image.Rect(25+10, 25, 75+10, 75),
@@ -354,8 +522,8 @@ func TestMargin(t *testing.T) {
 		{
 			Flex: &View{
 				Attrs: ViewAttrs{
-					Width:      100,
-					Height:     100,
+					Width:      Px(100),
+					Height:     Px(100),
 					Direction:  Column,
 					Justify:    JustifyCenter,
 					AlignItems: AlignItemCenter,
@@ -363,9 +531,9 @@ func TestMargin(t *testing.T) {
 			},
 			View: &View{
 				Attrs: ViewAttrs{
-					Width:     50,
-					Height:    50,
-					MarginTop: 20,
+					Width:     Px(50),
+					Height:    Px(50),
+					MarginTop: Px(20),
 				},
 			},
 			Want: image.Rect(25, 25+10, 75, 75+10),
@@ -373,8 +541,8 @@ func TestMargin(t *testing.T) {
 		{
 			Flex: &View{
 				Attrs: ViewAttrs{
-					Width:      100,
-					Height:     100,
+					Width:      Px(100),
+					Height:     Px(100),
 					Direction:  Row,
 					Justify:    JustifyEnd,
 					AlignItems: AlignItemStart,
@@ -382,10 +550,10 @@ func TestMargin(t *testing.T) {
 			},
 			View: &View{
 				Attrs: ViewAttrs{
-					Width:       50,
-					Height:      50,
-					MarginTop:   10,
-					MarginRight: 10,
+					Width:       Px(50),
+					Height:      Px(50),
+					MarginTop:   Px(10),
+					MarginRight: Px(10),
 				},
 			},
 			Want: image.Rect(40, 10, 90, 60),
@@ -393,8 +561,8 @@ func TestMargin(t *testing.T) {
 		{
 			Flex: &View{
 				Attrs: ViewAttrs{
-					Width:      100,
-					Height:     100,
+					Width:      Px(100),
+					Height:     Px(100),
 					Direction:  Column,
 					Justify:    JustifyEnd,
 					AlignItems: AlignItemEnd,
@@ -402,10 +570,10 @@ func TestMargin(t *testing.T) {
 			},
 			View: &View{
 				Attrs: ViewAttrs{
-					Width:        50,
-					Height:       50,
-					MarginRight:  10,
-					MarginBottom: 10,
+					Width:        Px(50),
+					Height:       Px(50),
+					MarginRight:  Px(10),
+					MarginBottom: Px(10),
 				},
 			},
 			Want: image.Rect(40, 40, 90, 90),
@@ -426,8 +594,8 @@ func TestMargin(t *testing.T) {
 func TestMarginedItemPosition(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      200,
-			Height:     200,
+			Width:      Px(200),
+			Height:     Px(200),
 			Direction:  Column,
 			Justify:    JustifyStart,
 			AlignItems: AlignItemCenter,
@@ -441,9 +609,9 @@ func TestMarginedItemPosition(t *testing.T) {
 
 	view1 := &View{
 		Attrs: ViewAttrs{
-			Width:      200,
-			Height:     50,
-			MarginTop:  10,
+			Width:      Px(200),
+			Height:     Px(50),
+			MarginTop:  Px(10),
 			Direction:  Column,
 			Justify:    JustifyStart,
 			AlignItems: AlignItemCenter,
@@ -454,8 +622,8 @@ func TestMarginedItemPosition(t *testing.T) {
 
 	view2 := &View{
 		Attrs: ViewAttrs{
-			Width:  200,
-			Height: 10,
+			Width:  Px(200),
+			Height: Px(10),
 		},
 		Handler: mocks[1].ViewHandler,
 	}
@@ -471,8 +639,8 @@ func TestMarginedItemPosition(t *testing.T) {
 func TestMultiMarginedWrapRowItems(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:        200,
-			Height:       200,
+			Width:        Px(200),
+			Height:       Px(200),
 			Direction:    Row,
 			Justify:      JustifyStart,
 			AlignItems:   AlignItemCenter,
@@ -487,10 +655,10 @@ func TestMultiMarginedWrapRowItems(t *testing.T) {
 		mocks[i] = NewMockHandler()
 		v := &View{
 			Attrs: ViewAttrs{
-				Width:      85,
-				Height:     85,
-				MarginTop:  10,
-				MarginLeft: 10,
+				Width:      Px(85),
+				Height:     Px(85),
+				MarginTop:  Px(10),
+				MarginLeft: Px(10),
 			},
 		}
 		v.Handler = mocks[i].ViewHandler
@@ -511,8 +679,8 @@ func TestRemoveChild(t *testing.T) {
 
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      w,
-			Height:     h,
+			Width:      Px(float64(w)),
+			Height:     Px(float64(h)),
 			Direction:  Row,
 			Justify:    JustifyCenter,
 			AlignItems: AlignItemCenter,
@@ -526,8 +694,8 @@ func TestRemoveChild(t *testing.T) {
 		mocks[i] = NewMockHandler()
 		views[i] = &View{
 			Attrs: ViewAttrs{
-				Width:  50,
-				Height: 50,
+				Width:  Px(50),
+				Height: Px(50),
 			},
 			Handler: mocks[i].ViewHandler,
 		}
@@ -550,8 +718,8 @@ func TestRemoveChild(t *testing.T) {
 func TestAutoExpanding(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			Direction:  Row,
 			Justify:    JustifyCenter,
 			AlignItems: AlignItemStretch,
@@ -581,8 +749,8 @@ func TestNestedChildrenGrow(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
 			ID:         "main",
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			Direction:  Row,
 			Justify:    JustifyCenter,
 			AlignItems: AlignItemStretch,
@@ -635,8 +803,8 @@ func TestNestedChildrenGrow(t *testing.T) {
 func TestNestedChildGrow(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			Direction:  Column,
 			AlignItems: AlignItemStretch,
 			Justify:    JustifyCenter,
@@ -675,8 +843,8 @@ func TestNestedChildGrow(t *testing.T) {
 func TestMerginWithChild(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			Direction:  Column,
 			AlignItems: AlignItemEnd,
 			Justify:    JustifyEnd,
@@ -689,8 +857,8 @@ func TestMerginWithChild(t *testing.T) {
 	flex.AddChild(
 		(&View{
 			Attrs: ViewAttrs{
-				MarginRight:  50,
-				MarginBottom: 100,
+				MarginRight:  Px(50),
+				MarginBottom: Px(100),
 				Direction:    Column,
 				AlignItems:   AlignItemEnd,
 				Justify:      JustifyEnd,
@@ -700,8 +868,8 @@ func TestMerginWithChild(t *testing.T) {
 			&View{
 				Attrs: ViewAttrs{
 					Grow:   1,
-					Width:  100,
-					Height: 100,
+					Width:  Px(100),
+					Height: Px(100),
 				},
 				Handler: mock2.ViewHandler,
 			},
@@ -718,8 +886,8 @@ func TestMerginWithChild(t *testing.T) {
 func TestStretchAndMargin(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			AlignItems: AlignItemStretch,
 		},
 	}
@@ -730,8 +898,8 @@ func TestStretchAndMargin(t *testing.T) {
 	flex.AddChild(
 		(&View{
 			Attrs: ViewAttrs{
-				MarginRight:  50,
-				MarginBottom: 100,
+				MarginRight:  Px(50),
+				MarginBottom: Px(100),
 				Grow:         1,
 				Direction:    Column,
 				AlignItems:   AlignItemEnd,
@@ -741,8 +909,8 @@ func TestStretchAndMargin(t *testing.T) {
 		}).AddChild(
 			&View{
 				Attrs: ViewAttrs{
-					Width:  100,
-					Height: 100,
+					Width:  Px(100),
+					Height: Px(100),
 				},
 				Handler: mock2.ViewHandler,
 			},
@@ -759,8 +927,8 @@ func TestStretchAndMargin(t *testing.T) {
 func TestStretchAndMarginItems(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			AlignItems: AlignItemStretch,
 		},
 	}
@@ -771,14 +939,14 @@ func TestStretchAndMarginItems(t *testing.T) {
 	flex.AddChild(
 		&View{
 			Attrs: ViewAttrs{
-				MarginRight: 50,
+				MarginRight: Px(50),
 				Grow:        1,
 			},
 			Handler: mock1.ViewHandler,
 		},
 		&View{
 			Attrs: ViewAttrs{
-				MarginLeft: 50,
+				MarginLeft: Px(50),
 				Grow:       1,
 			},
 			Handler: mock2.ViewHandler,
@@ -795,8 +963,8 @@ func TestStretchAndMarginItems(t *testing.T) {
 func TestStretchAndMarginItemsMain(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			AlignItems: AlignItemStretch,
 			Wrap:       WrapNormal,
 			Direction:  Column,
@@ -809,16 +977,16 @@ func TestStretchAndMarginItemsMain(t *testing.T) {
 	flex.AddChild(
 		&View{
 			Attrs: ViewAttrs{
-				Width:        1000,
-				MarginBottom: 50,
+				Width:        Px(1000),
+				MarginBottom: Px(50),
 				Grow:         1,
 			},
 			Handler: mock1.ViewHandler,
 		},
 		&View{
 			Attrs: ViewAttrs{
-				Width:        1000,
-				MarginBottom: 50,
+				Width:        Px(1000),
+				MarginBottom: Px(50),
 				Grow:         1,
 			},
 			Handler: mock2.ViewHandler,
@@ -835,8 +1003,8 @@ func TestStretchAndMarginItemsMain(t *testing.T) {
 func TestStretchAndMarginItemsCross(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:        1000,
-			Height:       1000,
+			Width:        Px(1000),
+			Height:       Px(1000),
 			AlignItems:   AlignItemStretch,
 			AlignContent: AlignContentStretch,
 			Wrap:         WrapNormal,
@@ -850,16 +1018,16 @@ func TestStretchAndMarginItemsCross(t *testing.T) {
 	flex.AddChild(
 		&View{
 			Attrs: ViewAttrs{
-				Width:        1000,
-				MarginBottom: 50,
+				Width:        Px(1000),
+				MarginBottom: Px(50),
 				Grow:         1,
 			},
 			Handler: mock1.ViewHandler,
 		},
 		&View{
 			Attrs: ViewAttrs{
-				Width:        1000,
-				MarginBottom: 50,
+				Width:        Px(1000),
+				MarginBottom: Px(50),
 				Grow:         1,
 			},
 			Handler: mock2.ViewHandler,
@@ -876,8 +1044,8 @@ func TestStretchAndMarginItemsCross(t *testing.T) {
 func TestNestedFlex(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			Justify:    JustifyCenter,
 			AlignItems: AlignItemCenter,
 		},
@@ -890,8 +1058,8 @@ func TestNestedFlex(t *testing.T) {
 	flex.AddChild(
 		(&View{
 			Attrs: ViewAttrs{
-				Width:      800,
-				Height:     800,
+				Width:      Px(800),
+				Height:     Px(800),
 				Justify:    JustifyCenter,
 				AlignItems: AlignItemCenter,
 			},
@@ -899,8 +1067,8 @@ func TestNestedFlex(t *testing.T) {
 		}).AddChild(
 			(&View{
 				Attrs: ViewAttrs{
-					Width:      100,
-					Height:     100,
+					Width:      Px(100),
+					Height:     Px(100),
 					Justify:    JustifyCenter,
 					AlignItems: AlignItemCenter,
 				},
@@ -908,8 +1076,8 @@ func TestNestedFlex(t *testing.T) {
 			}).AddChild(
 				&View{
 					Attrs: ViewAttrs{
-						Width:  10,
-						Height: 10,
+						Width:  Px(10),
+						Height: Px(10),
 					},
 					Handler: mock3.ViewHandler,
 				},
@@ -928,8 +1096,8 @@ func TestNestedFlex(t *testing.T) {
 func TestAbsoluteViewChildren(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			Justify:    JustifyCenter,
 			AlignItems: AlignItemCenter,
 		},
@@ -942,23 +1110,23 @@ func TestAbsoluteViewChildren(t *testing.T) {
 	flex.AddChild(
 		(&View{
 			Attrs: ViewAttrs{
-				Width:  800,
-				Height: 800,
+				Width:  Px(800),
+				Height: Px(800),
 			},
 			Handler: mock1.ViewHandler,
 		}).AddChild(
 			(&View{
 				Attrs: ViewAttrs{
-					Width:    100,
-					Height:   100,
+					Width:    Px(100),
+					Height:   Px(100),
 					Position: PositionAbsolute,
 				},
 				Handler: mock2.ViewHandler,
 			}).AddChild(
 				&View{
 					Attrs: ViewAttrs{
-						Width:    10,
-						Height:   10,
+						Width:    Px(10),
+						Height:   Px(10),
 						Position: PositionAbsolute,
 					},
 					Handler: mock3.ViewHandler,
@@ -978,8 +1146,8 @@ func TestAbsoluteViewChildren(t *testing.T) {
 func TestAutoHeightCalculation(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      1000,
-			Height:     1000,
+			Width:      Px(1000),
+			Height:     Px(1000),
 			Grow:       0,
 			Shrink:     0,
 			AlignItems: AlignItemStart,
@@ -996,23 +1164,23 @@ func TestAutoHeightCalculation(t *testing.T) {
 			Direction: Column,
 			Grow:      0,
 			Shrink:    0,
-			Width:     100,
+			Width:     Px(100),
 		},
 		Handler: mock1.ViewHandler,
 	}
 
 	firstRow.AddChild(&View{
 		Attrs: ViewAttrs{
-			Width:  100,
-			Height: 100,
+			Width:  Px(100),
+			Height: Px(100),
 		},
 	})
 
 	secondRow := &View{
 		Attrs: ViewAttrs{
 			Direction: Row,
-			Width:     200,
-			Height:    200,
+			Width:     Px(200),
+			Height:    Px(200),
 			Grow:      0,
 			Shrink:    0,
 		},
@@ -1034,8 +1202,8 @@ func TestAutoHeightCalculation(t *testing.T) {
 func TestWidthInPctRow(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      500,
-			Height:     500,
+			Width:      Px(500),
+			Height:     Px(500),
 			Direction:  Row,
 			Justify:    JustifyEnd,
 			AlignItems: AlignItemEnd,
@@ -1048,14 +1216,14 @@ func TestWidthInPctRow(t *testing.T) {
 		&View{
 			Attrs: ViewAttrs{
 				WidthInPct: 100,
-				Height:     100,
+				Height:     Px(100),
 			},
 			Handler: mock.ViewHandler,
 		},
 		&View{
 			Attrs: ViewAttrs{
-				Width:  50,
-				Height: 100,
+				Width:  Px(50),
+				Height: Px(100),
 			},
 		},
 	)
@@ -1069,8 +1237,8 @@ func TestWidthInPctRow(t *testing.T) {
 func TestWidthInPctCol(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      500,
-			Height:     500,
+			Width:      Px(500),
+			Height:     Px(500),
 			Direction:  Column,
 			Justify:    JustifyEnd,
 			AlignItems: AlignItemEnd,
@@ -1082,14 +1250,14 @@ func TestWidthInPctCol(t *testing.T) {
 	flex.AddChild(
 		&View{
 			Attrs: ViewAttrs{
-				Width:  50,
-				Height: 100,
+				Width:  Px(50),
+				Height: Px(100),
 			},
 		},
 		&View{
 			Attrs: ViewAttrs{
 				WidthInPct: 100,
-				Height:     100,
+				Height:     Px(100),
 			},
 			Handler: mock.ViewHandler,
 		},
@@ -1104,8 +1272,8 @@ func TestWidthInPctCol(t *testing.T) {
 func TestHeightInPctRow(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      500,
-			Height:     500,
+			Width:      Px(500),
+			Height:     Px(500),
 			Direction:  Row,
 			Justify:    JustifyEnd,
 			AlignItems: AlignItemEnd,
@@ -1117,15 +1285,15 @@ func TestHeightInPctRow(t *testing.T) {
 	flex.AddChild(
 		&View{
 			Attrs: ViewAttrs{
-				Width:       100,
+				Width:       Px(100),
 				HeightInPct: 100,
 			},
 			Handler: mock.ViewHandler,
 		},
 		&View{
 			Attrs: ViewAttrs{
-				Width:  50,
-				Height: 100,
+				Width:  Px(50),
+				Height: Px(100),
 			},
 		},
 	)
@@ -1139,8 +1307,8 @@ func TestHeightInPctRow(t *testing.T) {
 func TestHeightInPctCol(t *testing.T) {
 	flex := &View{
 		Attrs: ViewAttrs{
-			Width:      500,
-			Height:     500,
+			Width:      Px(500),
+			Height:     Px(500),
 			Direction:  Column,
 			Justify:    JustifyEnd,
 			AlignItems: AlignItemEnd,
@@ -1152,13 +1320,13 @@ func TestHeightInPctCol(t *testing.T) {
 	flex.AddChild(
 		&View{
 			Attrs: ViewAttrs{
-				Width:  50,
-				Height: 100,
+				Width:  Px(50),
+				Height: Px(100),
 			},
 		},
 		&View{
 			Attrs: ViewAttrs{
-				Width:       100,
+				Width:       Px(100),
 				HeightInPct: 100,
 			},
 			Handler: mock.ViewHandler,
@@ -1184,7 +1352,7 @@ func TestShrink(t *testing.T) {
 
 	palette := &View{
 		Attrs: ViewAttrs{
-			Width:     w,
+			Width:     Px(float64(w)),
 			Shrink:    1,
 			Direction: Column,
 			Justify:   JustifyCenter,
@@ -1196,8 +1364,8 @@ func TestShrink(t *testing.T) {
 	for i := 0; i < items; i++ {
 		bar := &View{
 			Attrs: ViewAttrs{
-				Width:  w,
-				Height: h,
+				Width:  Px(float64(w)),
+				Height: Px(float64(h)),
 			},
 		}
 		bar.AddTo(palette)
@@ -1209,6 +1377,882 @@ func TestShrink(t *testing.T) {
 	assert.Equal(t, image.Pt(w, h*items), mock.Frame.Size())
 }
 
+func TestShrinkIntrinsicSize(t *testing.T) {
+	// The intrinsic size of the container doesn't change the shrunk sizes of its items.
+	flex := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(10)}}
+	a := &View{Attrs: ViewAttrs{Width: Px(80), Height: Px(10), Shrink: 1}}
+	b := &View{Attrs: ViewAttrs{Width: Px(80), Height: Px(10), Shrink: 1}}
+	flex.AddChild(a, b)
+	flex.Update()
+
+	assert.Equal(t, image.Rect(0, 0, 50, 10), a.frame)
+	assert.Equal(t, image.Rect(50, 0, 100, 10), b.frame)
+	// Shrunk items contribute their shrunk sizes to the intrinsic size.
+	assert.Equal(t, 100, flex.calculatedWidth)
+}
+
+func TestPadding(t *testing.T) {
+	var tests = []struct {
+		Name string
+		Flex *View
+		View *View
+		Want image.Rectangle
+	}{
+		{
+			Name: "start",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:        Px(100),
+					Height:       Px(100),
+					PaddingLeft:  Px(10),
+					PaddingTop:   Px(20),
+					PaddingRight: Px(30),
+					Direction:    Row,
+					Justify:      JustifyStart,
+					AlignItems:   AlignItemStart,
+				},
+			},
+			View: &View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20)}},
+			Want: image.Rect(10, 20, 30, 40),
+		},
+		{
+			Name: "end",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:         Px(100),
+					Height:        Px(100),
+					PaddingRight:  Px(10),
+					PaddingBottom: Px(20),
+					Direction:     Column,
+					Justify:       JustifyEnd,
+					AlignItems:    AlignItemEnd,
+				},
+			},
+			View: &View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20)}},
+			Want: image.Rect(70, 60, 90, 80),
+		},
+		{
+			Name: "grow and stretch",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:         Px(100),
+					Height:        Px(100),
+					PaddingLeft:   Px(10),
+					PaddingTop:    Px(10),
+					PaddingRight:  Px(10),
+					PaddingBottom: Px(10),
+					Direction:     Row,
+					AlignItems:    AlignItemStretch,
+				},
+			},
+			View: &View{Attrs: ViewAttrs{Grow: 1}},
+			Want: image.Rect(10, 10, 90, 90),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.Equal(t, tt.Want, flexItemBounds(tt.Flex, tt.View))
+		})
+	}
+}
+
+func TestPaddingAutoSize(t *testing.T) {
+	root := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(500),
+			Height:     Px(500),
+			Direction:  Row,
+			Justify:    JustifyStart,
+			AlignItems: AlignItemStart,
+		},
+	}
+
+	mock1 := NewMockHandler()
+	mock2 := NewMockHandler()
+
+	root.AddChild(
+		(&View{
+			Attrs: ViewAttrs{
+				PaddingLeft:   Px(5),
+				PaddingTop:    Px(10),
+				PaddingRight:  Px(15),
+				PaddingBottom: Px(20),
+				Direction:     Row,
+				AlignItems:    AlignItemStart,
+			},
+			Handler: mock1.ViewHandler,
+		}).AddChild(
+			&View{
+				Attrs:   ViewAttrs{Width: Px(50), Height: Px(30)},
+				Handler: mock2.ViewHandler,
+			},
+		),
+	)
+
+	root.Update()
+	root.Draw(nil)
+
+	assert.Equal(t, image.Rect(0, 0, 70, 60), mock1.Frame)
+	assert.Equal(t, image.Rect(5, 10, 55, 40), mock2.Frame)
+}
+
+func TestGap(t *testing.T) {
+	var tests = []struct {
+		Name  string
+		Flex  *View
+		Items int
+		Item  ViewAttrs
+		Want  []image.Rectangle
+	}{
+		{
+			Name: "row",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:      Px(100),
+					Height:     Px(100),
+					ColumnGap:  10,
+					RowGap:     50,
+					Direction:  Row,
+					AlignItems: AlignItemStart,
+				},
+			},
+			Items: 3,
+			Item:  ViewAttrs{Width: Px(20), Height: Px(20)},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 20, 20),
+				image.Rect(30, 0, 50, 20),
+				image.Rect(60, 0, 80, 20),
+			},
+		},
+		{
+			Name: "column space-between",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:      Px(100),
+					Height:     Px(100),
+					RowGap:     10,
+					Direction:  Column,
+					Justify:    JustifySpaceBetween,
+					AlignItems: AlignItemStart,
+				},
+			},
+			Items: 3,
+			Item:  ViewAttrs{Width: Px(20), Height: Px(20)},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 20, 20),
+				image.Rect(0, 40, 20, 60),
+				image.Rect(0, 80, 20, 100),
+			},
+		},
+		{
+			Name: "grow",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:      Px(100),
+					Height:     Px(100),
+					ColumnGap:  20,
+					Direction:  Row,
+					AlignItems: AlignItemStart,
+				},
+			},
+			Items: 2,
+			Item:  ViewAttrs{Height: Px(20), Grow: 1},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 40, 20),
+				image.Rect(60, 0, 100, 20),
+			},
+		},
+		{
+			Name: "wrap",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:      Px(100),
+					Height:     Px(100),
+					ColumnGap:  30,
+					RowGap:     5,
+					Direction:  Row,
+					Wrap:       WrapNormal,
+					AlignItems: AlignItemStart,
+				},
+			},
+			Items: 3,
+			Item:  ViewAttrs{Width: Px(40), Height: Px(20)},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 40, 20),
+				image.Rect(0, 25, 40, 45),
+				image.Rect(0, 50, 40, 70),
+			},
+		},
+		{
+			Name: "wrap fits",
+			Flex: &View{
+				Attrs: ViewAttrs{
+					Width:      Px(100),
+					Height:     Px(100),
+					ColumnGap:  20,
+					RowGap:     10,
+					Direction:  Row,
+					Wrap:       WrapNormal,
+					AlignItems: AlignItemStart,
+				},
+			},
+			Items: 3,
+			Item:  ViewAttrs{Width: Px(40), Height: Px(20)},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 40, 20),
+				image.Rect(60, 0, 100, 20),
+				image.Rect(0, 30, 40, 50),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mocks := make([]*mockHandler, tt.Items)
+			for i := range mocks {
+				mocks[i] = NewMockHandler()
+				tt.Flex.AddChild(&View{Attrs: tt.Item, Handler: mocks[i].ViewHandler})
+			}
+			tt.Flex.Update()
+			tt.Flex.Draw(nil)
+
+			for i, want := range tt.Want {
+				assert.Equal(t, want, mocks[i].Frame)
+			}
+		})
+	}
+}
+
+func TestAlignSelf(t *testing.T) {
+	flex := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(100),
+			Height:     Px(100),
+			Direction:  Row,
+			AlignItems: AlignItemStart,
+		},
+	}
+	var tests = []struct {
+		AlignSelf FlexAlignSelf
+		Want      image.Rectangle
+	}{
+		{AlignSelfAuto, image.Rect(0, 0, 10, 20)},
+		{AlignSelfStart, image.Rect(10, 0, 20, 20)},
+		{AlignSelfEnd, image.Rect(20, 80, 30, 100)},
+		{AlignSelfCenter, image.Rect(30, 40, 40, 60)},
+		{AlignSelfStretch, image.Rect(40, 0, 50, 100)},
+	}
+	mocks := make([]*mockHandler, len(tests))
+	for i, tt := range tests {
+		mocks[i] = NewMockHandler()
+		height := 20
+		if tt.AlignSelf == AlignSelfStretch {
+			height = 0
+		}
+		flex.AddChild(&View{
+			Attrs:   ViewAttrs{Width: Px(10), Height: Px(float64(height)), AlignSelf: tt.AlignSelf},
+			Handler: mocks[i].ViewHandler,
+		})
+	}
+	flex.Update()
+	flex.Draw(nil)
+
+	for i, tt := range tests {
+		t.Run(tt.AlignSelf.String(), func(t *testing.T) {
+			assert.Equal(t, tt.Want, mocks[i].Frame)
+		})
+	}
+}
+
+func TestOrder(t *testing.T) {
+	flex := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(100),
+			Height:     Px(100),
+			Direction:  Row,
+			AlignItems: AlignItemStart,
+		},
+	}
+	orders := []int{1, -1, 0, 1}
+	views := make([]*View, len(orders))
+	mocks := make([]*mockHandler, len(orders))
+	for i, o := range orders {
+		mocks[i] = NewMockHandler()
+		views[i] = &View{
+			Attrs:   ViewAttrs{Width: Px(10), Height: Px(10), Order: o},
+			Handler: mocks[i].ViewHandler,
+		}
+		flex.AddChild(views[i])
+	}
+	flex.Update()
+	flex.Draw(nil)
+
+	assert.Equal(t, image.Rect(20, 0, 30, 10), mocks[0].Frame)
+	assert.Equal(t, image.Rect(0, 0, 10, 10), mocks[1].Frame)
+	assert.Equal(t, image.Rect(10, 0, 20, 10), mocks[2].Frame)
+	assert.Equal(t, image.Rect(30, 0, 40, 10), mocks[3].Frame)
+
+	views[1].SetOrder(2)
+	flex.Draw(nil)
+
+	assert.Equal(t, image.Rect(10, 0, 20, 10), mocks[0].Frame)
+	assert.Equal(t, image.Rect(30, 0, 40, 10), mocks[1].Frame)
+	assert.Equal(t, image.Rect(0, 0, 10, 10), mocks[2].Frame)
+	assert.Equal(t, image.Rect(20, 0, 30, 10), mocks[3].Frame)
+}
+
+func TestBasis(t *testing.T) {
+	var tests = []struct {
+		Name  string
+		Items []ViewAttrs
+		Want  []image.Rectangle
+	}{
+		{
+			Name: "px and grow",
+			Items: []ViewAttrs{
+				{Height: Px(10), Basis: Px(20), Grow: 1},
+				{Height: Px(10), Basis: Px(40), Grow: 1},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 40, 10),
+				image.Rect(40, 0, 100, 10),
+			},
+		},
+		{
+			Name: "overrides width",
+			Items: []ViewAttrs{
+				{Width: Px(50), Height: Px(10), Basis: Px(30)},
+				{Width: Px(50), Height: Px(10)},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 30, 10),
+				image.Rect(30, 0, 80, 10),
+			},
+		},
+		{
+			Name: "percent",
+			Items: []ViewAttrs{
+				{Height: Px(10), Basis: Pct(25)},
+				{Height: Px(10), Basis: Pct(50)},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 25, 10),
+				image.Rect(25, 0, 75, 10),
+			},
+		},
+		{
+			Name: "shrink",
+			Items: []ViewAttrs{
+				{Height: Px(10), Basis: Px(90), Shrink: 1},
+				{Height: Px(10), Basis: Px(30), Shrink: 1},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 75, 10),
+				image.Rect(75, 0, 100, 10),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			flex := &View{
+				Attrs: ViewAttrs{
+					Width:      Px(100),
+					Height:     Px(100),
+					Direction:  Row,
+					AlignItems: AlignItemStart,
+				},
+			}
+			mocks := make([]*mockHandler, len(tt.Items))
+			for i := range mocks {
+				mocks[i] = NewMockHandler()
+				flex.AddChild(&View{Attrs: tt.Items[i], Handler: mocks[i].ViewHandler})
+			}
+			flex.Update()
+			flex.Draw(nil)
+
+			for i, want := range tt.Want {
+				assert.Equal(t, want, mocks[i].Frame)
+			}
+		})
+	}
+}
+
+func TestBasisContent(t *testing.T) {
+	mock := NewMockHandler()
+	flex := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(100),
+			Height:     Px(100),
+			Direction:  Row,
+			AlignItems: AlignItemStart,
+		},
+	}
+	flex.AddChild(
+		(&View{
+			Attrs:   ViewAttrs{Width: Px(80), Basis: Content},
+			Handler: mock.ViewHandler,
+		}).AddChild(&View{Attrs: ViewAttrs{Width: Px(30), Height: Px(10)}}),
+	)
+	flex.Update()
+	flex.Draw(nil)
+
+	assert.Equal(t, image.Rect(0, 0, 30, 10), mock.Frame)
+}
+
+func TestMinMax(t *testing.T) {
+	var tests = []struct {
+		Name  string
+		Items []ViewAttrs
+		Want  []image.Rectangle
+	}{
+		{
+			Name: "max width redistributes grow",
+			Items: []ViewAttrs{
+				{Height: Px(10), Grow: 1, MaxWidth: Px(30)},
+				{Height: Px(10), Grow: 1},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 30, 10),
+				image.Rect(30, 0, 100, 10),
+			},
+		},
+		{
+			Name: "min width redistributes shrink",
+			Items: []ViewAttrs{
+				{Height: Px(10), Basis: Px(100), Shrink: 1, MinWidth: Px(80)},
+				{Height: Px(10), Basis: Px(100), Shrink: 1},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 80, 10),
+				image.Rect(80, 0, 100, 10),
+			},
+		},
+		{
+			Name: "percent",
+			Items: []ViewAttrs{
+				{Height: Px(10), Grow: 1, MaxWidth: Pct(25)},
+				{Width: Px(10), Height: Px(10), MinWidth: Pct(40)},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 25, 10),
+				image.Rect(25, 0, 65, 10),
+			},
+		},
+		{
+			Name: "min wins over max",
+			Items: []ViewAttrs{
+				{Width: Px(50), Height: Px(10), MinWidth: Px(40), MaxWidth: Px(20)},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 40, 10),
+			},
+		},
+		{
+			Name: "cross size",
+			Items: []ViewAttrs{
+				{Width: Px(10), Height: Px(10), MinHeight: Px(30)},
+				{Width: Px(10), Height: Px(80), MaxHeight: Pct(50)},
+			},
+			Want: []image.Rectangle{
+				image.Rect(0, 0, 10, 30),
+				image.Rect(10, 0, 20, 50),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			flex := &View{
+				Attrs: ViewAttrs{
+					Width:      Px(100),
+					Height:     Px(100),
+					Direction:  Row,
+					AlignItems: AlignItemStart,
+				},
+			}
+			mocks := make([]*mockHandler, len(tt.Items))
+			for i := range mocks {
+				mocks[i] = NewMockHandler()
+				flex.AddChild(&View{Attrs: tt.Items[i], Handler: mocks[i].ViewHandler})
+			}
+			flex.Update()
+			flex.Draw(nil)
+
+			for i, want := range tt.Want {
+				assert.Equal(t, want, mocks[i].Frame)
+			}
+		})
+	}
+}
+
+func TestMaxStretch(t *testing.T) {
+	mock := NewMockHandler()
+	flex := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(100),
+			Height:     Px(100),
+			Direction:  Row,
+			AlignItems: AlignItemStretch,
+		},
+	}
+	flex.AddChild(&View{
+		Attrs:   ViewAttrs{Width: Px(10), MaxHeight: Px(40)},
+		Handler: mock.ViewHandler,
+	})
+	flex.Update()
+	flex.Draw(nil)
+
+	assert.Equal(t, image.Rect(0, 0, 10, 40), mock.Frame)
+}
+
+func TestMeasure(t *testing.T) {
+	// textMeasure measures a 100px wide text with 10px high lines,
+	// which wraps when it is narrower.
+	textMeasure := func(modes *[]MeasureMode) func(w, h int, mode MeasureMode) (int, int) {
+		return func(w, h int, mode MeasureMode) (int, int) {
+			*modes = append(*modes, mode)
+			if mode == MeasureModeUndefined || w >= 100 {
+				return 100, 10
+			}
+			if w <= 0 {
+				return 0, 0
+			}
+			return w, (100 + w - 1) / w * 10
+		}
+	}
+
+	var tests = []struct {
+		Name      string
+		Flex      ViewAttrs
+		Sibling   *ViewAttrs
+		Item      ViewAttrs
+		Want      image.Rectangle
+		WantModes []MeasureMode
+	}{
+		{
+			Name: "natural size",
+			Flex: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart},
+			Item: ViewAttrs{},
+			Want: image.Rect(0, 0, 100, 10),
+			WantModes: []MeasureMode{
+				MeasureModeAtMost,
+			},
+		},
+		{
+			Name: "wraps at most",
+			Flex: ViewAttrs{Width: Px(60), Height: Px(100), AlignItems: AlignItemStart},
+			Item: ViewAttrs{Shrink: 1},
+			Want: image.Rect(0, 0, 60, 20),
+			WantModes: []MeasureMode{
+				MeasureModeAtMost,
+			},
+		},
+		{
+			Name:    "measured again after shrinking",
+			Flex:    ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart},
+			Sibling: &ViewAttrs{Width: Px(70), Height: Px(10)},
+			Item:    ViewAttrs{Shrink: 1},
+			Want:    image.Rect(70, 0, 100, 40),
+			WantModes: []MeasureMode{
+				MeasureModeAtMost,
+				MeasureModeExactly,
+			},
+		},
+		{
+			Name: "fixed width",
+			Flex: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart},
+			Item: ViewAttrs{Width: Px(50)},
+			Want: image.Rect(0, 0, 50, 20),
+			WantModes: []MeasureMode{
+				MeasureModeExactly,
+			},
+		},
+		{
+			Name: "column stretch",
+			Flex: ViewAttrs{Width: Px(30), Height: Px(100), Direction: Column, AlignItems: AlignItemStretch},
+			Item: ViewAttrs{},
+			Want: image.Rect(0, 0, 30, 40),
+			WantModes: []MeasureMode{
+				MeasureModeExactly,
+			},
+		},
+		{
+			Name: "padding",
+			Flex: ViewAttrs{Width: Px(60), Height: Px(100), AlignItems: AlignItemStart},
+			Item: ViewAttrs{Width: Px(60), PaddingLeft: Px(5), PaddingRight: Px(5), PaddingTop: Px(2), PaddingBottom: Px(2)},
+			Want: image.Rect(0, 0, 60, 24),
+			WantModes: []MeasureMode{
+				MeasureModeExactly,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var modes []MeasureMode
+			flex := &View{Attrs: tt.Flex}
+			if tt.Sibling != nil {
+				flex.AddChild(&View{Attrs: *tt.Sibling})
+			}
+			mock := NewMockHandler()
+			mock.ViewHandler.Measure = textMeasure(&modes)
+			flex.AddChild(&View{Attrs: tt.Item, Handler: mock.ViewHandler})
+
+			flex.Update()
+			flex.Draw(nil)
+
+			assert.Equal(t, tt.Want, mock.Frame)
+			assert.Equal(t, tt.WantModes, modes[:len(tt.WantModes)])
+		})
+	}
+}
+
+func TestMeasureAutoSizedContainer(t *testing.T) {
+	var modes []MeasureMode
+	mock := NewMockHandler()
+	mock.ViewHandler.Measure = func(w, h int, mode MeasureMode) (int, int) {
+		modes = append(modes, mode)
+		return 40, 12
+	}
+	container := &View{}
+	root := &View{
+		Attrs: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart},
+	}
+	label := &View{Handler: mock.ViewHandler}
+	root.AddChild(container.AddChild(label))
+
+	root.Update()
+	root.Draw(nil)
+
+	require.Equal(t, MeasureModeUndefined, modes[0])
+	assert.Equal(t, image.Rect(0, 0, 40, 12), label.frame)
+	assert.Equal(t, 40, container.calculatedWidth)
+	assert.Equal(t, 12, container.calculatedHeight)
+}
+
+func TestLengths(t *testing.T) {
+	root := &View{
+		Attrs: ViewAttrs{
+			Width:       Px(200),
+			Height:      Px(100),
+			Direction:   Row,
+			AlignItems:  AlignItemStart,
+			PaddingLeft: Pct(10),
+		},
+	}
+	mock1, mock2, mock3 := NewMockHandler(), NewMockHandler(), NewMockHandler()
+	root.AddChild(
+		&View{
+			Attrs: ViewAttrs{
+				Width:      Calc(Pct(50), Px(-20)),
+				Height:     Vh(20),
+				MarginLeft: Em(1),
+			},
+			Handler: mock1.ViewHandler,
+		},
+		(&View{
+			Attrs: ViewAttrs{
+				Width:      Px(50),
+				Height:     Rem(2),
+				PaddingTop: Pct(10),
+			},
+			Handler: mock2.ViewHandler,
+		}).AddChild(&View{
+			Attrs: ViewAttrs{
+				Width: Vw(10), Height: Pct(50),
+			},
+			Handler: mock3.ViewHandler,
+		}),
+	)
+	root.Update()
+	root.Draw(nil)
+
+	// The content box of the root is 180px wide after the 10% padding.
+	assert.Equal(t, image.Rect(36, 0, 106, 20), mock1.Frame)
+	assert.Equal(t, image.Rect(106, 0, 156, 32), mock2.Frame)
+	// Percent paddings are relative to the width of the containing view,
+	// vw lengths to the width of the root view.
+	assert.Equal(t, image.Rect(106, 18, 126, 25), mock3.Frame)
+}
+
+func TestWidthInPct(t *testing.T) {
+	mocks := []*mockHandler{NewMockHandler(), NewMockHandler(), NewMockHandler()}
+	flex := &View{
+		Attrs: ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart},
+	}
+	flex.AddChild(
+		&View{Attrs: ViewAttrs{Width: Px(60), Height: Px(10)}, Handler: mocks[0].ViewHandler},
+		// The percent width is limited to the space left by the other items.
+		&View{Attrs: ViewAttrs{WidthInPct: 50, HeightInPct: 20}, Handler: mocks[1].ViewHandler},
+		// The width takes precedence over the percent width.
+		&View{Attrs: ViewAttrs{Width: Px(10), WidthInPct: 50, Height: Px(10)}, Handler: mocks[2].ViewHandler},
+	)
+	flex.Update()
+	flex.Draw(nil)
+
+	assert.Equal(t, image.Rect(60, 0, 90, 20), mocks[1].Frame)
+	assert.Equal(t, image.Rect(90, 0, 100, 10), mocks[2].Frame)
+}
+
+func TestLengthsSetters(t *testing.T) {
+	mock := NewMockHandler()
+	view := &View{
+		Attrs: ViewAttrs{
+			Height:      Px(10),
+			Width:       Pct(50),
+			MarginLeft:  Em(2),
+			PaddingLeft: Calc(Pct(10), Px(2)),
+		},
+		Handler: mock.ViewHandler,
+	}
+	flex := &View{
+		Attrs: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart},
+	}
+	flex.AddChild(view)
+	flex.Update()
+	flex.Draw(nil)
+	require.Equal(t, image.Rect(32, 0, 132, 10), mock.Frame)
+	require.Equal(t, 22, view.used.PaddingLeft)
+
+	// Setting a pixel value replaces the length, zero included.
+	view.SetWidth(0)
+	view.SetMarginLeft(0)
+	view.SetPaddingLeft(0)
+	flex.Update()
+	flex.Draw(nil)
+	assert.Equal(t, Px(0), view.Attrs.Width)
+	assert.Equal(t, Px(0), view.Attrs.MarginLeft)
+	assert.Equal(t, Px(0), view.Attrs.PaddingLeft)
+	assert.Equal(t, image.Rect(0, 0, 0, 10), mock.Frame)
+	assert.Equal(t, 0, view.used.PaddingLeft)
+}
+
+func TestLengthsPercent(t *testing.T) {
+	// A percentage has the same meaning whether it is given alone or in calc().
+	view, err := ParseE(`
+		<view style="width: 200px; height: 100px; align-items: flex-start;">
+			<view style="width: 50px; height: 10px;"></view>
+			<view id="pct" style="width: 100%; height: 50%;"></view>
+			<view id="calc" style="width: calc(100%); height: calc(50%);"></view>
+		</view>`, nil)
+	require.NoError(t, err)
+	view.Update()
+
+	assert.Equal(t, 200, view.MustGetByID("pct").frame.Dx())
+	assert.Equal(t, 50, view.MustGetByID("pct").frame.Dy())
+	assert.Equal(t, 200, view.MustGetByID("calc").frame.Dx())
+	assert.Equal(t, 50, view.MustGetByID("calc").frame.Dy())
+}
+
+func TestLayoutPropagation(t *testing.T) {
+	// The auto sized views between the changed view and the nearest
+	// view of a fixed size are laid out again.
+	item := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10)}}
+	inner := (&View{}).AddChild(item)
+	outer := (&View{Attrs: ViewAttrs{AlignItems: AlignItemStart}}).AddChild(inner)
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(outer, &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10)}})
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 10, 10), outer.frame)
+	require.Equal(t, image.Rect(10, 0, 20, 10), root.children[1].frame)
+
+	item.SetWidth(30)
+	root.Update()
+	assert.Equal(t, image.Rect(0, 0, 30, 10), item.frame)
+	assert.Equal(t, image.Rect(0, 0, 30, 10), inner.frame)
+	assert.Equal(t, image.Rect(0, 0, 30, 10), outer.frame)
+	assert.Equal(t, image.Rect(30, 0, 40, 10), root.children[1].frame)
+}
+
+func TestIncrementalLayout(t *testing.T) {
+	// Each panel has a bar and a label, which counts its measurements.
+	measures := make([]int, 2)
+	var bars, labels []*View
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100), Direction: Column}}
+	for i := range measures {
+		i := i
+		bar := &View{Attrs: ViewAttrs{Width: Px(50), Height: Px(10)}}
+		label := &View{Handler: ViewHandler{Measure: func(w, h int, mode MeasureMode) (int, int) {
+			measures[i]++
+			return 20, 10
+		}}}
+		panel := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(50), AlignItems: AlignItemStart}}
+		root.AddChild(panel.AddChild(bar, label))
+		bars, labels = append(bars, bar), append(labels, label)
+	}
+	root.Update()
+	require.Equal(t, image.Rect(50, 50, 70, 60), labels[1].frame)
+
+	// A clean tree is not laid out again.
+	before := append([]int(nil), measures...)
+	root.Update()
+	root.Draw(nil)
+	assert.Equal(t, before, measures)
+
+	// Only the panel of the changed bar is laid out again.
+	bars[1].SetWidth(80)
+	root.Update()
+	assert.Equal(t, before[0], measures[0])
+	assert.Greater(t, measures[1], before[1])
+	assert.Equal(t, image.Rect(80, 50, 100, 60), labels[1].frame)
+
+	// The descendants of a moved view move with it without being laid out again.
+	before = append([]int(nil), measures...)
+	root.SetPaddingTop(10)
+	root.Update()
+	assert.Equal(t, before, measures)
+	assert.Equal(t, image.Rect(80, 60, 100, 70), labels[1].frame)
+}
+
+// newBenchmarkTree returns a HUD of 500 views: 20 auto sized panels with
+// a title and 23 rows, the first row of the first panel being a bar.
+func newBenchmarkTree() (root, bar *View) {
+	root = &View{Attrs: ViewAttrs{Width: Px(1280), Height: Px(720), Direction: Row, Wrap: WrapNormal}}
+	for i := 0; i < 20; i++ {
+		panel := &View{Attrs: ViewAttrs{Width: Px(300), Direction: Column, PaddingLeft: Px(4), PaddingTop: Px(4)}}
+		panel.AddChild(&View{Attrs: ViewAttrs{Height: Px(12), MarginBottom: Px(2)}})
+		for j := 0; j < 23; j++ {
+			row := &View{Attrs: ViewAttrs{Width: Px(float64(100 + j)), Height: Px(6), Grow: float64(j % 2)}}
+			panel.AddChild(row)
+			if bar == nil {
+				bar = row
+			}
+		}
+		root.AddChild(panel)
+	}
+	return root, bar
+}
+
+func BenchmarkLayout(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		root, _ := newBenchmarkTree()
+		b.StartTimer()
+		root.relayout()
+	}
+}
+
+func BenchmarkLayoutChangedLeaf(b *testing.B) {
+	root, bar := newBenchmarkTree()
+	root.relayout()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bar.SetWidth(100 + i%100)
+		root.relayout()
+	}
+}
+
+func BenchmarkLayoutClean(b *testing.B) {
+	root, _ := newBenchmarkTree()
+	root.relayout()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root.relayout()
+	}
+}
+
 func flexItemBounds(parent *View, child *View) image.Rectangle {
 	mock := NewMockHandler()
 	child.Handler = mock.ViewHandler
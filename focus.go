@@ -0,0 +1,199 @@
+package furex
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// keyRepeatDelay and keyRepeatInterval are the ticks a key is held down
+// before it repeats, and between the repeats.
+const keyRepeatDelay = 30
+const keyRepeatInterval = 3
+
+// Focus focuses the view so that it receives key events, blurring the view focused before.
+// It returns false if the view is not focusable, or if it or one of its ancestors is hidden.
+func (v *View) Focus() bool {
+	if !v.isFocusable() {
+		return false
+	}
+	root := v.root()
+	if root.focused != v {
+		root.setFocus(v)
+	}
+	return true
+}
+
+// Blur removes the focus from the view if it is focused.
+func (v *View) Blur() {
+	if root := v.root(); root.focused == v {
+		root.setFocus(nil)
+	}
+}
+
+// IsFocused returns true if the view is focused.
+func (v *View) IsFocused() bool {
+	return v.root().focusedView() == v
+}
+
+// FocusedView returns the focused view in the tree of the view,
+// or nil if no view is focused.
+func (v *View) FocusedView() *View {
+	return v.root().focusedView()
+}
+
+// FocusNext moves the focus to the next view in Tab navigation order.
+// It returns false if there is no view to focus.
+func (v *View) FocusNext() bool {
+	return v.root().moveFocus(1)
+}
+
+// FocusPrevious moves the focus to the previous view in Tab navigation order.
+// It returns false if there is no view to focus.
+func (v *View) FocusPrevious() bool {
+	return v.root().moveFocus(-1)
+}
+
+func (v *View) root() *View {
+	root := v
+	for root.hasParent {
+		root = root.parent
+	}
+	return root
+}
+
+// isFocusable returns true if the view is focusable and visible.
+func (v *View) isFocusable() bool {
+	if !v.Attrs.Focusable {
+		return false
+	}
+	for p := v; p != nil; p = p.parent {
+		if p.Attrs.Hidden || p.Attrs.Display == DisplayNone {
+			return false
+		}
+	}
+	return true
+}
+
+// focusedView returns the focused view of the root view. The focus is removed
+// if the view was removed from the tree or can't be focused anymore.
+func (root *View) focusedView() *View {
+	f := root.focused
+	if f != nil && (f.root() != root || !f.isFocusable()) {
+		root.setFocus(nil)
+		return nil
+	}
+	return f
+}
+
+func (root *View) setFocus(v *View) {
+	prev := root.focused
+	root.focused = v
+	if prev != nil {
+		prev.Handler.HandleBlur()
+	}
+	if v != nil {
+		v.Handler.HandleFocus()
+	}
+}
+
+// tabOrder returns the views that can be focused by Tab navigation, in order.
+func (root *View) tabOrder() []*View {
+	var views []*View
+	var walk func(v *View)
+	walk = func(v *View) {
+		if v.Attrs.Hidden || v.Attrs.Display == DisplayNone {
+			return
+		}
+		if v.Attrs.Focusable && v.Attrs.TabIndex >= 0 {
+			views = append(views, v)
+		}
+		for _, c := range v.children {
+			walk(c)
+		}
+	}
+	walk(root)
+	rank := func(v *View) int {
+		if v.Attrs.TabIndex == 0 {
+			return math.MaxInt
+		}
+		return v.Attrs.TabIndex
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		return rank(views[i]) < rank(views[j])
+	})
+	return views
+}
+
+func (root *View) moveFocus(dir int) bool {
+	views := root.tabOrder()
+	if len(views) == 0 {
+		return false
+	}
+	focused := root.focusedView()
+	next := 0
+	if dir < 0 {
+		next = len(views) - 1
+	}
+	for i, v := range views {
+		if v == focused {
+			next = (i + dir + len(views)) % len(views)
+			break
+		}
+	}
+	return views[next].Focus()
+}
+
+// focusAt focuses the topmost focusable view at (x, y), or one of its ancestors.
+// The focus is removed if there is none.
+func (root *View) focusAt(x, y int) {
+	for v := root.viewAt(x, y); v != nil; v = v.parent {
+		if v.isFocusable() {
+			v.Focus()
+			return
+		}
+	}
+	if root.focused != nil {
+		root.setFocus(nil)
+	}
+}
+
+// handleKeyEvents sends the key events of this tick to the focused view.
+func (root *View) handleKeyEvents() {
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	for _, k := range inpututil.AppendJustPressedKeys(nil) {
+		root.dispatchKey(KeyEvent{Key: k}, shift)
+	}
+	for _, k := range inpututil.AppendPressedKeys(nil) {
+		if d := inpututil.KeyPressDuration(k); d > keyRepeatDelay && (d-keyRepeatDelay)%keyRepeatInterval == 0 {
+			root.dispatchKey(KeyEvent{Key: k, Repeat: true}, shift)
+		}
+	}
+	for _, k := range inpututil.AppendJustReleasedKeys(nil) {
+		root.dispatchKey(KeyEvent{Key: k, Released: true}, shift)
+	}
+}
+
+// dispatchKey sends the key event to the focused view, or to the root view
+// if no view is focused. Events not handled by the view bubble up to its ancestors.
+// A Tab key not handled by any view moves the focus, backwards if shift is held down.
+func (root *View) dispatchKey(e KeyEvent, shift bool) bool {
+	v := root.focusedView()
+	if v == nil {
+		v = root
+	}
+	for ; v != nil; v = v.parent {
+		if v.Handler.HandleKey(e) {
+			return true
+		}
+	}
+	if e.Key == ebiten.KeyTab && !e.Released {
+		if shift {
+			return root.moveFocus(-1)
+		}
+		return root.moveFocus(1)
+	}
+	return false
+}
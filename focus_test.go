@@ -0,0 +1,146 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFocusTabOrder(t *testing.T) {
+	a := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true}}
+	b := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true, TabIndex: 2}}
+	c := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true, TabIndex: 1}}
+	d := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true, TabIndex: -1}}
+	e := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true}}
+	f := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10)}}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	root.AddChild(
+		(&View{Attrs: ViewAttrs{Width: Px(50), Height: Px(50)}}).AddChild(a, b),
+		c, d,
+		(&View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Hidden: true}}).AddChild(e),
+		f,
+	)
+
+	require.Equal(t, []*View{c, b, a}, root.tabOrder())
+	require.Nil(t, root.FocusedView())
+
+	require.True(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyTab}, false))
+	require.True(t, c.IsFocused())
+	root.dispatchKey(KeyEvent{Key: ebiten.KeyTab}, false)
+	require.True(t, b.IsFocused())
+	root.dispatchKey(KeyEvent{Key: ebiten.KeyTab}, false)
+	require.True(t, a.IsFocused())
+	root.dispatchKey(KeyEvent{Key: ebiten.KeyTab}, false)
+	require.True(t, c.IsFocused())
+
+	root.dispatchKey(KeyEvent{Key: ebiten.KeyTab}, true)
+	require.True(t, a.IsFocused())
+
+	// Views with a negative tab index can be focused, but not by Tab.
+	require.True(t, d.Focus())
+	require.Equal(t, d, a.FocusedView())
+	require.False(t, e.Focus())
+	require.False(t, f.Focus())
+	require.Equal(t, d, root.FocusedView())
+
+	root.dispatchKey(KeyEvent{Key: ebiten.KeyTab}, false)
+	require.True(t, c.IsFocused())
+	require.False(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyTab, Released: true}, false))
+	require.True(t, c.IsFocused())
+}
+
+func TestFocusBlur(t *testing.T) {
+	var events []string
+	focusable := func(name string) *View {
+		return &View{
+			Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true},
+			Handler: ViewHandler{
+				Focus: func() { events = append(events, "focus "+name) },
+				Blur:  func() { events = append(events, "blur "+name) },
+			},
+		}
+	}
+	a, b := focusable("a"), focusable("b")
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	root.AddChild(a, b)
+
+	a.Focus()
+	a.Focus()
+	b.Focus()
+	a.Blur()
+	require.True(t, b.IsFocused())
+	b.Blur()
+	require.Nil(t, root.FocusedView())
+	require.Equal(t, []string{"focus a", "blur a", "focus b", "blur b"}, events)
+
+	// The focus is removed when the view is hidden or removed from the tree.
+	events = nil
+	a.Focus()
+	a.SetDisplay(DisplayNone)
+	require.Nil(t, root.FocusedView())
+	b.Focus()
+	root.RemoveChild(b)
+	require.Nil(t, root.FocusedView())
+	require.Equal(t, []string{"focus a", "blur a", "focus b", "blur b"}, events)
+}
+
+func TestKeyBubbling(t *testing.T) {
+	var handled []string
+	keyHandler := func(name string, key ebiten.Key) ViewHandler {
+		return ViewHandler{
+			Key: func(e KeyEvent) bool {
+				if e.Key != key {
+					return false
+				}
+				handled = append(handled, name)
+				return true
+			},
+		}
+	}
+	input := &View{
+		Attrs:   ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true},
+		Handler: keyHandler("input", ebiten.KeyTab),
+	}
+	other := &View{Attrs: ViewAttrs{Width: Px(10), Height: Px(10), Focusable: true}}
+	form := &View{
+		Attrs:   ViewAttrs{Width: Px(50), Height: Px(50)},
+		Handler: keyHandler("form", ebiten.KeyEnter),
+	}
+	root := &View{
+		Attrs:   ViewAttrs{Width: Px(100), Height: Px(100)},
+		Handler: keyHandler("root", ebiten.KeyEscape),
+	}
+	root.AddChild(form.AddChild(input), other)
+
+	// Without a focused view, the root view gets the events.
+	require.True(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyEscape}, false))
+	require.False(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyEnter}, false))
+
+	input.Focus()
+	require.True(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyEnter}, false))
+	require.True(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyEscape, Repeat: true}, false))
+	require.False(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyA}, false))
+
+	// The focused view handles Tab itself, so the focus doesn't move.
+	require.True(t, root.dispatchKey(KeyEvent{Key: ebiten.KeyTab}, false))
+	require.True(t, input.IsFocused())
+	require.Equal(t, []string{"root", "form", "root", "input"}, handled)
+}
+
+func TestFocusAt(t *testing.T) {
+	button := &View{
+		Attrs: ViewAttrs{Width: Px(50), Height: Px(50), Focusable: true},
+	}
+	button.AddChild(&View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20)}})
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(button)
+	root.Update()
+	root.Draw(nil)
+
+	root.focusAt(10, 10)
+	require.True(t, button.IsFocused())
+
+	root.focusAt(80, 80)
+	require.Nil(t, root.FocusedView())
+}
@@ -14,6 +14,12 @@ var (
 	debugColorShift = ebiten.ColorM{}
 )
 
+// ScrollbarColor is the color of the scrollbars of scroll containers.
+var ScrollbarColor color.Color = color.RGBA{0x80, 0x80, 0x80, 0xc0}
+
+// BaseFontSize is the font size in pixels that em and rem lengths are relative to.
+var BaseFontSize = 16.0
+
 func debugBorders(screen *ebiten.Image, root containerEmbed) {
 	queue := []containerEmbed{}
 	queue = append(queue, root)
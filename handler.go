@@ -1,6 +1,7 @@
 package furex
 
 import (
+	"fmt"
 	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -53,6 +54,41 @@ type MouseEnterLeaveHandler interface {
 	HandleMouseLeave()
 }
 
+// MeasureMode tells a Measurer how the available width constrains the content.
+type MeasureMode int
+
+const (
+	// MeasureModeUndefined means the width is not constrained,
+	// the content should be measured at its natural size.
+	MeasureModeUndefined MeasureMode = iota
+	// MeasureModeExactly means the view will be exactly as wide as the available width,
+	// the content should be measured to fit it, e.g. wrapping text reports its height.
+	MeasureModeExactly
+	// MeasureModeAtMost means the view can be at most as wide as the available width.
+	MeasureModeAtMost
+)
+
+func (m MeasureMode) String() string {
+	switch m {
+	case MeasureModeUndefined:
+		return "undefined"
+	case MeasureModeExactly:
+		return "exactly"
+	case MeasureModeAtMost:
+		return "at-most"
+	default:
+		return fmt.Sprintf("%d", int(m))
+	}
+}
+
+// Measurer represents a component that knows the size of its content.
+type Measurer interface {
+	// HandleMeasure returns the size of the content, not including the padding.
+	// The available size is the size of the content box the view may take,
+	// mode tells how the available width should be interpreted.
+	HandleMeasure(availableW, availableH int, mode MeasureMode) (w, h int)
+}
+
 // SwipeHandler represents different swipe directions.
 type SwipeDirection int
 
@@ -69,6 +105,30 @@ type SwipeHandler interface {
 	HandleSwipe(dir SwipeDirection)
 }
 
+// FocusHandler represents a component that can be focused.
+type FocusHandler interface {
+	// HandleFocus handles the view getting the focus.
+	HandleFocus()
+	// HandleBlur handles the view losing the focus.
+	HandleBlur()
+}
+
+// KeyEvent is a key event sent to the focused view.
+type KeyEvent struct {
+	Key ebiten.Key
+	// Released is true if the key is just released, otherwise it is just pressed.
+	Released bool
+	// Repeat is true if the key is pressed again by key repeat while it is held down.
+	Repeat bool
+}
+
+// KeyHandler represents a component that handles key events.
+type KeyHandler interface {
+	// HandleKey handles a key event of the focused view or one of its descendants.
+	// It returns true if it handles the event, otherwise the event is sent to the parent view.
+	HandleKey(e KeyEvent) bool
+}
+
 // ViewHandler represents a component that can be added to a container.
 // Do not directly use func in this struct, using Handle* instead.
 type ViewHandler struct {
@@ -84,6 +144,10 @@ type ViewHandler struct {
 	MouseEnter                  func(x, y int) bool
 	MouseLeave                  func()
 	Swipe                       func(dir SwipeDirection)
+	Measure                     func(availableW, availableH int, mode MeasureMode) (w, h int)
+	Focus                       func()
+	Blur                        func()
+	Key                         func(e KeyEvent) bool
 }
 
 // IsTouchHandler returns true if the handler is a touch handler. Otherwise, returns false.
@@ -154,6 +218,36 @@ func (h *ViewHandler) HandleJustReleasedTouchID(touch ebiten.TouchID, x int, y i
 	}
 }
 
+// HandleMeasure implements Measurer.
+func (h *ViewHandler) HandleMeasure(availableW, availableH int, mode MeasureMode) (int, int) {
+	if h.Measure != nil {
+		return h.Measure(availableW, availableH, mode)
+	}
+	return 0, 0
+}
+
+// HandleFocus implements FocusHandler.
+func (h *ViewHandler) HandleFocus() {
+	if h.Focus != nil {
+		h.Focus()
+	}
+}
+
+// HandleBlur implements FocusHandler.
+func (h *ViewHandler) HandleBlur() {
+	if h.Blur != nil {
+		h.Blur()
+	}
+}
+
+// HandleKey implements KeyHandler.
+func (h *ViewHandler) HandleKey(e KeyEvent) bool {
+	if h.Key != nil {
+		return h.Key(e)
+	}
+	return false
+}
+
 // HandleUpdate implements Updater.
 func (h *ViewHandler) HandleUpdate(v *View) {
 	if h.Update != nil {
@@ -175,3 +269,6 @@ var _ MouseHandler = (*ViewHandler)(nil)
 var _ MouseLeftButtonHandler = (*ViewHandler)(nil)
 var _ MouseEnterLeaveHandler = (*ViewHandler)(nil)
 var _ SwipeHandler = (*ViewHandler)(nil)
+var _ Measurer = (*ViewHandler)(nil)
+var _ FocusHandler = (*ViewHandler)(nil)
+var _ KeyHandler = (*ViewHandler)(nil)
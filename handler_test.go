@@ -21,10 +21,10 @@ func TestHandlers(t *testing.T) {
 		t.Run(scenario, func(t *testing.T) {
 			flex := &View{
 				Attrs: ViewAttrs{
-					Width:      300,
-					Height:     500,
-					Left:       100,
-					Top:        50,
+					Width:      Px(300),
+					Height:     Px(500),
+					Left:       Px(100),
+					Top:        Px(50),
 					Position:   PositionAbsolute,
 					Direction:  Column,
 					Justify:    JustifyCenter,
@@ -34,8 +34,8 @@ func TestHandlers(t *testing.T) {
 
 			flex2 := &View{
 				Attrs: ViewAttrs{
-					Width:      100,
-					Height:     200,
+					Width:      Px(100),
+					Height:     Px(200),
 					Direction:  Column,
 					Justify:    JustifyEnd,
 					AlignItems: AlignItemEnd,
@@ -47,8 +47,8 @@ func TestHandlers(t *testing.T) {
 			h := NewMockHandler()
 			flex2.AddChild(&View{
 				Attrs: ViewAttrs{
-					Width:  10,
-					Height: 20,
+					Width:  Px(10),
+					Height: Px(20),
 				},
 				Handler: h.ViewHandler,
 			})
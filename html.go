@@ -1,11 +1,17 @@
 package furex
 
 import (
+	"errors"
 	"fmt"
+	"image/color"
 	"io"
+	"math"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/vanng822/go-premailer/premailer"
 	"golang.org/x/net/html"
@@ -47,18 +53,38 @@ type ParseOptions struct {
 	Handler *ViewHandler
 }
 
+// Parse parses the HTML into a view. It panics if the HTML is invalid or uses an
+// unknown component. Other errors, such as invalid styles, are ignored,
+// callers who need them should use ParseE.
 func Parse(input string, opts *ParseOptions) *View {
+	view, err := ParseE(input, opts)
+	if errors.Is(err, ErrInvalidHTML) || errors.Is(err, ErrUnknownComponent) {
+		panic(err)
+	}
+	return view
+}
+
+// ParseE parses the HTML into a view, and returns an *ErrorList of *ParseError
+// if it has errors. Views with errors are built as well as they can be, e.g.
+// unknown components are plain views and invalid properties are not set,
+// so the view is returned with the errors unless the HTML is invalid.
+func ParseE(input string, opts *ParseOptions) (*View, error) {
 	if opts == nil {
 		opts = &ParseOptions{}
 	}
 
-	inlinedHTML := inlineCSS(input)
+	errs := &ErrorList{}
+	doc, frames := parseKeyframes(markPositions(input), errs)
+	inlinedHTML := inlineCSS(doc, errs)
 	z := html.NewTokenizer(strings.NewReader(inlinedHTML))
 	dummy := &View{}
 	stack := &stack{stack: []*View{dummy}}
 	depth := 0
 	inBody := false
 	cms := []ComponentsMap{opts.Components, registerdComponents}
+	rich := &richText{}
+	// secondRoot is the second view at the root, which makes the HTML invalid.
+	var secondRoot *tagErrors
 Loop:
 	for {
 		tt := z.Next()
@@ -68,7 +94,8 @@ Loop:
 			if z.Err() == io.EOF {
 				break Loop
 			}
-			panic(z.Err())
+			errs.Add(&ParseError{Err: fmt.Errorf("%w: %s", ErrInvalidHTML, z.Err())})
+			return nil, errs
 		case html.StartTagToken:
 			if string(tn) == "body" {
 				inBody = true
@@ -77,40 +104,72 @@ Loop:
 			if !inBody {
 				continue
 			}
-			view := processTag(z, string(tn), opts, depth, cms)
+			a := readAttrs(z)
+			tagErrs := &tagErrors{list: errs, tag: string(tn), line: a.line, column: a.column}
+			if isInline(string(tn), cms) {
+				rich.start(stack.peek(), string(tn), a, tagErrs)
+				continue
+			}
+			view := processTag(z, string(tn), a, opts, depth, cms, tagErrs)
 			if view == nil {
 				continue
 			}
+			if stack.peek() == dummy && len(dummy.children) == 1 {
+				secondRoot = tagErrs
+			}
 			stack.peek().AddChild(view)
+			if voidElements[string(tn)] {
+				continue
+			}
 			stack.push(view)
 
 			depth++
 		case html.SelfClosingTagToken:
-			view := processTag(z, string(tn), opts, depth, cms)
+			a := readAttrs(z)
+			tagErrs := &tagErrors{list: errs, tag: string(tn), line: a.line, column: a.column}
+			if isInline(string(tn), cms) {
+				rich.start(stack.peek(), string(tn), a, tagErrs)
+				continue
+			}
+			view := processTag(z, string(tn), a, opts, depth, cms, tagErrs)
 			if view == nil {
 				continue
 			}
+			if stack.peek() == dummy && len(dummy.children) == 1 {
+				secondRoot = tagErrs
+			}
 			stack.peek().AddChild(view)
 		case html.TextToken:
 			if stack.len() > 0 {
-				stack.peek().Attrs.Text = strings.TrimSpace(string(z.Text()))
+				rich.add(stack.peek(), TextSpan{Text: string(z.Text())})
 			}
 		case html.EndTagToken:
 			if string(tn) == "body" {
 				inBody = false
 				continue
 			}
-			if !inBody {
+			if !inBody || voidElements[string(tn)] {
+				continue
+			}
+			if isInline(string(tn), cms) {
+				rich.end(stack.peek(), string(tn))
 				continue
 			}
-			stack.pop()
+			rich.finish(stack.pop())
 			depth--
 		}
 	}
-	if len(dummy.children) != 1 {
-		panic(fmt.Sprintf("invalid html: %s", input))
+	if n := len(dummy.children); n != 1 {
+		err := fmt.Errorf("%w: %d root views, want 1", ErrInvalidHTML, n)
+		if secondRoot != nil {
+			secondRoot.add("", err)
+		} else {
+			errs.Add(&ParseError{Err: err})
+		}
+		return nil, errs
 	}
 	view := dummy.PopChild()
+	view.docKeyframes = frames
 	// the root view should be dirty for the first time
 	// even if the view does not have any children
 	view.isDirty = true
@@ -118,23 +177,312 @@ Loop:
 		view.Handler = *opts.Handler
 	}
 
-	return view
+	if errs.HasErrors() {
+		return view, errs
+	}
+	return view, nil
 }
 
-func inlineCSS(doc string) string {
+func inlineCSS(doc string, errs *ErrorList) string {
 	prem, err := premailer.NewPremailerFromString(doc, &premailer.Options{})
 	if err != nil {
-		println(fmt.Errorf("invalid css: %s", err))
+		errs.Add(&ParseError{Err: fmt.Errorf("invalid css: %w", err)})
 		return doc
 	}
 	html, err := prem.Transform()
 	if err != nil {
-		println(fmt.Errorf("error transform html: %s", err))
+		errs.Add(&ParseError{Err: fmt.Errorf("error transform html: %w", err)})
 		return doc
 	}
 	return html
 }
 
+// positionAttr is the attribute of the start tags that carries their positions in the
+// document given to ParseE through the inlining of CSS, which rewrites the document.
+const positionAttr = "data-furex-position"
+
+// positionAttrs matches the position attributes added by markPositions.
+var positionAttrs = regexp.MustCompile(` ` + positionAttr + `="\d+:\d+"`)
+
+// markPositions returns the document with the line and column of each start tag
+// in its position attribute.
+func markPositions(doc string) string {
+	sb := &strings.Builder{}
+	z := html.NewTokenizer(strings.NewReader(doc))
+	line, column := 1, 1
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := string(z.Raw())
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			// The attribute is added after the tag name.
+			n := len(raw)
+			if i := strings.IndexAny(raw[1:], " \t\n\r\f/>"); i >= 0 {
+				n = i + 1
+			}
+			fmt.Fprintf(sb, `%s %s="%d:%d"%s`, raw[:n], positionAttr, line, column, raw[n:])
+		} else {
+			sb.WriteString(raw)
+		}
+		line, column = advancePosition(line, column, raw)
+	}
+	return sb.String()
+}
+
+// unmarkPositions returns the text without the position attributes of markPositions.
+func unmarkPositions(text string) string {
+	return positionAttrs.ReplaceAllString(text, "")
+}
+
+// advancePosition returns the line and column after the text from the line and column.
+func advancePosition(line, column int, text string) (int, int) {
+	for _, r := range text {
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+// tagErrors adds the errors of a tag to the errors of the document.
+type tagErrors struct {
+	list         *ErrorList
+	tag          string
+	line, column int
+}
+
+func (e *tagErrors) add(property string, err error) {
+	e.list.Add(&ParseError{Line: e.line, Column: e.column, Tag: e.tag, Property: property, Err: err})
+}
+
+// parseKeyframes returns the keyframes of the @keyframes rules of the style elements
+// of the document, which are not inlined, and the document without them.
+// Comments and strings are skipped.
+func parseKeyframes(doc string, errs *ErrorList) (string, map[string][]Keyframe) {
+	sb := &strings.Builder{}
+	frames := map[string][]Keyframe{}
+	line, column := 1, 1
+	// written is the end of the part of the document written to sb,
+	// scanned the end of the part line and column are counted to.
+	written, scanned := 0, 0
+	inStyle := false
+	for i := 0; i < len(doc); {
+		rest := doc[i:]
+		switch {
+		case !inStyle && strings.HasPrefix(rest, "<!--"):
+			i += skipPast(rest, 4, "-->")
+			continue
+		case !inStyle && hasTagPrefix(rest, "<style"):
+			i += skipPast(rest, 0, ">")
+			inStyle = true
+			continue
+		case !inStyle:
+			i++
+			continue
+		case hasTagPrefix(rest, "</style"):
+			inStyle = false
+			i += len("</style")
+			continue
+		case strings.HasPrefix(rest, "/*"):
+			i += skipPast(rest, 2, "*/")
+			continue
+		case rest[0] == '"' || rest[0] == '\'':
+			i += skipString(rest)
+			continue
+		case !strings.HasPrefix(rest, "@keyframes"):
+			i++
+			continue
+		}
+		sb.WriteString(doc[written:i])
+		line, column = advancePosition(line, column, unmarkPositions(doc[scanned:i]))
+		scanned = i
+		rule := rest[len("@keyframes"):]
+		open := strings.Index(rule, "{")
+		end := matchingBrace(rule, open)
+		if open < 0 || end < 0 {
+			errs.Add(&ParseError{Line: line, Column: column, Err: fmt.Errorf("invalid keyframes: %s", strings.TrimSpace(rule))})
+			return sb.String(), frames
+		}
+		name := strings.TrimSpace(rule[:open])
+		blocks, err := parseKeyframeBlocks(rule[open+1 : end])
+		if err != nil {
+			errs.Add(&ParseError{Line: line, Column: column, Err: fmt.Errorf("invalid keyframes %s: %w", name, err)})
+		} else {
+			frames[name] = sortKeyframes(blocks)
+		}
+		i += len("@keyframes") + end + 1
+		written = i
+	}
+	sb.WriteString(doc[written:])
+	return sb.String(), frames
+}
+
+// hasTagPrefix returns true if s starts with the tag, such as "<style", ignoring case.
+func hasTagPrefix(s, tag string) bool {
+	if len(s) < len(tag) || !strings.EqualFold(s[:len(tag)], tag) {
+		return false
+	}
+	if len(s) == len(tag) {
+		return true
+	}
+	c := s[len(tag)]
+	return c == '>' || c == '/' || unicode.IsSpace(rune(c))
+}
+
+// skipPast returns the length of s up to the end of the first end after from,
+// or the length of s if there is none.
+func skipPast(s string, from int, end string) int {
+	if i := strings.Index(s[from:], end); i >= 0 {
+		return from + i + len(end)
+	}
+	return len(s)
+}
+
+// skipString returns the length of the CSS string at the start of s.
+func skipString(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case s[0], '\n':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// matchingBrace returns the index of the brace closing the brace at open, or -1.
+func matchingBrace(s string, open int) int {
+	if open < 0 {
+		return -1
+	}
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseKeyframeBlocks parses the keyframes of a @keyframes rule, such as
+// "from { left: 0 } 50%, 75% { left: 10px; animation-timing-function: ease-in }".
+func parseKeyframeBlocks(body string) ([]Keyframe, error) {
+	var frames []Keyframe
+	for {
+		open := strings.Index(body, "{")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(body, "}")
+		if end < open {
+			return nil, fmt.Errorf("invalid keyframe: %s", strings.TrimSpace(body))
+		}
+		frame := Keyframe{}
+		for _, pair := range strings.Split(body[open+1:end], ";") {
+			kv := strings.Split(pair, ":")
+			if len(kv) != 2 {
+				continue
+			}
+			k, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+			if k == "animation-timing-function" {
+				e, err := parseEasing(val)
+				if err != nil {
+					return nil, err
+				}
+				frame.Easing = e
+				continue
+			}
+			prop, ok := propertiesByName[k]
+			if !ok || prop == PropertyAll {
+				return nil, fmt.Errorf("property cannot be animated: %s", k)
+			}
+			if err := setKeyframeValue(&frame, prop, val); err != nil {
+				return nil, err
+			}
+		}
+		for _, sel := range strings.Split(body[:open], ",") {
+			offset, err := parseKeyframeOffset(strings.TrimSpace(sel))
+			if err != nil {
+				return nil, err
+			}
+			f := frame
+			f.Offset = offset
+			frames = append(frames, f)
+		}
+		body = body[end+1:]
+	}
+	if strings.TrimSpace(body) != "" {
+		return nil, fmt.Errorf("invalid keyframe: %s", strings.TrimSpace(body))
+	}
+	return frames, nil
+}
+
+// parseKeyframeOffset parses the selector of a keyframe, from, to or a percentage.
+func parseKeyframeOffset(sel string) (float64, error) {
+	switch sel {
+	case "from":
+		return 0, nil
+	case "to":
+		return 1, nil
+	}
+	n, err := strconv.ParseFloat(strings.TrimSuffix(sel, "%"), 64)
+	if err != nil || !strings.HasSuffix(sel, "%") || n < 0 || n > 100 {
+		return 0, fmt.Errorf("invalid keyframe selector: %s", sel)
+	}
+	return n / 100, nil
+}
+
+// setKeyframeValue sets the value of the property at the keyframe.
+// Lengths are in pixels.
+func setKeyframeValue(f *Keyframe, prop FlexProperty, val string) error {
+	var n any
+	var err error
+	switch prop {
+	case PropertyOpacity:
+		n, err = parseOpacity(val)
+	case PropertyScale:
+		var s any
+		s, err = parseScale(val)
+		if err == nil {
+			n = 1.0
+			if s.(*float64) != nil {
+				n = *s.(*float64)
+			}
+		}
+	case PropertyColor, PropertyBackgroundColor, PropertyBorderColor:
+		var clr any
+		if clr, err = parseColor(val); err != nil {
+			return err
+		}
+		if f.Colors == nil {
+			f.Colors = map[FlexProperty]color.Color{}
+		}
+		f.Colors[prop] = clr.(color.Color)
+		return nil
+	default:
+		n, err = parsePixels(val)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %s", prop, val)
+	}
+	if f.Values == nil {
+		f.Values = map[FlexProperty]float64{}
+	}
+	f.Values[prop] = n.(float64)
+	return nil
+}
+
 type stack struct {
 	stack []*View
 }
@@ -158,10 +506,163 @@ func (s *stack) pop() *View {
 }
 
 var (
-	defaultComponents   = ComponentsMap{"div": nil, "view": nil}
+	defaultComponents   = ComponentsMap{"div": nil, "view": nil, "input": newInputView}
 	registerdComponents = defaultComponents
 )
 
+// voidElements are the tags which have no end tag, such as <input>.
+var voidElements = map[string]bool{"input": true}
+
+// inlineElements are the tags of rich text, which are parsed into the text spans
+// of the enclosing view instead of views, unless they are registered as components.
+var inlineElements = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "span": true, "br": true, "img": true,
+}
+
+func isInline(tagName string, cms cms) bool {
+	if !inlineElements[tagName] {
+		return false
+	}
+	for _, cm := range cms {
+		if _, ok := cm[tagName]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// richText builds the text spans of the views from their text and inline elements.
+type richText struct {
+	spans map[*View][]richSpan
+	// styles are the styles of the open inline elements of the views.
+	styles map[*View][]TextSpan
+}
+
+// style returns the style of the innermost open inline element of the view.
+func (r *richText) style(v *View) TextSpan {
+	styles := r.styles[v]
+	if len(styles) == 0 {
+		return TextSpan{}
+	}
+	return styles[len(styles)-1]
+}
+
+// richSpan is a span of a view being parsed. Inline spans are the line breaks,
+// icons and text in inline elements, which are kept in views with block children.
+type richSpan struct {
+	TextSpan
+	inline bool
+}
+
+// add adds a span to the view in the style of the open inline elements.
+// Adjacent text spans of the same style are merged when the view is finished.
+func (r *richText) add(v *View, span TextSpan) {
+	if r.spans == nil {
+		r.spans = make(map[*View][]richSpan)
+	}
+	st := r.style(v)
+	span.Bold, span.Italic = span.Bold || st.Bold, span.Italic || st.Italic
+	if span.Color == nil {
+		span.Color = st.Color
+	}
+	inline := len(r.styles[v]) > 0 || !isText(span)
+	r.spans[v] = append(r.spans[v], richSpan{TextSpan: span, inline: inline})
+}
+
+// start opens an inline element in the view, or adds the span of a line break or icon.
+func (r *richText) start(v *View, tagName string, a attrs, errs *tagErrors) {
+	switch tagName {
+	case "br":
+		r.add(v, TextSpan{Break: true})
+		return
+	case "img":
+		r.add(v, TextSpan{Icon: a.miscs["src"]})
+		return
+	}
+	st := r.style(v)
+	switch tagName {
+	case "b", "strong":
+		st.Bold = true
+	case "i", "em":
+		st.Italic = true
+	}
+	if a.style != "" {
+		styled := &View{}
+		parseStyle(styled, a.style, errs)
+		if styled.Attrs.Color != nil {
+			st.Color = styled.Attrs.Color
+		}
+	}
+	if r.styles == nil {
+		r.styles = make(map[*View][]TextSpan)
+	}
+	r.styles[v] = append(r.styles[v], st)
+}
+
+// end closes the innermost open inline element of the view.
+func (r *richText) end(v *View, tagName string) {
+	if tagName == "br" || tagName == "img" {
+		return
+	}
+	if styles := r.styles[v]; len(styles) > 0 {
+		r.styles[v] = styles[:len(styles)-1]
+	}
+}
+
+// finish sets the text of the view from its spans, without the white spaces
+// at the start and the end. Views with plain text only have no spans. The text
+// between the block children of a view is left out, only its inline elements are kept.
+func (r *richText) finish(v *View) {
+	var spans []TextSpan
+	for _, s := range r.spans[v] {
+		if !s.inline && len(v.children) > 0 {
+			continue
+		}
+		if n := len(spans); n > 0 && isText(spans[n-1]) && isText(s.TextSpan) && sameStyle(spans[n-1], s.TextSpan) {
+			spans[n-1].Text += s.Text
+			continue
+		}
+		spans = append(spans, s.TextSpan)
+	}
+	delete(r.spans, v)
+	delete(r.styles, v)
+	for len(spans) > 0 && isText(spans[0]) {
+		spans[0].Text = strings.TrimLeftFunc(spans[0].Text, unicode.IsSpace)
+		if spans[0].Text != "" {
+			break
+		}
+		spans = spans[1:]
+	}
+	for len(spans) > 0 && isText(spans[len(spans)-1]) {
+		last := &spans[len(spans)-1]
+		last.Text = strings.TrimRightFunc(last.Text, unicode.IsSpace)
+		if last.Text != "" {
+			break
+		}
+		spans = spans[:len(spans)-1]
+	}
+	if len(spans) == 0 {
+		return
+	}
+	v.Attrs.Text = spansText(spans)
+	if len(spans) > 1 || spans[0] != (TextSpan{Text: spans[0].Text}) {
+		v.Attrs.Spans = spans
+	}
+}
+
+func isText(s TextSpan) bool {
+	return s.Icon == "" && !s.Break
+}
+
+func sameStyle(a, b TextSpan) bool {
+	a.Text, b.Text = "", ""
+	return a == b
+}
+
+func newInputView() *View {
+	return NewTextInputView(&TextInput{})
+}
+
 func RegisterComponents(cs ComponentsMap) {
 	for k, v := range cs {
 		register(k, v)
@@ -173,46 +674,65 @@ func resetComponents()                  { registerdComponents = defaultComponent
 
 type cms []ComponentsMap
 
-func processTag(z *html.Tokenizer, tagName string, opts *ParseOptions, depth int, cms cms) *View {
-	view := createView(tagName, cms)
+func processTag(z *html.Tokenizer, tagName string, a attrs, opts *ParseOptions, depth int, cms cms, errs *tagErrors) *View {
+	view, err := createView(tagName, cms)
+	if err != nil {
+		errs.add("", err)
+	}
 
 	if depth == 0 {
 		processRootView(view, opts)
 	}
 
 	view.Attrs.TagName = tagName
-	view.Attrs.Raw = string(z.Raw())
+	view.Attrs.Raw = unmarkPositions(string(z.Raw()))
 
-	setStyleProps(view, readAttrs(z))
+	setStyleProps(view, a, errs)
 
 	return view
 }
 
-func setStyleProps(view *View, attrs attrs) {
-	parseStyle(view, attrs.style)
+func setStyleProps(view *View, attrs attrs, errs *tagErrors) {
+	parseStyle(view, attrs.style, errs)
 
 	view.Attrs.ID = attrs.id
 	view.Attrs.ExtraAttrs = attrs.miscs
 	view.Attrs.Hidden = attrs.hidden
+	view.Attrs.Cache = attrs.cache
+	view.Attrs.DrawText = attrs.drawText
+	if t, ok := view.Handler.Extra.(*TextInput); ok {
+		t.setAttrs(attrs.miscs)
+	}
+	if attrs.focusable {
+		view.Attrs.Focusable = true
+		view.Attrs.TabIndex = attrs.tabIndex
+	}
+	for dir, id := range attrs.nav {
+		if id != "" {
+			Nav(NavAction(dir), id)(view)
+		}
+	}
 }
 
 func processRootView(view *View, opts *ParseOptions) {
 	if opts.Width != 0 {
-		view.Attrs.Width = opts.Width
+		view.Attrs.Width = Px(float64(opts.Width))
 	}
 	if opts.Height != 0 {
-		view.Attrs.Height = opts.Height
+		view.Attrs.Height = Px(float64(opts.Height))
 	}
 }
 
-func createView(name string, cms cms) *View {
+// createView creates the view of the component. A plain view is returned
+// with an error if the component is unknown.
+func createView(name string, cms cms) (*View, error) {
 	view := &View{}
 	for _, cm := range cms {
 		if ok := component(name, cm, view); ok {
-			return view
+			return view, nil
 		}
 	}
-	panic(fmt.Sprintf("unknown component: %s", name))
+	return view, fmt.Errorf("%w: %s", ErrUnknownComponent, name)
 }
 
 func component(name string, m ComponentsMap, v *View) bool {
@@ -231,90 +751,154 @@ func component(name string, m ComponentsMap, v *View) bool {
 	return false
 }
 
-func parseStyle(view *View, style string) {
+func parseStyle(view *View, style string, errs *tagErrors) {
 	pairs := strings.Split(style, ";")
-	errs := &ErrorList{}
 	for _, pair := range pairs {
-		kv := strings.Split(pair, ":")
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, ":", 2)
 		if len(kv) != 2 {
+			errs.add("", fmt.Errorf("invalid declaration: %s", strings.TrimSpace(pair)))
 			continue
 		}
 		k := strings.TrimSpace(kv[0])
 		v := strings.TrimSpace(kv[1])
+		if v == "" {
+			errs.add(k, errors.New("missing value"))
+			continue
+		}
 
 		mapper, ok := styleMapper[k]
 		if !ok {
-			errs.Add(fmt.Errorf("unknown style: %s", k))
+			errs.add(k, fmt.Errorf("unknown style: %s", k))
 			continue
 		}
 		parsed, err := mapper.parseFunc(v)
 		if err != nil {
-			errs.Add(err)
+			errs.add(k, err)
 			continue
 		}
 		mapper.setFunc(view, parsed)
 	}
-	if errs.HasErrors() {
-		println(fmt.Sprintf("parse style errors: %v", errs))
-	}
 }
 
 func Int(i int) *int { return &i }
 
 var styleMapper = map[string]mapper[View]{
 	"left": {
-		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.Left = val }),
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.Left = val }),
 	},
 	"right": {
-		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.Right = Int(val) }),
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.Right = val }),
 	},
 	"top": {
-		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.Top = val }),
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.Top = val }),
 	},
 	"bottom": {
-		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.Bottom = Int(val) }),
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.Bottom = val }),
 	},
 	"width": {
 		parseFunc: parseLength,
-		setFunc: setFunc(func(v *View, val cssLength) {
-			switch val.unit {
-			case cssUnitPx:
-				v.Attrs.Width = int(val.val)
-			case cssUnitPct:
-				v.Attrs.WidthInPct = val.val
-			}
-		}),
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.Width = val }),
 	},
 	"height": {
 		parseFunc: parseLength,
-		setFunc: setFunc(func(v *View, val cssLength) {
-			switch val.unit {
-			case cssUnitPx:
-				v.Attrs.Height = int(val.val)
-			case cssUnitPct:
-				v.Attrs.HeightInPct = val.val
-			}
-		}),
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.Height = val }),
+	},
+	"min-width": {
+		parseFunc: parseSizeConstraint,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MinWidth = val }),
+	},
+	"max-width": {
+		parseFunc: parseSizeConstraint,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MaxWidth = val }),
+	},
+	"min-height": {
+		parseFunc: parseSizeConstraint,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MinHeight = val }),
+	},
+	"max-height": {
+		parseFunc: parseSizeConstraint,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MaxHeight = val }),
 	},
 	"margin-left": {
-		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.MarginLeft = val }),
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MarginLeft = val }),
 	},
 	"margin-top": {
-		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.MarginTop = val }),
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MarginTop = val }),
 	},
 	"margin-right": {
-		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.MarginRight = val }),
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MarginRight = val }),
 	},
 	"margin-bottom": {
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.MarginBottom = val }),
+	},
+	"margin": {
+		parseFunc: parseEdges,
+		setFunc: setFunc(func(v *View, val cssEdges) {
+			v.Attrs.MarginTop = val.top
+			v.Attrs.MarginRight = val.right
+			v.Attrs.MarginBottom = val.bottom
+			v.Attrs.MarginLeft = val.left
+		}),
+	},
+	"padding-left": {
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.PaddingLeft = val }),
+	},
+	"padding-top": {
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.PaddingTop = val }),
+	},
+	"padding-right": {
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.PaddingRight = val }),
+	},
+	"padding-bottom": {
+		parseFunc: parseLength,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.PaddingBottom = val }),
+	},
+	"padding": {
+		parseFunc: parseEdges,
+		setFunc: setFunc(func(v *View, val cssEdges) {
+			v.Attrs.PaddingTop = val.top
+			v.Attrs.PaddingRight = val.right
+			v.Attrs.PaddingBottom = val.bottom
+			v.Attrs.PaddingLeft = val.left
+		}),
+	},
+	"row-gap": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Attrs.RowGap = val }),
+	},
+	"column-gap": {
 		parseFunc: parseNumber,
-		setFunc:   setFunc(func(v *View, val int) { v.Attrs.MarginBottom = val }),
+		setFunc:   setFunc(func(v *View, val int) { v.Attrs.ColumnGap = val }),
+	},
+	"gap": {
+		parseFunc: parseGap,
+		setFunc: setFunc(func(v *View, val cssGap) {
+			v.Attrs.RowGap = val.row
+			v.Attrs.ColumnGap = val.column
+		}),
+	},
+	"inset": {
+		parseFunc: parseEdges,
+		setFunc: setFunc(func(v *View, val cssEdges) {
+			v.Attrs.Top = val.top
+			v.Attrs.Right = val.right
+			v.Attrs.Bottom = val.bottom
+			v.Attrs.Left = val.left
+		}),
 	},
 	"position": {
 		parseFunc: parsePosition,
@@ -336,6 +920,13 @@ var styleMapper = map[string]mapper[View]{
 		parseFunc: parseWrap,
 		setFunc:   setFunc(func(v *View, val FlexWrap) { v.Attrs.Wrap = val }),
 	},
+	"flex-flow": {
+		parseFunc: parseFlexFlow,
+		setFunc: setFunc(func(v *View, val cssFlexFlow) {
+			v.Attrs.Direction = val.direction
+			v.Attrs.Wrap = val.wrap
+		}),
+	},
 	"justify": {
 		parseFunc: parseJustify,
 		setFunc:   setFunc(func(v *View, val FlexJustify) { v.Attrs.Justify = val }),
@@ -352,6 +943,126 @@ var styleMapper = map[string]mapper[View]{
 		parseFunc: parseAlignContent,
 		setFunc:   setFunc(func(v *View, val FlexAlignContent) { v.Attrs.AlignContent = val }),
 	},
+	"align-self": {
+		parseFunc: parseAlignSelf,
+		setFunc:   setFunc(func(v *View, val FlexAlignSelf) { v.Attrs.AlignSelf = val }),
+	},
+	"order": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Attrs.Order = val }),
+	},
+	"overflow": {
+		parseFunc: parseOverflow,
+		setFunc:   setFunc(func(v *View, val FlexOverflow) { v.Attrs.Overflow = val }),
+	},
+	"scrollbar-width": {
+		parseFunc: parseScrollbarWidth,
+		setFunc:   setFunc(func(v *View, val int) { v.Attrs.ScrollbarWidth = val }),
+	},
+	"z-index": {
+		parseFunc: parseZIndex,
+		setFunc:   setFunc(func(v *View, val int) { v.Attrs.ZIndex = val }),
+	},
+	"color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.Attrs.Color = val }),
+	},
+	"font-family": {
+		parseFunc: parseFontFamily,
+		setFunc:   setFunc(func(v *View, val string) { v.Attrs.FontFamily = val }),
+	},
+	"font-size": {
+		parseFunc: parseFontSize,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.FontSize = val }),
+	},
+	"line-height": {
+		parseFunc: parseLineHeight,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.LineHeight = val }),
+	},
+	"text-align": {
+		parseFunc: parseTextAlign,
+		setFunc:   setFunc(func(v *View, val FlexTextAlign) { v.Attrs.TextAlign = val }),
+	},
+	"vertical-align": {
+		parseFunc: parseVerticalAlign,
+		setFunc:   setFunc(func(v *View, val FlexVerticalAlign) { v.Attrs.VerticalAlign = val }),
+	},
+	"white-space": {
+		parseFunc: parseWhiteSpace,
+		setFunc:   setFunc(func(v *View, val FlexWhiteSpace) { v.Attrs.WhiteSpace = val }),
+	},
+	"text-overflow": {
+		parseFunc: parseTextOverflow,
+		setFunc:   setFunc(func(v *View, val FlexTextOverflow) { v.Attrs.TextOverflow = val }),
+	},
+	"background": {
+		parseFunc: parseBackground,
+		setFunc: setFunc(func(v *View, val cssBackground) {
+			v.Attrs.BackgroundColor = val.color
+			v.Attrs.BackgroundImage = val.image
+		}),
+	},
+	"background-color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.Attrs.BackgroundColor = val }),
+	},
+	"background-image": {
+		parseFunc: parseImageName,
+		setFunc:   setFunc(func(v *View, val string) { v.Attrs.BackgroundImage = val }),
+	},
+	"border": {
+		parseFunc: parseBorder,
+		setFunc: setFunc(func(v *View, val cssBorder) {
+			v.Attrs.BorderWidth = val.width
+			v.Attrs.BorderColor = val.color
+		}),
+	},
+	"border-width": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Attrs.BorderWidth = val }),
+	},
+	"border-color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.Attrs.BorderColor = val }),
+	},
+	"border-image": {
+		parseFunc: parseBorderImage,
+		setFunc:   setFunc(func(v *View, val *NineSlice) { v.Attrs.BorderImage = val }),
+	},
+	"border-radius": {
+		parseFunc: parsePixels,
+		setFunc:   setFunc(func(v *View, val float64) { v.Attrs.BorderRadius = val }),
+	},
+	"opacity": {
+		parseFunc: parseOpacity,
+		setFunc:   setFunc(func(v *View, val float64) { v.Attrs.Opacity = &val }),
+	},
+	"scale": {
+		parseFunc: parseScale,
+		setFunc:   setFunc(func(v *View, val *float64) { v.Attrs.Scale = val }),
+	},
+	"transform": {
+		parseFunc: parseTransform,
+		setFunc:   setFunc(func(v *View, val []TransformFunc) { v.Attrs.Transform = val }),
+	},
+	"transform-origin": {
+		parseFunc: parseTransformOrigin,
+		setFunc: setFunc(func(v *View, val [2]Length) {
+			v.Attrs.TransformOriginX, v.Attrs.TransformOriginY = val[0], val[1]
+		}),
+	},
+	"transition": {
+		parseFunc: parseTransitions,
+		setFunc:   setFunc(func(v *View, val []Transition) { v.Attrs.Transitions = val }),
+	},
+	"animation": {
+		parseFunc: parseAnimations,
+		setFunc:   setFunc(func(v *View, val []Animation) { v.SetAnimations(val...) }),
+	},
+	"box-shadow": {
+		parseFunc: parseBoxShadow,
+		setFunc:   setFunc(func(v *View, val *Shadow) { v.Attrs.BoxShadow = val }),
+	},
 	"flex-grow": {
 		parseFunc: parseFloat,
 		setFunc:   setFunc(func(v *View, val float64) { v.Attrs.Grow = val }),
@@ -368,6 +1079,18 @@ var styleMapper = map[string]mapper[View]{
 		parseFunc: parseFloat,
 		setFunc:   setFunc(func(v *View, val float64) { v.Attrs.Shrink = val }),
 	},
+	"flex-basis": {
+		parseFunc: parseBasis,
+		setFunc:   setFunc(func(v *View, val Length) { v.Attrs.Basis = val }),
+	},
+	"flex": {
+		parseFunc: parseFlex,
+		setFunc: setFunc(func(v *View, val cssFlex) {
+			v.Attrs.Grow = val.grow
+			v.Attrs.Shrink = val.shrink
+			v.Attrs.Basis = val.basis
+		}),
+	},
 	"display": {
 		parseFunc: parseDisplay,
 		setFunc:   setFunc(func(v *View, val FlexDisplay) { v.Attrs.Display = val }),
@@ -434,16 +1157,696 @@ func parseNumber(val string) (any, error) {
 	return strconv.Atoi(val)
 }
 
-func parseFloat(val string) (any, error) {
-	return strconv.ParseFloat(val, 64)
-}
+func parseOverflow(val string) (any, error) {
+	switch val {
+	case "visible":
+		return OverflowVisible, nil
+	case "hidden", "clip":
+		return OverflowHidden, nil
+	case "scroll", "auto":
+		return OverflowScroll, nil
+	}
+	return OverflowVisible, fmt.Errorf("unknown overflow: %s", val)
+}
+
+func parseScrollbarWidth(val string) (any, error) {
+	switch val {
+	case "none":
+		return 0, nil
+	case "thin":
+		return 4, nil
+	case "auto":
+		return 8, nil
+	}
+	return parseNumber(val)
+}
+
+func parseZIndex(val string) (any, error) {
+	if val == "auto" {
+		return 0, nil
+	}
+	return strconv.Atoi(val)
+}
+
+var namedColors = map[string]color.NRGBA{
+	"transparent": {},
+	"black":       {0x00, 0x00, 0x00, 0xff},
+	"white":       {0xff, 0xff, 0xff, 0xff},
+	"gray":        {0x80, 0x80, 0x80, 0xff},
+	"grey":        {0x80, 0x80, 0x80, 0xff},
+	"silver":      {0xc0, 0xc0, 0xc0, 0xff},
+	"red":         {0xff, 0x00, 0x00, 0xff},
+	"maroon":      {0x80, 0x00, 0x00, 0xff},
+	"orange":      {0xff, 0xa5, 0x00, 0xff},
+	"yellow":      {0xff, 0xff, 0x00, 0xff},
+	"olive":       {0x80, 0x80, 0x00, 0xff},
+	"lime":        {0x00, 0xff, 0x00, 0xff},
+	"green":       {0x00, 0x80, 0x00, 0xff},
+	"aqua":        {0x00, 0xff, 0xff, 0xff},
+	"cyan":        {0x00, 0xff, 0xff, 0xff},
+	"teal":        {0x00, 0x80, 0x80, 0xff},
+	"blue":        {0x00, 0x00, 0xff, 0xff},
+	"navy":        {0x00, 0x00, 0x80, 0xff},
+	"fuchsia":     {0xff, 0x00, 0xff, 0xff},
+	"magenta":     {0xff, 0x00, 0xff, 0xff},
+	"purple":      {0x80, 0x00, 0x80, 0xff},
+}
+
+// parseColor parses a named color, a hex color (#rgb, #rgba, #rrggbb or #rrggbbaa),
+// or an rgb() or rgba() color.
+func parseColor(val string) (any, error) {
+	val = strings.ToLower(val)
+	if c, ok := namedColors[val]; ok {
+		return c, nil
+	}
+	if strings.HasPrefix(val, "#") {
+		return parseHexColor(val)
+	}
+	for _, fn := range []string{"rgba(", "rgb("} {
+		if !strings.HasPrefix(val, fn) || !strings.HasSuffix(val, ")") {
+			continue
+		}
+		args := strings.FieldsFunc(val[len(fn):len(val)-1], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '/'
+		})
+		if len(args) != 3 && len(args) != 4 {
+			break
+		}
+		c := color.NRGBA{A: 0xff}
+		for i, ch := range []*uint8{&c.R, &c.G, &c.B, &c.A} {
+			if i == len(args) {
+				break
+			}
+			max := 255.0
+			if i == 3 {
+				max = 1
+			}
+			n, err := parseColorComponent(args[i], max)
+			if err != nil {
+				return nil, fmt.Errorf("invalid color: %s", val)
+			}
+			*ch = n
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("invalid color: %s", val)
+}
+
+func parseHexColor(val string) (any, error) {
+	hex := val[1:]
+	if len(hex) == 3 || len(hex) == 4 {
+		var sb strings.Builder
+		for _, r := range hex {
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		}
+		hex = sb.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color: %s", val)
+	}
+	return color.NRGBA{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+}
+
+// parseColorComponent parses a number from 0 to max, or a percentage, into a byte.
+func parseColorComponent(val string, max float64) (uint8, error) {
+	if strings.HasSuffix(val, "%") {
+		val, max = strings.TrimSuffix(val, "%"), 100
+	}
+	n, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(math.Round(clamp(n/max, 0, 1) * 255)), nil
+}
+
+// parseFontFamily returns the first font family of the list.
+func parseFontFamily(val string) (any, error) {
+	family := strings.TrimSpace(strings.Split(val, ",")[0])
+	return strings.Trim(family, `"'`), nil
+}
+
+// parseFontSize parses a font size. Em, rem and percent sizes are resolved
+// in the layout, against the font size of the parent or the root view.
+func parseFontSize(val string) (any, error) {
+	parsed, err := parseLength(val)
+	if err != nil {
+		return Auto, err
+	}
+	l := parsed.(Length)
+	switch l.Unit {
+	case LengthPx, LengthEm, LengthRem, LengthPct:
+		return l, nil
+	}
+	return Auto, fmt.Errorf("invalid font size: %s", val)
+}
+
+// parseLineHeight parses a line height. A number without a unit is relative to the font size.
+func parseLineHeight(val string) (any, error) {
+	if val == "normal" {
+		return Auto, nil
+	}
+	if n, err := strconv.ParseFloat(val, 64); err == nil {
+		return Length{Value: n, Unit: LengthEm}, nil
+	}
+	return parseLength(val)
+}
+
+func parseTextAlign(val string) (any, error) {
+	switch val {
+	case "left", "start":
+		return TextAlignLeft, nil
+	case "center":
+		return TextAlignCenter, nil
+	case "right", "end":
+		return TextAlignRight, nil
+	case "inherit":
+		return TextAlignInherit, nil
+	}
+	return TextAlignInherit, fmt.Errorf("unknown text align: %s", val)
+}
+
+func parseVerticalAlign(val string) (any, error) {
+	switch val {
+	case "top":
+		return VerticalAlignTop, nil
+	case "middle":
+		return VerticalAlignMiddle, nil
+	case "bottom":
+		return VerticalAlignBottom, nil
+	}
+	return VerticalAlignTop, fmt.Errorf("unknown vertical align: %s", val)
+}
+
+func parseWhiteSpace(val string) (any, error) {
+	switch val {
+	case "normal":
+		return WhiteSpaceNormal, nil
+	case "nowrap":
+		return WhiteSpaceNoWrap, nil
+	case "pre":
+		return WhiteSpacePre, nil
+	case "pre-wrap":
+		return WhiteSpacePreWrap, nil
+	case "pre-line":
+		return WhiteSpacePreLine, nil
+	case "inherit":
+		return WhiteSpaceInherit, nil
+	}
+	return WhiteSpaceInherit, fmt.Errorf("unknown white space: %s", val)
+}
+
+func parseTextOverflow(val string) (any, error) {
+	switch val {
+	case "clip":
+		return TextOverflowClip, nil
+	case "ellipsis":
+		return TextOverflowEllipsis, nil
+	}
+	return TextOverflowClip, fmt.Errorf("unknown text overflow: %s", val)
+}
+
+func parseFloat(val string) (any, error) {
+	return strconv.ParseFloat(val, 64)
+}
+
+// parsePixels parses a length in pixels into a float64.
+func parsePixels(val string) (any, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(val, "px"), 64)
+}
+
+// parseImageName parses url(name) into the name of a registered image.
+func parseImageName(val string) (any, error) {
+	if val == "none" {
+		return "", nil
+	}
+	if !strings.HasPrefix(val, "url(") || !strings.HasSuffix(val, ")") {
+		return "", fmt.Errorf("invalid image: %s", val)
+	}
+	return strings.Trim(strings.TrimSpace(val[len("url("):len(val)-1]), `"'`), nil
+}
+
+type cssBackground struct {
+	color color.Color
+	image string
+}
+
+// parseBackground parses the 'background' shorthand of a color and an image.
+func parseBackground(val string) (any, error) {
+	bg := cssBackground{}
+	if val == "none" {
+		return bg, nil
+	}
+	for _, v := range splitLengths(val) {
+		if strings.HasPrefix(v, "url(") {
+			img, err := parseImageName(v)
+			if err != nil {
+				return bg, err
+			}
+			bg.image = img.(string)
+			continue
+		}
+		c, err := parseColor(v)
+		if err != nil {
+			return bg, fmt.Errorf("invalid background: %s", val)
+		}
+		bg.color = c.(color.Color)
+	}
+	return bg, nil
+}
+
+type cssBorder struct {
+	width int
+	color color.Color
+}
+
+// parseBorder parses the 'border' shorthand of a width, a style and a color.
+// Borders are drawn solid in all the styles but none and hidden.
+func parseBorder(val string) (any, error) {
+	b := cssBorder{}
+	none := false
+	for _, v := range splitLengths(val) {
+		switch v {
+		case "none", "hidden":
+			none = true
+			continue
+		case "solid", "dashed", "dotted", "double", "groove", "ridge", "inset", "outset":
+			continue
+		}
+		if w, err := parseNumber(v); err == nil {
+			b.width = w.(int)
+			continue
+		}
+		c, err := parseColor(v)
+		if err != nil {
+			return b, fmt.Errorf("invalid border: %s", val)
+		}
+		b.color = c.(color.Color)
+	}
+	if none {
+		b.width = 0
+	}
+	return b, nil
+}
+
+// parseBorderImage parses the 'border-image' shorthand of an image, one to four
+// slice sizes in pixels, the fill keyword and a repeat keyword, such as
+// "url(panel) 32 fill round".
+func parseBorderImage(val string) (any, error) {
+	if val == "none" {
+		return (*NineSlice)(nil), nil
+	}
+	b := &NineSlice{}
+	var slices []int
+	for _, v := range splitLengths(val) {
+		switch v {
+		case "fill":
+			b.Fill = true
+			continue
+		case "stretch":
+			b.Repeat = RepeatStretch
+			continue
+		case "repeat":
+			b.Repeat = RepeatTile
+			continue
+		case "round":
+			b.Repeat = RepeatRound
+			continue
+		}
+		if strings.HasPrefix(v, "url(") {
+			img, err := parseImageName(v)
+			if err != nil {
+				return (*NineSlice)(nil), err
+			}
+			b.Image = img.(string)
+			continue
+		}
+		n, err := parseNumber(v)
+		if err != nil {
+			return (*NineSlice)(nil), fmt.Errorf("invalid border image: %s", val)
+		}
+		slices = append(slices, n.(int))
+	}
+	if b.Image == "" {
+		return (*NineSlice)(nil), fmt.Errorf("border image without an image: %s", val)
+	}
+	switch len(slices) {
+	case 1:
+		b.Top, b.Right, b.Bottom, b.Left = slices[0], slices[0], slices[0], slices[0]
+	case 2:
+		b.Top, b.Right, b.Bottom, b.Left = slices[0], slices[1], slices[0], slices[1]
+	case 3:
+		b.Top, b.Right, b.Bottom, b.Left = slices[0], slices[1], slices[2], slices[1]
+	case 4:
+		b.Top, b.Right, b.Bottom, b.Left = slices[0], slices[1], slices[2], slices[3]
+	default:
+		return (*NineSlice)(nil), fmt.Errorf("invalid number of slices: %s", val)
+	}
+	return b, nil
+}
+
+// parseOpacity parses a number or a percentage, clamped from 0 to 1.
+func parseOpacity(val string) (any, error) {
+	max := 1.0
+	if strings.HasSuffix(val, "%") {
+		val, max = strings.TrimSuffix(val, "%"), 100
+	}
+	n, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 1.0, err
+	}
+	return clamp(n/max, 0, 1), nil
+}
+
+// parseBoxShadow parses a shadow of two to four lengths and a color.
+// Shadows without a color are black.
+func parseBoxShadow(val string) (any, error) {
+	if val == "none" {
+		return (*Shadow)(nil), nil
+	}
+	s := &Shadow{Color: color.Black}
+	var lengths []float64
+	for _, v := range splitLengths(val) {
+		if v == "inset" {
+			return (*Shadow)(nil), fmt.Errorf("inset shadows are not supported: %s", val)
+		}
+		if n, err := parsePixels(v); err == nil {
+			lengths = append(lengths, n.(float64))
+			continue
+		}
+		c, err := parseColor(v)
+		if err != nil {
+			return (*Shadow)(nil), fmt.Errorf("invalid box shadow: %s", val)
+		}
+		s.Color = c.(color.Color)
+	}
+	if len(lengths) < 2 || len(lengths) > 4 {
+		return (*Shadow)(nil), fmt.Errorf("invalid box shadow: %s", val)
+	}
+	s.OffsetX, s.OffsetY = lengths[0], lengths[1]
+	if len(lengths) > 2 {
+		s.Blur = lengths[2]
+	}
+	if len(lengths) > 3 {
+		s.Spread = lengths[3]
+	}
+	return s, nil
+}
+
+// parseScale parses a scale of a number or a percentage, or none.
+func parseScale(val string) (any, error) {
+	if val == "none" {
+		return (*float64)(nil), nil
+	}
+	max := 1.0
+	if strings.HasSuffix(val, "%") {
+		val, max = strings.TrimSuffix(val, "%"), 100
+	}
+	n, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return (*float64)(nil), err
+	}
+	n /= max
+	return &n, nil
+}
+
+// parseTransform parses a list of transform functions separated by spaces,
+// such as "translate(10px, 20px) rotate(45deg) scale(1.5)". Translations are in pixels.
+func parseTransform(val string) (any, error) {
+	if val == "none" {
+		return []TransformFunc(nil), nil
+	}
+	var funcs []TransformFunc
+	for _, v := range splitLengths(val) {
+		open := strings.Index(v, "(")
+		if open < 0 || !strings.HasSuffix(v, ")") {
+			return []TransformFunc(nil), fmt.Errorf("invalid transform: %s", v)
+		}
+		name := v[:open]
+		var args []float64
+		for _, arg := range strings.Split(v[open+1:len(v)-1], ",") {
+			arg = strings.TrimSpace(arg)
+			var n any
+			var err error
+			switch name {
+			case "translate", "translateX", "translateY":
+				n, err = parsePixels(arg)
+			case "rotate":
+				n, err = parseAngle(arg)
+			default:
+				n, err = parseFloat(arg)
+			}
+			if err != nil {
+				return []TransformFunc(nil), fmt.Errorf("invalid transform: %s", v)
+			}
+			args = append(args, n.(float64))
+		}
+		var f TransformFunc
+		switch {
+		case name == "translate" && len(args) <= 2:
+			f = TransformFunc{Func: TransformTranslate, X: args[0]}
+			if len(args) == 2 {
+				f.Y = args[1]
+			}
+		case name == "translateX" && len(args) == 1:
+			f = TransformFunc{Func: TransformTranslate, X: args[0]}
+		case name == "translateY" && len(args) == 1:
+			f = TransformFunc{Func: TransformTranslate, Y: args[0]}
+		case name == "scale" && len(args) <= 2:
+			f = TransformFunc{Func: TransformScale, X: args[0], Y: args[len(args)-1]}
+		case name == "scaleX" && len(args) == 1:
+			f = TransformFunc{Func: TransformScale, X: args[0], Y: 1}
+		case name == "scaleY" && len(args) == 1:
+			f = TransformFunc{Func: TransformScale, X: 1, Y: args[0]}
+		case name == "rotate" && len(args) == 1:
+			f = TransformFunc{Func: TransformRotate, X: args[0]}
+		default:
+			return []TransformFunc(nil), fmt.Errorf("invalid transform: %s", v)
+		}
+		funcs = append(funcs, f)
+	}
+	return funcs, nil
+}
+
+// parseAngle parses an angle in deg, rad, grad or turn into radians.
+func parseAngle(val string) (any, error) {
+	for _, u := range []struct {
+		suffix string
+		scale  float64
+	}{{"deg", math.Pi / 180}, {"grad", math.Pi / 200}, {"rad", 1}, {"turn", 2 * math.Pi}} {
+		if strings.HasSuffix(val, u.suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(val, u.suffix), 64)
+			if err != nil {
+				return 0.0, fmt.Errorf("invalid angle: %s", val)
+			}
+			return n * u.scale, nil
+		}
+	}
+	if val == "0" {
+		return 0.0, nil
+	}
+	return 0.0, fmt.Errorf("invalid angle: %s", val)
+}
+
+// parseTransformOrigin parses one or two lengths or the keywords left, center,
+// right, top and bottom.
+func parseTransformOrigin(val string) (any, error) {
+	keywords := map[string]Length{
+		"left": Pct(0), "right": Pct(100), "top": Pct(0), "bottom": Pct(100), "center": Pct(50),
+	}
+	values := splitLengths(val)
+	if len(values) == 0 || len(values) > 2 {
+		return [2]Length{}, fmt.Errorf("invalid transform origin: %s", val)
+	}
+	// A single value or vertical keywords first are swapped to the right order.
+	if len(values) == 1 {
+		if values[0] == "top" || values[0] == "bottom" {
+			values = []string{"center", values[0]}
+		} else {
+			values = append(values, "center")
+		}
+	} else if values[0] == "top" || values[0] == "bottom" || values[1] == "left" || values[1] == "right" {
+		values[0], values[1] = values[1], values[0]
+	}
+	var origin [2]Length
+	for i, v := range values {
+		if l, ok := keywords[v]; ok {
+			origin[i] = l
+			continue
+		}
+		l, err := parseLength(v)
+		if err != nil || l.(Length).IsAuto() {
+			return [2]Length{}, fmt.Errorf("invalid transform origin: %s", val)
+		}
+		origin[i] = l.(Length)
+	}
+	return origin, nil
+}
+
+// parseTransitions parses a list of transitions separated by commas,
+// each of a property, a duration, an easing and a delay, such as
+// "left 200ms ease-out, opacity 1s linear 0.5s".
+func parseTransitions(val string) (any, error) {
+	if val == "none" {
+		return []Transition(nil), nil
+	}
+	var transitions []Transition
+	for _, item := range splitList(val) {
+		t := Transition{Property: PropertyAll}
+		durations := 0
+		for _, v := range splitLengths(item) {
+			if d, err := parseDuration(v); err == nil {
+				if durations == 0 {
+					t.Duration = d
+				} else if durations == 1 {
+					t.Delay = d
+				} else {
+					return []Transition(nil), fmt.Errorf("invalid transition: %s", item)
+				}
+				durations++
+				continue
+			}
+			if e, err := parseEasing(v); err == nil {
+				t.Easing = e
+				continue
+			}
+			p, ok := propertiesByName[v]
+			if !ok {
+				return []Transition(nil), fmt.Errorf("invalid transition: %s", item)
+			}
+			t.Property = p
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, nil
+}
+
+// parseAnimations parses a list of animations separated by commas, each of
+// the name of keyframes, a duration, an easing, a delay, an iteration count,
+// a direction and a fill mode, such as "pulse 1s ease-in-out infinite alternate".
+func parseAnimations(val string) (any, error) {
+	if val == "none" {
+		return []Animation(nil), nil
+	}
+	var animations []Animation
+	for _, item := range splitList(val) {
+		a := Animation{}
+		durations := 0
+		for _, v := range splitLengths(item) {
+			if d, err := parseDuration(v); err == nil {
+				if durations == 0 {
+					a.Duration = d
+				} else if durations == 1 {
+					a.Delay = d
+				} else {
+					return []Animation(nil), fmt.Errorf("invalid animation: %s", item)
+				}
+				durations++
+				continue
+			}
+			if e, err := parseEasing(v); err == nil {
+				a.Easing = e
+				continue
+			}
+			if n, err := strconv.Atoi(v); err == nil {
+				if n < 1 {
+					return []Animation(nil), fmt.Errorf("invalid iteration count: %s", item)
+				}
+				a.Repeat = n - 1
+				continue
+			}
+			switch v {
+			case "infinite":
+				a.Repeat = -1
+			case "normal", "none", "backwards", "running":
+			case "reverse":
+				a.Reverse = true
+			case "alternate":
+				a.Alternate = true
+			case "alternate-reverse":
+				a.Alternate, a.Reverse = true, true
+			case "forwards", "both":
+				a.Forwards = true
+			default:
+				if a.Name != "" {
+					return []Animation(nil), fmt.Errorf("invalid animation: %s", item)
+				}
+				a.Name = v
+			}
+		}
+		if a.Name == "" {
+			return []Animation(nil), fmt.Errorf("animation without a name: %s", item)
+		}
+		animations = append(animations, a)
+	}
+	return animations, nil
+}
+
+// propertiesByName are the properties that can be animated by their CSS names.
+var propertiesByName = func() map[string]FlexProperty {
+	m := map[string]FlexProperty{}
+	for p, name := range propertyNames {
+		m[name] = FlexProperty(p)
+	}
+	return m
+}()
+
+// parseDuration parses a time in seconds or milliseconds, such as "0.2s" or "200ms".
+func parseDuration(val string) (time.Duration, error) {
+	unit := time.Second
+	switch {
+	case strings.HasSuffix(val, "ms"):
+		val, unit = strings.TrimSuffix(val, "ms"), time.Millisecond
+	case strings.HasSuffix(val, "s"):
+		val = strings.TrimSuffix(val, "s")
+	default:
+		return 0, fmt.Errorf("invalid duration: %s", val)
+	}
+	n, err := strconv.ParseFloat(val, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", val)
+	}
+	return time.Duration(n * float64(unit)), nil
+}
+
+// parseEasing parses the name of an easing or cubic-bezier(x1, y1, x2, y2).
+func parseEasing(val string) (Easing, error) {
+	if e, ok := easingsByName[val]; ok {
+		return e, nil
+	}
+	if !strings.HasPrefix(val, "cubic-bezier(") || !strings.HasSuffix(val, ")") {
+		return nil, fmt.Errorf("invalid easing: %s", val)
+	}
+	args := strings.Split(strings.TrimSuffix(strings.TrimPrefix(val, "cubic-bezier("), ")"), ",")
+	if len(args) != 4 {
+		return nil, fmt.Errorf("invalid easing: %s", val)
+	}
+	var n [4]float64
+	for i, arg := range args {
+		f, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid easing: %s", val)
+		}
+		n[i] = f
+	}
+	if n[0] < 0 || n[0] > 1 || n[2] < 0 || n[2] > 1 {
+		return nil, fmt.Errorf("invalid easing: %s", val)
+	}
+	return CubicBezier{n[0], n[1], n[2], n[3]}, nil
+}
 
 func parsePosition(val string) (any, error) {
 	switch val {
 	case "absolute":
 		return PositionAbsolute, nil
-	case "static", "relative":
+	case "static":
 		return PositionStatic, nil
+	case "relative":
+		return PositionRelative, nil
 	}
 	return PositionStatic, fmt.Errorf("unknown position: %s", val)
 }
@@ -498,6 +1901,22 @@ func parseAlignItem(val string) (any, error) {
 	return AlignItemStretch, fmt.Errorf("unknown align-items: %s", val)
 }
 
+func parseAlignSelf(val string) (any, error) {
+	switch val {
+	case "auto":
+		return AlignSelfAuto, nil
+	case "flex-start", "start":
+		return AlignSelfStart, nil
+	case "flex-end", "end":
+		return AlignSelfEnd, nil
+	case "center":
+		return AlignSelfCenter, nil
+	case "stretch":
+		return AlignSelfStretch, nil
+	}
+	return AlignSelfAuto, fmt.Errorf("unknown align-self: %s", val)
+}
+
 func parseAlignContent(val string) (any, error) {
 	switch val {
 	case "flex-start", "start":
@@ -526,35 +1945,399 @@ func parseDisplay(val string) (any, error) {
 	return DisplayFlex, fmt.Errorf("unknown display: %s", val)
 }
 
-type cssLength struct {
-	unit cssUnit
-	val  float64
+// parseLength parses a length: auto | <number><unit>? | calc(<expression>).
+// The units are px, %, em, rem, vw and vh. Numbers without a unit are pixels.
+func parseLength(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "auto" {
+		return Auto, nil
+	}
+	if strings.HasPrefix(val, "calc(") && strings.HasSuffix(val, ")") {
+		return parseCalc(val[len("calc(") : len(val)-1])
+	}
+	for _, u := range []struct {
+		suffix string
+		unit   LengthUnit
+	}{
+		{"px", LengthPx},
+		{"%", LengthPct},
+		{"rem", LengthRem},
+		{"em", LengthEm},
+		{"vw", LengthVw},
+		{"vh", LengthVh},
+		{"", LengthPx},
+	} {
+		if !strings.HasSuffix(val, u.suffix) {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.TrimSuffix(val, u.suffix), 64)
+		if err != nil {
+			return Auto, fmt.Errorf("invalid length: %s", val)
+		}
+		return Length{Value: n, Unit: u.unit}, nil
+	}
+	return Auto, fmt.Errorf("invalid length: %s", val)
 }
 
-func parseLength(val string) (any, error) {
-	switch {
-	case strings.HasSuffix(val, "%"):
-		val = strings.TrimSuffix(val, "%")
-		v, err := parseFloat(val)
-		if err != nil || v.(float64) <= 0 {
-			return cssLength{}, nil
+// parseCalc parses the expression of calc(). Lengths can be added and subtracted,
+// and multiplied or divided by numbers.
+func parseCalc(expr string) (Length, error) {
+	p := &calcParser{s: expr}
+	v, err := p.sum()
+	if err == nil && p.skipSpace() < len(p.s) {
+		err = fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+	if err == nil && v.isNum {
+		err = fmt.Errorf("not a length")
+	}
+	if err != nil {
+		return Auto, fmt.Errorf("invalid calc(%s): %v", expr, err)
+	}
+	return Length{Unit: LengthCalc, calc: &v.sum}, nil
+}
+
+// calcValue is an intermediate value of a calc() expression,
+// either a plain number or a sum of lengths.
+type calcValue struct {
+	sum   calcSum
+	num   float64
+	isNum bool
+}
+
+type calcParser struct {
+	s   string
+	pos int
+}
+
+func (p *calcParser) skipSpace() int {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+	return p.pos
+}
+
+// sum parses <product> [('+' | '-') <product>]*.
+func (p *calcParser) sum() (calcValue, error) {
+	v, err := p.product()
+	if err != nil {
+		return v, err
+	}
+	for p.skipSpace() < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+		sign := 1.0
+		if p.s[p.pos] == '-' {
+			sign = -1
 		}
-		return cssLength{unit: cssUnitPct, val: v.(float64)}, nil
-	default:
-		val = strings.TrimSuffix(val, "px")
-		v, err := parseFloat(val)
+		p.pos++
+		rhs, err := p.product()
+		if err != nil {
+			return v, err
+		}
+		if v.isNum != rhs.isNum {
+			return v, fmt.Errorf("cannot add a number and a length")
+		}
+		v.num += sign * rhs.num
+		for u := range v.sum {
+			v.sum[u] += sign * rhs.sum[u]
+		}
+	}
+	return v, nil
+}
+
+// product parses <value> [('*' | '/') <value>]*.
+func (p *calcParser) product() (calcValue, error) {
+	v, err := p.value()
+	if err != nil {
+		return v, err
+	}
+	for p.skipSpace() < len(p.s) && (p.s[p.pos] == '*' || p.s[p.pos] == '/') {
+		op := p.s[p.pos]
+		p.pos++
+		rhs, err := p.value()
+		if err != nil {
+			return v, err
+		}
+		switch {
+		case op == '/' && (!rhs.isNum || rhs.num == 0):
+			return v, fmt.Errorf("division by a length or zero")
+		case op == '/':
+			v = v.scale(1 / rhs.num)
+		case v.isNum:
+			v = rhs.scale(v.num)
+		case rhs.isNum:
+			v = v.scale(rhs.num)
+		default:
+			return v, fmt.Errorf("cannot multiply lengths")
+		}
+	}
+	return v, nil
+}
+
+// value parses a number, a length or a parenthesized expression.
+func (p *calcParser) value() (calcValue, error) {
+	start := p.skipSpace()
+	if strings.HasPrefix(p.s[p.pos:], "(") || strings.HasPrefix(p.s[p.pos:], "calc(") {
+		p.pos = strings.IndexByte(p.s[p.pos:], '(') + p.pos + 1
+		v, err := p.sum()
 		if err != nil {
-			return cssLength{}, nil
+			return v, err
 		}
-		return cssLength{unit: cssUnitPx, val: v.(float64)}, nil
+		if p.skipSpace() >= len(p.s) || p.s[p.pos] != ')' {
+			return v, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return v, nil
+	}
+	// A sign at the start of a value belongs to the number.
+	if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+		p.pos++
+	}
+	for p.pos < len(p.s) && strings.IndexByte(" +-*/()", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	tok := p.s[start:p.pos]
+	if n, err := strconv.ParseFloat(tok, 64); err == nil {
+		return calcValue{num: n, isNum: true}, nil
+	}
+	l, err := parseLength(tok)
+	if err != nil || tok == "auto" {
+		return calcValue{}, fmt.Errorf("invalid value %q", tok)
+	}
+	v := calcValue{}
+	v.sum.add(l.(Length), 1)
+	return v, nil
+}
+
+func (v calcValue) scale(n float64) calcValue {
+	v.num *= n
+	for u := range v.sum {
+		v.sum[u] *= n
+	}
+	return v
+}
+
+// splitLengths splits a list of lengths or other values separated by spaces,
+// keeping the spaces in parentheses such as calc() expressions and rgb() colors.
+func splitLengths(val string) []string {
+	var fields []string
+	depth, start := 0, -1
+	for i, r := range val {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ' ' && depth == 0:
+			if start >= 0 {
+				fields = append(fields, val[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, val[start:])
 	}
+	return fields
+}
+
+// cssEdges is the value of a box shorthand property such as padding.
+type cssEdges struct {
+	top, right, bottom, left Length
+}
+
+// splitList splits a list of values separated by commas, keeping the commas
+// in parentheses such as cubic-bezier() easings.
+func splitList(val string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range val {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(val[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(val[start:]))
+}
+
+// parseEdges parses the 1-, 2-, 3- and 4-value syntax of box shorthand properties.
+func parseEdges(val string) (any, error) {
+	fields := splitLengths(val)
+	nums := make([]Length, len(fields))
+	for i, f := range fields {
+		n, err := parseLength(f)
+		if err != nil {
+			return cssEdges{}, err
+		}
+		nums[i] = n.(Length)
+	}
+	switch len(nums) {
+	case 1:
+		return cssEdges{nums[0], nums[0], nums[0], nums[0]}, nil
+	case 2:
+		return cssEdges{nums[0], nums[1], nums[0], nums[1]}, nil
+	case 3:
+		return cssEdges{nums[0], nums[1], nums[2], nums[1]}, nil
+	case 4:
+		return cssEdges{nums[0], nums[1], nums[2], nums[3]}, nil
+	}
+	return cssEdges{}, fmt.Errorf("invalid number of values: %s", val)
+}
+
+// cssGap is the value of the gap shorthand property.
+type cssGap struct {
+	row, column int
+}
+
+// parseGap parses the gap shorthand: <row-gap> <column-gap>?.
+func parseGap(val string) (any, error) {
+	fields := strings.Fields(val)
+	if len(fields) == 0 || len(fields) > 2 {
+		return cssGap{}, fmt.Errorf("invalid gap: %s", val)
+	}
+	nums := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := parseNumber(f)
+		if err != nil {
+			return cssGap{}, err
+		}
+		nums[i] = n.(int)
+	}
+	if len(nums) == 1 {
+		return cssGap{row: nums[0], column: nums[0]}, nil
+	}
+	return cssGap{row: nums[0], column: nums[1]}, nil
+}
+
+// cssFlex is the value of the flex shorthand property.
+type cssFlex struct {
+	grow, shrink float64
+	basis        Length
+}
+
+// parseFlex parses the flex shorthand: none | auto | initial | <grow> <shrink>? || <basis>.
+func parseFlex(val string) (any, error) {
+	switch val {
+	case "none":
+		return cssFlex{grow: 0, shrink: 0, basis: Auto}, nil
+	case "auto":
+		return cssFlex{grow: 1, shrink: 1, basis: Auto}, nil
+	case "initial":
+		return cssFlex{grow: 0, shrink: 1, basis: Auto}, nil
+	}
+
+	fields := splitLengths(val)
+	if len(fields) == 0 || len(fields) > 3 {
+		return cssFlex{}, fmt.Errorf("invalid flex: %s", val)
+	}
+
+	// Unitless numbers are the grow and shrink factors, which must be adjacent.
+	// Anything else is the basis.
+	ret := cssFlex{grow: 1, shrink: 1, basis: Px(0)}
+	factors, hasBasis, prevIsFactor := 0, false, false
+	for _, f := range fields {
+		n, err := strconv.ParseFloat(f, 64)
+		switch {
+		case err == nil && (factors == 0 || (factors == 1 && prevIsFactor)):
+			if factors == 0 {
+				ret.grow = n
+			} else {
+				ret.shrink = n
+			}
+			factors++
+			prevIsFactor = true
+		case !hasBasis:
+			b, err := parseBasis(f)
+			if err != nil {
+				return cssFlex{}, fmt.Errorf("invalid flex: %s", val)
+			}
+			ret.basis = b.(Length)
+			hasBasis = true
+			prevIsFactor = false
+		default:
+			return cssFlex{}, fmt.Errorf("invalid flex: %s", val)
+		}
+	}
+	return ret, nil
+}
+
+// parseBasis parses a flex basis: auto | content | <number>px? | <number>%.
+func parseBasis(val string) (any, error) {
+	switch val {
+	case "auto":
+		return Auto, nil
+	case "content":
+		return Content, nil
+	}
+	l, err := parseLength(val)
+	if err != nil {
+		return Auto, fmt.Errorf("invalid flex-basis: %s", val)
+	}
+	return l, nil
+}
+
+// parseSizeConstraint parses a min or max size: auto | none | <number>px? | <number>%.
+func parseSizeConstraint(val string) (any, error) {
+	switch val {
+	case "auto", "none":
+		return Auto, nil
+	}
+	l, err := parseLength(val)
+	if err != nil {
+		return Auto, fmt.Errorf("invalid size: %s", val)
+	}
+	return l, nil
+}
+
+// cssFlexFlow is the value of the flex-flow shorthand property.
+type cssFlexFlow struct {
+	direction FlexDirection
+	wrap      FlexWrap
+}
+
+// parseFlexFlow parses the flex-flow shorthand: <direction> || <wrap>.
+func parseFlexFlow(val string) (any, error) {
+	ret := cssFlexFlow{direction: Row, wrap: NoWrap}
+	fields := strings.Fields(val)
+	if len(fields) == 0 || len(fields) > 2 {
+		return ret, fmt.Errorf("invalid flex-flow: %s", val)
+	}
+	var hasDirection, hasWrap bool
+	for _, f := range fields {
+		if d, err := parseDirection(f); err == nil && !hasDirection {
+			ret.direction = d.(FlexDirection)
+			hasDirection = true
+			continue
+		}
+		if w, err := parseWrap(f); err == nil && !hasWrap {
+			ret.wrap = w.(FlexWrap)
+			hasWrap = true
+			continue
+		}
+		return ret, fmt.Errorf("invalid flex-flow: %s", val)
+	}
+	return ret, nil
 }
 
 type attrs struct {
-	id     string
-	style  string
-	hidden bool
-	miscs  map[string]string
+	id        string
+	style     string
+	hidden    bool
+	cache     bool
+	drawText  bool
+	focusable bool
+	tabIndex  int
+	nav       [4]string
+	miscs     map[string]string
+	// line and column are the position of the tag in the document given to ParseE.
+	line, column int
 }
 
 func readAttrs(z *html.Tokenizer) attrs {
@@ -576,6 +2359,27 @@ func readAttrs(z *html.Tokenizer) attrs {
 			} else {
 				attr.hidden = parseBool(v)
 			}
+		case "cache":
+			v := string(val)
+			attr.cache = v == "" || parseBool(v)
+		case "draw-text":
+			v := string(val)
+			attr.drawText = v == "" || parseBool(v)
+		case "tabindex":
+			if i, err := strconv.Atoi(string(val)); err == nil {
+				attr.focusable, attr.tabIndex = true, i
+			}
+		case "nav-up":
+			attr.nav[NavActionUp] = strings.TrimPrefix(string(val), "#")
+		case "nav-down":
+			attr.nav[NavActionDown] = strings.TrimPrefix(string(val), "#")
+		case "nav-left":
+			attr.nav[NavActionLeft] = strings.TrimPrefix(string(val), "#")
+		case "nav-right":
+			attr.nav[NavActionRight] = strings.TrimPrefix(string(val), "#")
+		case positionAttr:
+			delete(attr.miscs, positionAttr)
+			fmt.Sscanf(string(val), "%d:%d", &attr.line, &attr.column)
 		}
 		if !more {
 			break
@@ -587,10 +2391,3 @@ func readAttrs(z *html.Tokenizer) attrs {
 func parseBool(val string) bool {
 	return val == "true"
 }
-
-type cssUnit int
-
-const (
-	cssUnitPx cssUnit = iota
-	cssUnitPct
-)
@@ -1,7 +1,13 @@
 package furex
 
 import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -42,14 +48,14 @@ func TestParseHTML(t *testing.T) {
 				</body>`,
 			expected: (&View{
 				Attrs: ViewAttrs{
-					Left:         50,
-					Top:          100,
-					Width:        200,
-					Height:       300,
-					MarginLeft:   120,
-					MarginTop:    130,
-					MarginRight:  140,
-					MarginBottom: 150,
+					Left:         Px(50),
+					Top:          Px(100),
+					Width:        Px(200),
+					Height:       Px(300),
+					MarginLeft:   Px(120),
+					MarginTop:    Px(130),
+					MarginRight:  Px(140),
+					MarginBottom: Px(150),
 					Position:     PositionAbsolute,
 					Direction:    Row,
 					Wrap:         WrapNormal,
@@ -61,11 +67,161 @@ func TestParseHTML(t *testing.T) {
 				},
 			}).AddChild(&View{
 				Attrs: ViewAttrs{
-					Width:  100,
-					Height: 200,
+					Width:  Px(100),
+					Height: Px(200),
 				},
 			}),
 		},
+		{
+			name: "padding",
+			html: `
+				<view style="padding: 10px 20px 30px 40px;">
+					<view style="padding: 5; padding-left: 6px;"></view>
+					<view style="padding: 1 2"></view>
+					<view style="padding: 1 2 3"></view>
+				</view>`,
+			expected: (&View{
+				Attrs: ViewAttrs{
+					PaddingTop:    Px(10),
+					PaddingRight:  Px(20),
+					PaddingBottom: Px(30),
+					PaddingLeft:   Px(40),
+				},
+			}).AddChild(
+				&View{Attrs: ViewAttrs{PaddingTop: Px(5), PaddingRight: Px(5), PaddingBottom: Px(5), PaddingLeft: Px(6)}},
+				&View{Attrs: ViewAttrs{PaddingTop: Px(1), PaddingRight: Px(2), PaddingBottom: Px(1), PaddingLeft: Px(2)}},
+				&View{Attrs: ViewAttrs{PaddingTop: Px(1), PaddingRight: Px(2), PaddingBottom: Px(3), PaddingLeft: Px(2)}},
+			),
+		},
+		{
+			name: "shorthands and gaps",
+			html: `
+				<view style="margin: 10px 20px; flex: 2 3; flex-flow: column wrap;">
+					<view style="margin: 1 2 3 4; flex: none; flex-flow: wrap"></view>
+					<view style="position: absolute; inset: 5px 6px 7px 8px;"></view>
+					<view style="gap: 4px 8px;"></view>
+					<view style="gap: 3; row-gap: 2"></view>
+				</view>`,
+			expected: (&View{
+				Attrs: ViewAttrs{
+					MarginTop:    Px(10),
+					MarginRight:  Px(20),
+					MarginBottom: Px(10),
+					MarginLeft:   Px(20),
+					Grow:         2,
+					Shrink:       3,
+					Basis:        Px(0),
+					Direction:    Column,
+					Wrap:         WrapNormal,
+				},
+			}).AddChild(
+				&View{Attrs: ViewAttrs{MarginTop: Px(1), MarginRight: Px(2), MarginBottom: Px(3), MarginLeft: Px(4), Wrap: WrapNormal}},
+				&View{Attrs: ViewAttrs{Position: PositionAbsolute, Top: Px(5), Right: Px(6), Bottom: Px(7), Left: Px(8)}},
+				&View{Attrs: ViewAttrs{RowGap: 4, ColumnGap: 8}},
+				&View{Attrs: ViewAttrs{RowGap: 2, ColumnGap: 3}},
+			),
+		},
+		{
+			name: "align-self, order and flex-basis",
+			html: `
+				<view>
+					<view style="align-self: center; order: 2; z-index: 3;"></view>
+					<view style="align-self: flex-end; order: -1; z-index: auto;"></view>
+					<view style="align-self: stretch; flex-basis: 120px"></view>
+					<view style="flex-basis: 25%"></view>
+					<view style="flex-basis: content"></view>
+				</view>`,
+			expected: (&View{}).AddChild(
+				&View{Attrs: ViewAttrs{AlignSelf: AlignSelfCenter, Order: 2, ZIndex: 3}},
+				&View{Attrs: ViewAttrs{AlignSelf: AlignSelfEnd, Order: -1}},
+				&View{Attrs: ViewAttrs{AlignSelf: AlignSelfStretch, Basis: Px(120)}},
+				&View{Attrs: ViewAttrs{Basis: Pct(25)}},
+				&View{Attrs: ViewAttrs{Basis: Content}},
+			),
+		},
+		{
+			name: "min and max sizes",
+			html: `
+				<view style="min-width: 10px; max-width: 50%; min-height: 20; max-height: none;">
+				</view>`,
+			expected: &View{
+				Attrs: ViewAttrs{
+					MinWidth:  Px(10),
+					MaxWidth:  Pct(50),
+					MinHeight: Px(20),
+				},
+			},
+		},
+		{
+			name: "lengths",
+			html: `
+				<view style="left: 2em; top: 3px; width: calc(100% - 20px); height: 50vh; margin: 1rem 5%; padding: 10.5px 0;">
+					<view style="inset: 10% 1vw 2vh 0; padding-left: calc(2 * (1em + 2px));"></view>
+					<view style="width: 50%; height: 25%;"></view>
+				</view>`,
+			expected: (&View{
+				Attrs: ViewAttrs{
+					Top:           Px(3),
+					Left:          Em(2),
+					Width:         Calc(Pct(100), Px(-20)),
+					Height:        Vh(50),
+					MarginTop:     Rem(1),
+					MarginRight:   Pct(5),
+					MarginBottom:  Rem(1),
+					MarginLeft:    Pct(5),
+					PaddingTop:    Px(10.5),
+					PaddingRight:  Px(0),
+					PaddingBottom: Px(10.5),
+					PaddingLeft:   Px(0),
+				},
+			}).AddChild(&View{
+				Attrs: ViewAttrs{
+					Left:        Px(0),
+					Top:         Pct(10),
+					Right:       Vw(1),
+					Bottom:      Vh(2),
+					PaddingLeft: Calc(Em(2), Px(4)),
+				},
+			}, &View{
+				Attrs: ViewAttrs{
+					Width: Pct(50), Height: Pct(25),
+				},
+			}),
+		},
+		{
+			name: "positions",
+			html: `
+				<view>
+					<view style="position: relative; left: 5px; top: -2px;"></view>
+					<view style="position: absolute; left: 0; right: 0; top: 50%;"></view>
+				</view>`,
+			expected: (&View{}).AddChild(
+				&View{Attrs: ViewAttrs{Position: PositionRelative, Left: Px(5), Top: Px(-2)}},
+				&View{
+					Attrs: ViewAttrs{
+						Position: PositionAbsolute,
+						Right:    Px(0),
+						Left:     Px(0), Top: Pct(50),
+					},
+				},
+			),
+		},
+		{
+			name: "overflow",
+			html: `
+				<view style="overflow: scroll; scrollbar-width: thin;">
+					<view style="overflow: hidden;"></view>
+					<view style="overflow: visible; scrollbar-width: 6px;"></view>
+					<view style="overflow: clip;"></view>
+				</view>`,
+			expected: (&View{
+				Attrs: ViewAttrs{Overflow: OverflowScroll, ScrollbarWidth: 4},
+			}).AddChild(
+				&View{Attrs: ViewAttrs{Overflow: OverflowHidden}},
+				&View{Attrs: ViewAttrs{ScrollbarWidth: 6}},
+				&View{Attrs: ViewAttrs{Overflow: OverflowHidden}},
+			),
+		},
 		{
 			name: "nested",
 			html: `
@@ -102,8 +258,8 @@ func TestParseHTML(t *testing.T) {
 						`,
 			expected: (&View{
 				Attrs: ViewAttrs{
-					Width:  200,
-					Height: 300,
+					Width:  Px(200),
+					Height: Px(300),
 				},
 			}).
 				AddChild(
@@ -155,8 +311,8 @@ func TestParseHTML(t *testing.T) {
 			},
 			expected: (&View{
 				Attrs: ViewAttrs{
-					Width:  100,
-					Height: 200,
+					Width:  Px(100),
+					Height: Px(200),
 				},
 			}),
 			after: func(t *testing.T, v *View) {
@@ -181,8 +337,8 @@ func TestParseHTML(t *testing.T) {
 						`,
 			expected: (&View{
 				Attrs: ViewAttrs{
-					Width:  200,
-					Height: 300,
+					Width:  Px(200),
+					Height: Px(300),
 				},
 			}).AddChild((&View{})),
 			opts: &ParseOptions{
@@ -195,6 +351,260 @@ func TestParseHTML(t *testing.T) {
 				require.Equal(t, true, elem.Attrs.Hidden)
 			},
 		},
+		{
+			name: "tabindex attribute",
+			html: `
+				<view>
+					<view tabindex="0"></view>
+					<view tabindex="-1"></view>
+					<view tabindex="x"></view>
+				</view>`,
+			expected: (&View{}).AddChild(
+				&View{Attrs: ViewAttrs{Focusable: true}},
+				&View{Attrs: ViewAttrs{Focusable: true, TabIndex: -1}},
+				&View{},
+			),
+		},
+		{
+			name: "nav attributes",
+			html: `
+				<view>
+					<view id="a" nav-up="#b" nav-down="b" nav-left="#c" nav-right="c"></view>
+				</view>`,
+			expected: (&View{}).AddChild(&View{}),
+			after: func(t *testing.T, v *View) {
+				a, ok := v.GetByID("a")
+				require.True(t, ok)
+				require.Equal(t, "b", a.Attrs.NavUp)
+				require.Equal(t, "b", a.Attrs.NavDown)
+				require.Equal(t, "c", a.Attrs.NavLeft)
+				require.Equal(t, "c", a.Attrs.NavRight)
+			},
+		},
+		{
+			name: "input",
+			html: `
+				<view>
+					<input id="name" placeholder="Name" value="Alice" maxlength="3">
+					<input id="empty" tabindex="2" />
+				</view>`,
+			expected: (&View{}).AddChild(
+				&View{Attrs: ViewAttrs{Focusable: true}},
+				&View{Attrs: ViewAttrs{Focusable: true, TabIndex: 2}},
+			),
+			after: func(t *testing.T, v *View) {
+				name, ok := v.GetByID("name")
+				require.True(t, ok)
+				input := name.Handler.Extra.(*TextInput)
+				require.Equal(t, "Name", input.Placeholder)
+				require.Equal(t, "Ali", input.Value)
+				require.Equal(t, 3, input.MaxLength)
+
+				empty, ok := v.GetByID("empty")
+				require.True(t, ok)
+				require.Equal(t, "", empty.Handler.Extra.(*TextInput).Value)
+			},
+		},
+		{
+			name: "text",
+			html: `
+				<view style="color: #f00; font-family: 'Go Mono', monospace; font-size: 2em; line-height: 1.5; text-align: center">
+					<view style="vertical-align: middle; white-space: nowrap; text-overflow: ellipsis"></view>
+				</view>`,
+			expected: (&View{Attrs: ViewAttrs{
+				Color:      color.NRGBA{0xff, 0, 0, 0xff},
+				FontFamily: "Go Mono",
+				FontSize:   Em(2),
+				LineHeight: Em(1.5),
+				TextAlign:  TextAlignCenter,
+			}}).AddChild(&View{Attrs: ViewAttrs{
+				VerticalAlign: VerticalAlignMiddle,
+				WhiteSpace:    WhiteSpaceNoWrap,
+				TextOverflow:  TextOverflowEllipsis,
+			}}),
+		},
+		{
+			name: "box",
+			html: `
+				<view style="background: #fff url('panel'); border: 2px solid rgb(0, 0, 0); border-radius: 4px; opacity: 50%; box-shadow: 0 2px 4px rgba(0, 0, 0, 0.5)">
+					<view style="background-color: red; background-image: url(icon); border-width: 1; border-color: blue; opacity: 0; box-shadow: 1px 1px"></view>
+					<view style="background: none; border: none; box-shadow: none"></view>
+				</view>`,
+			expected: (&View{Attrs: ViewAttrs{
+				BackgroundColor: color.NRGBA{0xff, 0xff, 0xff, 0xff},
+				BackgroundImage: "panel",
+				BorderWidth:     2,
+				BorderColor:     color.NRGBA{0, 0, 0, 0xff},
+				BorderRadius:    4,
+				Opacity:         floatPtr(0.5),
+				BoxShadow:       &Shadow{OffsetY: 2, Blur: 4, Color: color.NRGBA{0, 0, 0, 0x80}},
+			}}).AddChild(
+				&View{Attrs: ViewAttrs{
+					BackgroundColor: color.NRGBA{0xff, 0, 0, 0xff},
+					BackgroundImage: "icon",
+					BorderWidth:     1,
+					BorderColor:     color.NRGBA{0, 0, 0xff, 0xff},
+					Opacity:         floatPtr(0),
+					BoxShadow:       &Shadow{OffsetX: 1, OffsetY: 1, Color: color.Black},
+				}},
+				&View{},
+			),
+		},
+		{
+			name: "border image",
+			html: `
+				<view style="border-image: url(panel) 8 12 fill round">
+					<view style="border-image: none"></view>
+				</view>`,
+			expected: (&View{Attrs: ViewAttrs{
+				BorderImage: &NineSlice{Image: "panel", Top: 8, Right: 12, Bottom: 8, Left: 12, Fill: true, Repeat: RepeatRound},
+			}}).AddChild(&View{}),
+		},
+		{
+			name: "transition",
+			html: `
+				<view style="scale: 150%; transition: left 200ms ease-out, opacity 1s cubic-bezier(0.1, 0.7, 1.0, 0.1) 0.5s">
+					<view style="scale: 0.5; transition: 0.2s"></view>
+					<view style="scale: none; transition: none"></view>
+				</view>`,
+			expected: (&View{Attrs: ViewAttrs{
+				Scale: floatPtr(1.5),
+				Transitions: []Transition{
+					{Property: PropertyLeft, Duration: 200 * time.Millisecond, Easing: EaseOut},
+					{Property: PropertyOpacity, Duration: time.Second, Delay: 500 * time.Millisecond, Easing: CubicBezier{0.1, 0.7, 1, 0.1}},
+				},
+			}}).AddChild(
+				&View{Attrs: ViewAttrs{
+					Scale:       floatPtr(0.5),
+					Transitions: []Transition{{Property: PropertyAll, Duration: 200 * time.Millisecond}},
+				}},
+				&View{},
+			),
+		},
+		{
+			name: "transform",
+			html: `
+				<view style="transform: translate(10px, -5px) rotate(0.5turn) scale(2); transform-origin: left top">
+					<view style="transform: none; transform-origin: 25% 10px"></view>
+				</view>`,
+			expected: (&View{Attrs: ViewAttrs{
+				Transform: []TransformFunc{
+					{Func: TransformTranslate, X: 10, Y: -5},
+					{Func: TransformRotate, X: math.Pi},
+					{Func: TransformScale, X: 2, Y: 2},
+				},
+				TransformOriginX: Pct(0),
+				TransformOriginY: Pct(0),
+			}}).AddChild(&View{Attrs: ViewAttrs{
+				TransformOriginX: Pct(25),
+				TransformOriginY: Px(10),
+			}}),
+		},
+		{
+			name: "cache",
+			html: `
+				<view cache>
+					<view cache="false"></view>
+					<view cache="true"></view>
+				</view>`,
+			expected: (&View{Attrs: ViewAttrs{Cache: true}}).AddChild(
+				&View{},
+				&View{Attrs: ViewAttrs{Cache: true}},
+			),
+		},
+		{
+			name: "keyframes",
+			html: `
+				<head>
+					<style>
+						@keyframes pulse {
+							from, to { scale: 1; }
+							50% { scale: 1.1; background-color: #f00; animation-timing-function: ease-in; }
+						}
+						.button {
+							animation: pulse 1s ease-in-out infinite alternate;
+						}
+					</style>
+				</head>
+				<body>
+					<view class="button">
+						<view style="animation: slide 200ms 100ms 2 reverse forwards, pulse 1s"></view>
+					</view>
+				</body>`,
+			expected: (&View{Attrs: ViewAttrs{
+				Animations: []Animation{{Name: "pulse", Duration: time.Second, Easing: EaseInOut, Repeat: -1, Alternate: true}},
+			}}).AddChild(&View{Attrs: ViewAttrs{
+				Animations: []Animation{
+					{Name: "slide", Duration: 200 * time.Millisecond, Delay: 100 * time.Millisecond, Repeat: 1, Reverse: true, Forwards: true},
+					{Name: "pulse", Duration: time.Second},
+				},
+			}}),
+			after: func(t *testing.T, v *View) {
+				require.NotContains(t, keyframes, "pulse")
+				require.Equal(t, []Keyframe{
+					{Offset: 0, Values: map[FlexProperty]float64{PropertyScale: 1}},
+					{Offset: 0.5, Values: map[FlexProperty]float64{PropertyScale: 1.1}, Colors: map[FlexProperty]color.Color{PropertyBackgroundColor: color.NRGBA{0xff, 0, 0, 0xff}}, Easing: EaseIn},
+					{Offset: 1, Values: map[FlexProperty]float64{PropertyScale: 1}},
+				}, v.docKeyframes["pulse"])
+			},
+		},
+		{
+			name: "rich text",
+			html: `
+				<view>
+					<view id="tooltip" draw-text>Deal <b>20</b> <span style="color: red">fire <i>and</i></span><br>ice <img src="coin"></view>
+					<view id="plain" draw-text="false">
+						hello  <span>world</span>
+					</view>
+				</view>`,
+			expected: (&View{}).AddChild(&View{}, &View{}),
+			after: func(t *testing.T, v *View) {
+				red := color.NRGBA{0xff, 0, 0, 0xff}
+				tooltip, _ := v.GetByID("tooltip")
+				require.Equal(t, []TextSpan{
+					{Text: "Deal "},
+					{Text: "20", Bold: true},
+					{Text: " "},
+					{Text: "fire ", Color: red},
+					{Text: "and", Italic: true, Color: red},
+					{Break: true},
+					{Text: "ice "},
+					{Icon: "coin"},
+				}, tooltip.Attrs.Spans)
+				require.Equal(t, "Deal 20 fire and\nice ", tooltip.Attrs.Text)
+				require.True(t, tooltip.Attrs.DrawText)
+
+				plain, _ := v.GetByID("plain")
+				require.Nil(t, plain.Attrs.Spans)
+				require.Equal(t, "hello  world", plain.Attrs.Text)
+				require.False(t, plain.Attrs.DrawText)
+			},
+		},
+		{
+			name: "text between block children",
+			html: `
+				<view>
+					<view id="mixed">label<view id="child">inner</view>more</view>
+					<view id="titled"><b>title</b><view></view> more <br></view>
+				</view>`,
+			expected: (&View{}).AddChild(
+				(&View{}).AddChild(&View{}),
+				(&View{}).AddChild(&View{}),
+			),
+			after: func(t *testing.T, v *View) {
+				// The text between block children is not text of the view.
+				mixed, _ := v.GetByID("mixed")
+				require.Empty(t, mixed.Attrs.Text)
+				require.Nil(t, mixed.Attrs.Spans)
+				child, _ := v.GetByID("child")
+				require.Equal(t, "inner", child.Attrs.Text)
+
+				// Its inline elements are.
+				titled, _ := v.GetByID("titled")
+				require.Equal(t, []TextSpan{{Text: "title", Bold: true}, {Break: true}}, titled.Attrs.Spans)
+				require.Equal(t, "title\n", titled.Attrs.Text)
+			},
+		},
 		{
 			name: "complex",
 			html: `
@@ -383,8 +793,8 @@ func TestParseHTML(t *testing.T) {
 			},
 			expected: (&View{
 				Attrs: ViewAttrs{
-					Width:        640,
-					Height:       800,
+					Width:        Px(640),
+					Height:       Px(800),
 					Direction:    Column,
 					Justify:      JustifySpaceBetween,
 					AlignItems:   AlignItemStretch,
@@ -393,7 +803,7 @@ func TestParseHTML(t *testing.T) {
 			}).AddChild(
 				(&View{
 					Attrs: ViewAttrs{
-						MarginTop:  50,
+						MarginTop:  Px(50),
 						Grow:       1,
 						AlignItems: AlignItemCenter,
 						Justify:    JustifyCenter,
@@ -401,10 +811,10 @@ func TestParseHTML(t *testing.T) {
 				}).AddChild(
 					(&View{
 						Attrs: ViewAttrs{
-							Width:      300,
-							Height:     300,
-							MarginTop:  120,
-							MarginLeft: 130,
+							Width:      Px(300),
+							Height:     Px(300),
+							MarginTop:  Px(120),
+							MarginLeft: Px(130),
 							Direction:  Column,
 							AlignItems: AlignItemCenter,
 							Justify:    JustifyCenter,
@@ -412,9 +822,9 @@ func TestParseHTML(t *testing.T) {
 					}).AddChild(
 						(&View{
 							Attrs: ViewAttrs{
-								MarginTop:  20,
-								Width:      245,
-								Height:     200,
+								MarginTop:  Px(20),
+								Width:      Px(245),
+								Height:     Px(200),
 								Direction:  Column,
 								AlignItems: AlignItemCenter,
 								Justify:    JustifyCenter,
@@ -422,8 +832,8 @@ func TestParseHTML(t *testing.T) {
 						}).AddChild(
 							(&View{
 								Attrs: ViewAttrs{
-									Width:      180,
-									Height:     38,
+									Width:      Px(180),
+									Height:     Px(38),
 									AlignItems: AlignItemStart,
 									Justify:    JustifyStart,
 									Direction:  Column,
@@ -431,48 +841,48 @@ func TestParseHTML(t *testing.T) {
 							}).AddChild(
 								&View{
 									Attrs: ViewAttrs{
-										Height:       20,
-										Width:        180,
-										MarginBottom: 2,
+										Height:       Px(20),
+										Width:        Px(180),
+										MarginBottom: Px(2),
 									},
 								},
 								&View{
 									Attrs: ViewAttrs{
-										Width:  180,
-										Height: 18,
+										Width:  Px(180),
+										Height: Px(18),
 									},
 								},
 							),
 
 							(&View{
 								Attrs: ViewAttrs{
-									Width:      180,
-									Height:     38,
+									Width:      Px(180),
+									Height:     Px(38),
 									AlignItems: AlignItemStart,
 									Justify:    JustifyStart,
 									Direction:  Column,
-									MarginTop:  30,
+									MarginTop:  Px(30),
 								},
 							}).AddChild(
 								&View{
 									Attrs: ViewAttrs{
-										Height:       20,
-										Width:        180,
-										MarginBottom: 2,
+										Height:       Px(20),
+										Width:        Px(180),
+										MarginBottom: Px(2),
 									},
 								},
 								&View{
 									Attrs: ViewAttrs{
-										Width:  180,
-										Height: 18,
+										Width:  Px(180),
+										Height: Px(18),
 									},
 								},
 							),
 						),
 						(&View{
 							Attrs: ViewAttrs{
-								MarginTop:    20,
-								MarginBottom: 20,
+								MarginTop:    Px(20),
+								MarginBottom: Px(20),
 								Grow:         1,
 								Direction:    Row,
 								AlignItems:   AlignItemCenter,
@@ -481,32 +891,32 @@ func TestParseHTML(t *testing.T) {
 						}).AddChild(
 							&View{
 								Attrs: ViewAttrs{
-									Width:  190,
-									Height: 49,
+									Width:  Px(190),
+									Height: Px(49),
 								},
 							},
 							&View{
 								Attrs: ViewAttrs{
-									Width:      45,
-									Height:     49,
-									MarginLeft: 10,
+									Width:      Px(45),
+									Height:     Px(49),
+									MarginLeft: Px(10),
 								},
 							},
 						),
 						(&View{
 							Attrs: ViewAttrs{
 								Position: PositionAbsolute,
-								Left:     300 - 35/2,
-								Top:      4 - 38/2,
-								Width:    35,
-								Height:   38,
+								Left:     Px(300 - 35/2),
+								Top:      Px(4 - 38/2),
+								Width:    Px(35),
+								Height:   Px(38),
 							},
 						}).AddChild(
 							&View{
 								Attrs: ViewAttrs{
 									Position: PositionAbsolute,
-									Left:     18,
-									Top:      17,
+									Left:     Px(18),
+									Top:      Px(17),
 								},
 							},
 						),
@@ -556,8 +966,8 @@ func TestParseHTML(t *testing.T) {
 			},
 			expected: (&View{
 				Attrs: ViewAttrs{
-					Width:     640,
-					Height:    800,
+					Width:     Px(640),
+					Height:    Px(800),
 					Direction: Column,
 					Justify:   JustifyCenter,
 					Grow:      1,
@@ -572,8 +982,8 @@ func TestParseHTML(t *testing.T) {
 				}).AddChild(
 					&View{
 						Attrs: ViewAttrs{
-							Width:  30,
-							Height: 800,
+							Width:  Px(30),
+							Height: Px(800),
 						},
 					},
 				),
@@ -582,7 +992,7 @@ func TestParseHTML(t *testing.T) {
 			name: "functional component",
 			before: func(t *testing.T) {
 				register("test-comp", func() *View {
-					return &View{Attrs: ViewAttrs{Width: 100, Height: 100}}
+					return &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
 				})
 			},
 			html: `
@@ -593,8 +1003,8 @@ func TestParseHTML(t *testing.T) {
 				Width:  200,
 				Height: 300,
 			},
-			expected: (&View{Attrs: ViewAttrs{Width: 200, Height: 300}}).AddChild((&View{
-				Attrs: ViewAttrs{Position: PositionAbsolute, Width: 100, Height: 100},
+			expected: (&View{Attrs: ViewAttrs{Width: Px(200), Height: Px(300)}}).AddChild((&View{
+				Attrs: ViewAttrs{Position: PositionAbsolute, Width: Px(100), Height: Px(100)},
 			})),
 		},
 	}
@@ -613,6 +1023,40 @@ func TestParseHTML(t *testing.T) {
 	}
 }
 
+func TestParseFlex(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    cssFlex
+		wantErr bool
+	}{
+		{val: "1", want: cssFlex{grow: 1, shrink: 1, basis: Px(0)}},
+		{val: "2 3", want: cssFlex{grow: 2, shrink: 3, basis: Px(0)}},
+		{val: "2 3 10px", want: cssFlex{grow: 2, shrink: 3, basis: Px(10)}},
+		{val: "1 1 0", want: cssFlex{grow: 1, shrink: 1, basis: Px(0)}},
+		{val: "50%", want: cssFlex{grow: 1, shrink: 1, basis: Pct(50)}},
+		{val: "auto 2", want: cssFlex{grow: 2, shrink: 1, basis: Auto}},
+		{val: "content", want: cssFlex{grow: 1, shrink: 1, basis: Content}},
+		{val: "none", want: cssFlex{grow: 0, shrink: 0, basis: Auto}},
+		{val: "auto", want: cssFlex{grow: 1, shrink: 1, basis: Auto}},
+		{val: "initial", want: cssFlex{grow: 0, shrink: 1, basis: Auto}},
+		{val: "1 auto 2", wantErr: true},
+		{val: "1 2 3 4", wantErr: true},
+		{val: "grow", wantErr: true},
+		{val: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseFlex(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func testViewStyle(t *testing.T, v *View, expected *View) {
 	t.Helper()
 	require.Equal(t, styleConfig(expected.Config()), styleConfig(v.Config()))
@@ -626,3 +1070,385 @@ func styleConfig(cfg ViewConfig) ViewConfig {
 	}
 	return cfg
 }
+
+func TestParseLength(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    Length
+		wantErr bool
+	}{
+		{val: "10", want: Px(10)},
+		{val: "10.5px", want: Px(10.5)},
+		{val: "-3px", want: Px(-3)},
+		{val: "50%", want: Pct(50)},
+		{val: "1.5em", want: Em(1.5)},
+		{val: "2rem", want: Rem(2)},
+		{val: "10vw", want: Vw(10)},
+		{val: "20vh", want: Vh(20)},
+		{val: "auto", want: Auto},
+		{val: "calc(100% - 20px)", want: Calc(Pct(100), Px(-20))},
+		{val: "calc(100% + -20px)", want: Calc(Pct(100), Px(-20))},
+		{val: "calc((100% - 10px) / 2)", want: Calc(Pct(50), Px(-5))},
+		{val: "calc(2 * 1em + 50vw)", want: Calc(Em(2), Vw(50))},
+		{val: "calc(1rem * 3 - calc(1px))", want: Calc(Rem(3), Px(-1))},
+		{val: "calc(10px * 10px)", wantErr: true},
+		{val: "calc(10px + 5)", wantErr: true},
+		{val: "calc(10px / 0)", wantErr: true},
+		{val: "calc(5)", wantErr: true},
+		{val: "calc(10px", wantErr: true},
+		{val: "10pt", wantErr: true},
+		{val: "px", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseLength(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    color.NRGBA
+		wantErr bool
+	}{
+		{val: "red", want: color.NRGBA{0xff, 0, 0, 0xff}},
+		{val: "Transparent", want: color.NRGBA{}},
+		{val: "#0f0", want: color.NRGBA{0, 0xff, 0, 0xff}},
+		{val: "#0f08", want: color.NRGBA{0, 0xff, 0, 0x88}},
+		{val: "#123456", want: color.NRGBA{0x12, 0x34, 0x56, 0xff}},
+		{val: "#12345678", want: color.NRGBA{0x12, 0x34, 0x56, 0x78}},
+		{val: "rgb(1, 2, 3)", want: color.NRGBA{1, 2, 3, 0xff}},
+		{val: "rgba(255, 0, 0, 0.5)", want: color.NRGBA{0xff, 0, 0, 0x80}},
+		{val: "rgb(100% 0% 0% / 50%)", want: color.NRGBA{0xff, 0, 0, 0x80}},
+		{val: "rgb(300, -1, 0)", want: color.NRGBA{0xff, 0, 0, 0xff}},
+		{val: "#12345", wantErr: true},
+		{val: "#ggg", wantErr: true},
+		{val: "rgb(1, 2)", wantErr: true},
+		{val: "blurple", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseColor(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseBoxShadow(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    *Shadow
+		wantErr bool
+	}{
+		{val: "none", want: nil},
+		{val: "1px 2px", want: &Shadow{OffsetX: 1, OffsetY: 2, Color: color.Black}},
+		{val: "1px 2px 3px 4px red", want: &Shadow{OffsetX: 1, OffsetY: 2, Blur: 3, Spread: 4, Color: color.NRGBA{0xff, 0, 0, 0xff}}},
+		{val: "rgb(0, 0, 255) -1px -2px 3px", want: &Shadow{OffsetX: -1, OffsetY: -2, Blur: 3, Color: color.NRGBA{0, 0, 0xff, 0xff}}},
+		{val: "1px", wantErr: true},
+		{val: "1px 2px 3px 4px 5px", wantErr: true},
+		{val: "inset 1px 2px", wantErr: true},
+		{val: "1px 2px blurple", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseBoxShadow(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseBorderImage(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    *NineSlice
+		wantErr bool
+	}{
+		{val: "none", want: nil},
+		{val: "url(panel) 32", want: &NineSlice{Image: "panel", Top: 32, Right: 32, Bottom: 32, Left: 32}},
+		{val: "url('panel') 1 2 3 fill", want: &NineSlice{Image: "panel", Top: 1, Right: 2, Bottom: 3, Left: 2, Fill: true}},
+		{val: "repeat 1 2 3 4px url(panel)", want: &NineSlice{Image: "panel", Top: 1, Right: 2, Bottom: 3, Left: 4, Repeat: RepeatTile}},
+		{val: "url(panel) 8 stretch", want: &NineSlice{Image: "panel", Top: 8, Right: 8, Bottom: 8, Left: 8}},
+		{val: "url(panel)", wantErr: true},
+		{val: "32 fill", wantErr: true},
+		{val: "url(panel) 1 2 3 4 5", wantErr: true},
+		{val: "url(panel) 32 space", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseBorderImage(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTransitions(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    []Transition
+		wantErr bool
+	}{
+		{val: "none", want: nil},
+		{val: "left 200ms", want: []Transition{{Property: PropertyLeft, Duration: 200 * time.Millisecond}}},
+		{val: "ease-in 1.5s background-color 100ms", want: []Transition{{Property: PropertyBackgroundColor, Duration: 1500 * time.Millisecond, Delay: 100 * time.Millisecond, Easing: EaseIn}}},
+		{val: "width 1s linear, margin-top 2s", want: []Transition{
+			{Property: PropertyWidth, Duration: time.Second, Easing: EaseLinear},
+			{Property: PropertyMarginTop, Duration: 2 * time.Second},
+		}},
+		{val: "padding 1s", wantErr: true},
+		{val: "left 1s 2s 3s", wantErr: true},
+		{val: "left 1s cubic-bezier(2, 0, 1, 1)", wantErr: true},
+		{val: "left -1s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseTransitions(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseAnimations(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    []Animation
+		wantErr bool
+	}{
+		{val: "none", want: nil},
+		{val: "fade 2s", want: []Animation{{Name: "fade", Duration: 2 * time.Second}}},
+		{val: "3 linear fade 2s 1s alternate-reverse both", want: []Animation{{Name: "fade", Duration: 2 * time.Second, Delay: time.Second, Easing: EaseLinear, Repeat: 2, Alternate: true, Reverse: true, Forwards: true}}},
+		{val: "fade 1s, slide 2s infinite", want: []Animation{
+			{Name: "fade", Duration: time.Second},
+			{Name: "slide", Duration: 2 * time.Second, Repeat: -1},
+		}},
+		{val: "1s", wantErr: true},
+		{val: "fade slide 1s", wantErr: true},
+		{val: "fade 1s 0", wantErr: true},
+		{val: "fade 1s 2s 3s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseAnimations(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseKeyframes(t *testing.T) {
+	errs := &ErrorList{}
+	doc, frames := parseKeyframes(`<style>@keyframes a { to { left: 10px } } .x { left: 0 } @keyframes b { from { padding: 1px } }</style>`, errs)
+	require.Equal(t, `<style> .x { left: 0 } </style>`, doc)
+	require.Equal(t, []Keyframe{{Offset: 1, Values: map[FlexProperty]float64{PropertyLeft: 10}}}, frames["a"])
+	// Keyframes with errors are not kept.
+	require.NotContains(t, frames, "b")
+	require.Len(t, errs.errors, 1)
+
+	// Rules in comments, strings and outside of style elements are not keyframes.
+	input := `<!-- <style>@keyframes c { to { left: 1px } }</style> -->
+		<style>/* @keyframes d { to { left: 1px } } */ .y::after { content: "@keyframes e {" }
+		@keyframes f { to { left: 2px } }</style>
+		<view>@keyframes g { to { left: 1px } }</view>`
+	doc, frames = parseKeyframes(input, errs)
+	require.Len(t, frames, 1)
+	require.Contains(t, frames, "f")
+	require.Equal(t, strings.Replace(input, "@keyframes f { to { left: 2px } }", "", 1), doc)
+	require.Len(t, errs.errors, 1)
+
+	// Each document has its own keyframes.
+	html := `<head><style>@keyframes k { to { left: %dpx } }</style></head><body><view style="animation: k 1s"></view></body>`
+	v1, v2 := Parse(fmt.Sprintf(html, 10), nil), Parse(fmt.Sprintf(html, 20), nil)
+	require.NotContains(t, keyframes, "k")
+	require.Equal(t, 10.0, v1.keyframesByName("k")[0].Values[PropertyLeft])
+	require.Equal(t, 20.0, v2.keyframesByName("k")[0].Values[PropertyLeft])
+	root := &View{}
+	root.AddChild(v2)
+	require.Equal(t, 20.0, v2.keyframesByName("k")[0].Values[PropertyLeft])
+
+	_, err := parseKeyframeBlocks("50% { opacity: 0.5 } 120% { opacity: 1 }")
+	require.Error(t, err)
+	_, err = parseKeyframeBlocks("to { color: blurple }")
+	require.Error(t, err)
+	_, err = parseKeyframeBlocks("to { opacity: 1 } left")
+	require.Error(t, err)
+}
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    []TransformFunc
+		wantErr bool
+	}{
+		{val: "none", want: nil},
+		{val: "translateX(5px) translateY(-2) translate(3px)", want: []TransformFunc{
+			{Func: TransformTranslate, X: 5},
+			{Func: TransformTranslate, Y: -2},
+			{Func: TransformTranslate, X: 3},
+		}},
+		{val: "scale(1.5, 0.5) scaleX(2) scaleY(3)", want: []TransformFunc{
+			{Func: TransformScale, X: 1.5, Y: 0.5},
+			{Func: TransformScale, X: 2, Y: 1},
+			{Func: TransformScale, X: 1, Y: 3},
+		}},
+		{val: "rotate(90deg) rotate(1rad) rotate(0)", want: []TransformFunc{
+			{Func: TransformRotate, X: math.Pi / 2},
+			{Func: TransformRotate, X: 1},
+			{Func: TransformRotate, X: 0},
+		}},
+		{val: "rotate(90)", wantErr: true},
+		{val: "translate(10%)", wantErr: true},
+		{val: "skew(10deg)", wantErr: true},
+		{val: "scale()", wantErr: true},
+		{val: "scale(1, 2, 3)", wantErr: true},
+		{val: "scale", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseTransform(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTransformOrigin(t *testing.T) {
+	tests := []struct {
+		val     string
+		want    [2]Length
+		wantErr bool
+	}{
+		{val: "center", want: [2]Length{Pct(50), Pct(50)}},
+		{val: "top", want: [2]Length{Pct(50), Pct(0)}},
+		{val: "right", want: [2]Length{Pct(100), Pct(50)}},
+		{val: "bottom left", want: [2]Length{Pct(0), Pct(100)}},
+		{val: "10px 2em", want: [2]Length{Px(10), Em(2)}},
+		{val: "auto", wantErr: true},
+		{val: "1px 2px 3px", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			got, err := parseTransformOrigin(tt.val)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseE(t *testing.T) {
+	view, err := ParseE(`<view><view style="width: 10px" data-x="y"></view></view>`, nil)
+	require.NoError(t, err)
+	require.Equal(t, Px(10), view.children[0].Attrs.Width)
+	// The positions of the tags are not attributes of the views.
+	require.Equal(t, map[string]string{"style": "width: 10px", "data-x": "y"}, view.children[0].Attrs.ExtraAttrs)
+	require.NotContains(t, view.children[0].Attrs.Raw, positionAttr)
+
+	view, err = ParseE(`<head>
+	<style>
+		@keyframes pulse { 50% { padding: 1px } }
+		.x { height: 5px }
+	</style>
+</head>
+<body>
+	<view class="x">
+		<view style="width: 10px; color: blurple; colour: red"></view>
+		<fancy-button style="height: 20px"></fancy-button>
+	</view>
+</body>`, nil)
+	require.Error(t, err)
+	// The view is built without the errors.
+	require.NotNil(t, view)
+	require.Equal(t, Px(5), view.Attrs.Height)
+	require.Equal(t, Px(10), view.children[0].Attrs.Width)
+	require.Equal(t, Px(20), view.children[1].Attrs.Height)
+	require.True(t, errors.Is(err, ErrUnknownComponent))
+	require.False(t, errors.Is(err, ErrInvalidHTML))
+
+	var list *ErrorList
+	require.True(t, errors.As(err, &list))
+	errs := list.Errors()
+	require.Len(t, errs, 4)
+	want := []struct {
+		line, column  int
+		tag, property string
+	}{
+		{3, 3, "", ""},
+		{9, 3, "view", "color"},
+		{9, 3, "view", "colour"},
+		{10, 3, "fancy-button", ""},
+	}
+	for i, w := range want {
+		var pe *ParseError
+		require.True(t, errors.As(errs[i], &pe))
+		require.Equal(t, w.line, pe.Line, errs[i].Error())
+		require.Equal(t, w.column, pe.Column, errs[i].Error())
+		require.Equal(t, w.tag, pe.Tag)
+		require.Equal(t, w.property, pe.Property)
+	}
+	require.Equal(t, "9:3: <view> color: invalid color: blurple", errs[1].Error())
+
+	// Malformed declarations are errors, values may contain colons.
+	view, err = ParseE(`<view style="width 10px; height:; background-image: url(http://x); left: 1px"></view>`, nil)
+	require.Equal(t, Px(1), view.Attrs.Left)
+	require.True(t, errors.As(err, &list))
+	errs = list.Errors()
+	require.Equal(t, "http://x", view.Attrs.BackgroundImage)
+	require.Len(t, errs, 2)
+	require.Equal(t, "1:1: <view> invalid declaration: width 10px", errs[0].Error())
+	require.Equal(t, "1:1: <view> height: missing value", errs[1].Error())
+
+	// The errors of repeated sibling tags are at the tag they belong to.
+	_, err = ParseE(`<view>
+	<view style="width: 1px"></view>
+	<view style="width: 1px"></view><view style="width: 1px; color: blurple"></view>
+</view>`, nil)
+	require.Equal(t, "3:34: <view> color: invalid color: blurple", err.Error())
+	doc := `<head><style>@keyframes k { to { left: 1px } }</style></head><body><view><view></view><view style="color: blurple"></view></view></body>`
+	_, err = ParseE(doc, nil)
+	require.Equal(t, fmt.Sprintf("1:%d: <view> color: invalid color: blurple", strings.Index(doc, `<view style`)+1), err.Error())
+
+	// The error of several root views is at the second one.
+	view, err = ParseE("<view></view>\n<view></view>", nil)
+	require.Nil(t, view)
+	require.True(t, errors.Is(err, ErrInvalidHTML))
+	require.Equal(t, "2:1: <view> invalid html: 2 root views, want 1", err.Error())
+	require.Panics(t, func() { Parse(`<view></view><view></view>`, nil) })
+	require.Panics(t, func() { Parse(`<fancy-button></fancy-button>`, nil) })
+}
@@ -0,0 +1,70 @@
+package furex
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var images = map[string]*ebiten.Image{}
+
+// RegisterImage registers an image by name, such as a background image
+// or an icon drawn in text with <img src="name">.
+func RegisterImage(name string, img *ebiten.Image) {
+	images[name] = img
+}
+
+// lookupImage returns the image registered with the name, or nil.
+func lookupImage(name string) *ebiten.Image {
+	return images[name]
+}
+
+type textureAtlas struct {
+	SubTextures []subTexture `xml:"SubTexture"`
+}
+
+type subTexture struct {
+	Name   string `xml:"name,attr"`
+	X      int    `xml:"x,attr"`
+	Y      int    `xml:"y,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+// RegisterAtlas registers the images of a texture atlas in the XML format of
+// TexturePacker and Kenney's sprite sheets, which are parts of the sheet image:
+//
+//	<TextureAtlas imagePath="sheet.png">
+//		<SubTexture name="panel_blue.png" x="0" y="0" width="100" height="100"/>
+//	</TextureAtlas>
+//
+// The images are registered by their names with and without the file extension,
+// such as "panel_blue.png" and "panel_blue". No image is registered if the
+// atlas has an invalid sub texture.
+func RegisterAtlas(r io.Reader, sheet *ebiten.Image) error {
+	var atlas textureAtlas
+	if err := xml.NewDecoder(r).Decode(&atlas); err != nil {
+		return fmt.Errorf("invalid texture atlas: %w", err)
+	}
+	rects := make([]image.Rectangle, len(atlas.SubTextures))
+	for i, s := range atlas.SubTextures {
+		rect := image.Rect(s.X, s.Y, s.X+s.Width, s.Y+s.Height).Add(sheet.Bounds().Min)
+		if s.Name == "" || rect.Empty() || !rect.In(sheet.Bounds()) {
+			return fmt.Errorf("invalid sub texture %q: %v", s.Name, rect)
+		}
+		rects[i] = rect
+	}
+	for i, s := range atlas.SubTextures {
+		img := sheet.SubImage(rects[i]).(*ebiten.Image)
+		images[s.Name] = img
+		if ext := path.Ext(s.Name); ext != "" {
+			images[strings.TrimSuffix(s.Name, ext)] = img
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package furex
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"time"
+)
+
+// Keyframe is the values of properties at a point of a keyframe animation.
+type Keyframe struct {
+	// Offset is the progress of the animation at the keyframe, from 0 to 1.
+	Offset float64
+	// Values are the values of numeric properties, such as PropertyLeft.
+	Values map[FlexProperty]float64
+	// Colors are the values of color properties, such as PropertyBackgroundColor.
+	Colors map[FlexProperty]color.Color
+	// Easing is the easing from this keyframe to the next one,
+	// the easing of the animation is used if nil.
+	Easing Easing
+}
+
+// Animation is the 'animation' property, which plays keyframes on the view.
+type Animation struct {
+	// Name is the name of keyframes registered with RegisterKeyframes
+	// or defined by @keyframes in the HTML the view is parsed from.
+	Name     string
+	Duration time.Duration
+	// Delay is the time before the animation starts.
+	Delay time.Duration
+	// Easing is the easing between keyframes without an easing, it is Ease if nil.
+	Easing Easing
+	// Repeat is the number of times the animation is played again after the first time,
+	// it is repeated forever if negative.
+	Repeat int
+	// Alternate plays every other repetition backwards.
+	Alternate bool
+	// Reverse plays the animation backwards.
+	Reverse bool
+	// Forwards keeps the values of the last keyframe when the animation ends.
+	// The properties are set back to their values before the animation otherwise.
+	Forwards bool
+}
+
+var keyframes = map[string][]Keyframe{}
+
+// RegisterKeyframes registers the keyframes of animations by name.
+// Keyframes defined by @keyframes in HTML belong to the views parsed from it
+// and take precedence over the registered ones of the same name.
+func RegisterKeyframes(name string, frames ...Keyframe) {
+	keyframes[name] = sortKeyframes(append([]Keyframe(nil), frames...))
+}
+
+func sortKeyframes(frames []Keyframe) []Keyframe {
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Offset < frames[j].Offset
+	})
+	return frames
+}
+
+// keyframesByName returns the keyframes of the name defined in the HTML the view
+// or an ancestor is parsed from, or registered with RegisterKeyframes.
+func (v *View) keyframesByName(name string) []Keyframe {
+	for p := v; p != nil; p = p.parent {
+		if frames, ok := p.docKeyframes[name]; ok {
+			return frames
+		}
+	}
+	return keyframes[name]
+}
+
+// keyframeAnimation is a running animation of a view.
+type keyframeAnimation struct {
+	Animation
+	frames []Keyframe
+	// base are the values of the animated properties before the animation.
+	base    map[FlexProperty]animValue
+	elapsed time.Duration
+	done    bool
+}
+
+// keyframeValue is the value of a property at a keyframe.
+type keyframeValue struct {
+	offset float64
+	value  animValue
+	easing Easing
+}
+
+// value returns the value of the property at the progress p of an iteration.
+func (a *keyframeAnimation) value(prop FlexProperty, p float64) animValue {
+	var points []keyframeValue
+	for _, f := range a.frames {
+		if val, ok := f.value(prop); ok {
+			points = append(points, keyframeValue{f.Offset, val, f.Easing})
+		}
+	}
+	// The properties are animated from and to their values before the animation
+	// if the first and last keyframes don't set them.
+	if len(points) == 0 || points[0].offset > 0 {
+		points = append([]keyframeValue{{offset: 0, value: a.base[prop]}}, points...)
+	}
+	if last := points[len(points)-1]; last.offset < 1 {
+		points = append(points, keyframeValue{offset: 1, value: a.base[prop]})
+	}
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		if p > to.offset && i < len(points)-1 {
+			continue
+		}
+		if to.offset <= from.offset {
+			return to.value
+		}
+		easing := from.easing
+		if easing == nil {
+			easing = a.Easing
+		}
+		if easing == nil {
+			easing = Ease
+		}
+		return lerp(from.value, to.value, easing.Ease(clamp((p-from.offset)/(to.offset-from.offset), 0, 1)))
+	}
+	return points[0].value
+}
+
+// value returns the value of the property at the keyframe and true if it sets it.
+func (f *Keyframe) value(prop FlexProperty) (animValue, bool) {
+	if prop.isColor() {
+		clr, ok := f.Colors[prop]
+		return colorValue(clr), ok
+	}
+	val, ok := f.Values[prop]
+	return animValue{val}, ok
+}
+
+// properties returns the properties set by the keyframes.
+func (a *keyframeAnimation) properties() []FlexProperty {
+	var props []FlexProperty
+	for p := FlexProperty(0); p < PropertyAll; p++ {
+		for _, f := range a.frames {
+			if _, ok := f.value(p); ok {
+				props = append(props, p)
+				break
+			}
+		}
+	}
+	return props
+}
+
+// SetAnimations sets the keyframe animations of the view, which restart.
+func (v *View) SetAnimations(animations ...Animation) {
+	v.stopKeyframeAnimations()
+	v.Attrs.Animations = animations
+}
+
+// stopKeyframeAnimations stops the running keyframe animations of the view,
+// leaving the properties at their current values.
+func (v *View) stopKeyframeAnimations() {
+	v.keyframeAnimations = nil
+}
+
+// updateKeyframeAnimations starts the animations of the view and advances them by a tick.
+func (v *View) updateKeyframeAnimations() {
+	if len(v.Attrs.Animations) == 0 {
+		return
+	}
+	if v.keyframeAnimations == nil {
+		for _, anim := range v.Attrs.Animations {
+			a := &keyframeAnimation{Animation: anim, frames: v.keyframesByName(anim.Name)}
+			a.base = map[FlexProperty]animValue{}
+			for _, p := range a.properties() {
+				a.base[p] = v.propertyValue(p)
+			}
+			v.keyframeAnimations = append(v.keyframeAnimations, a)
+		}
+	}
+	dt := tick()
+	for _, a := range v.keyframeAnimations {
+		if a.done || len(a.frames) == 0 {
+			continue
+		}
+		a.elapsed += dt
+		if a.elapsed < a.Delay {
+			continue
+		}
+		p, i := 1.0, 0.0
+		if a.Duration > 0 {
+			p = float64(a.elapsed-a.Delay) / float64(a.Duration)
+			i = math.Floor(p)
+			p -= i
+		}
+		if a.Duration <= 0 || (a.Repeat >= 0 && i > float64(a.Repeat)) {
+			a.done = true
+			// The animation ends at the end of its last iteration.
+			i, p = float64(a.Repeat), 1
+			if a.Repeat < 0 || !a.Forwards {
+				for prop, val := range a.base {
+					v.setPropertyValue(prop, val)
+				}
+				continue
+			}
+		}
+		if a.Reverse != (a.Alternate && int(i)%2 == 1) {
+			p = 1 - p
+		}
+		for _, prop := range a.properties() {
+			v.setPropertyValue(prop, a.value(prop, p))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package furex
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyframeAnimation(t *testing.T) {
+	RegisterKeyframes("pulse",
+		Keyframe{Offset: 1, Values: map[FlexProperty]float64{PropertyScale: 1}},
+		Keyframe{Offset: 0.5, Values: map[FlexProperty]float64{PropertyScale: 2}},
+		Keyframe{Offset: 0, Values: map[FlexProperty]float64{PropertyScale: 1}},
+	)
+	defer delete(keyframes, "pulse")
+	require.Equal(t, []float64{0, 0.5, 1}, []float64{keyframes["pulse"][0].Offset, keyframes["pulse"][1].Offset, keyframes["pulse"][2].Offset})
+
+	v := &View{Attrs: ViewAttrs{Animations: []Animation{{Name: "pulse", Duration: 4 * tick(), Easing: EaseLinear}}}}
+	var scales []float64
+	for i := 0; i < 4; i++ {
+		v.Update()
+		scales = append(scales, *v.Attrs.Scale)
+	}
+	assert.Equal(t, []float64{1.5, 2, 1.5, 1}, scales)
+	// Ended animations don't restart.
+	updateTimes(v, 2)
+	assert.Equal(t, 1.0, *v.Attrs.Scale)
+
+	// Setting the animations restarts them.
+	v.SetAnimations(Animation{Name: "pulse", Duration: 4 * tick(), Easing: EaseLinear, Repeat: -1})
+	updateTimes(v, 42)
+	assert.Equal(t, 2.0, *v.Attrs.Scale)
+}
+
+func TestKeyframeAnimationBase(t *testing.T) {
+	red, blue := color.NRGBA{0xff, 0, 0, 0xff}, color.NRGBA{0, 0, 0xff, 0xff}
+	RegisterKeyframes("slide",
+		Keyframe{Offset: 0.5, Values: map[FlexProperty]float64{PropertyLeft: 100}, Easing: EasingFunc(func(t float64) float64 { return 0 })},
+		Keyframe{Offset: 1, Colors: map[FlexProperty]color.Color{PropertyBackgroundColor: blue}},
+	)
+	defer delete(keyframes, "slide")
+
+	// The properties are animated from and to their values before the animation
+	// where the keyframes don't set them.
+	v := &View{Attrs: ViewAttrs{Left: Px(20), BackgroundColor: red}}
+	v.SetAnimations(Animation{Name: "slide", Duration: 4 * tick(), Easing: EaseLinear})
+	v.Update()
+	require.Equal(t, Px(60), v.Attrs.Left)
+	require.Equal(t, color.NRGBA{0xbf, 0, 0x40, 0xff}, v.Attrs.BackgroundColor)
+	updateTimes(v, 2)
+	// The keyframe at 50% has its own easing.
+	require.Equal(t, Px(100), v.Attrs.Left)
+	updateTimes(v, 1)
+	require.Equal(t, Px(20), v.Attrs.Left)
+	require.Equal(t, red, v.Attrs.BackgroundColor)
+
+	// Forwards keeps the values of the end of the last iteration.
+	v.SetAnimations(Animation{Name: "slide", Duration: 2 * tick(), Delay: tick(), Easing: EaseLinear, Repeat: 1, Alternate: true, Forwards: true})
+	v.Update()
+	require.Equal(t, Px(20), v.Attrs.Left)
+	v.Update()
+	require.Equal(t, Px(100), v.Attrs.Left)
+	v.Update()
+	require.Equal(t, blue, v.Attrs.BackgroundColor)
+	v.Update()
+	require.Equal(t, color.NRGBA{0x80, 0, 0x80, 0xff}, v.Attrs.BackgroundColor)
+	// The second iteration is played backwards and ends at the start.
+	updateTimes(v, 3)
+	require.Equal(t, red, v.Attrs.BackgroundColor)
+	require.Equal(t, Px(20), v.Attrs.Left)
+
+	// Reverse plays the keyframes backwards.
+	v.SetAnimations(Animation{Name: "slide", Duration: 4 * tick(), Easing: EaseLinear, Reverse: true})
+	v.Update()
+	require.Equal(t, color.NRGBA{0x40, 0, 0xbf, 0xff}, v.Attrs.BackgroundColor)
+
+	// Animations of unknown keyframes do nothing.
+	left := v.Attrs.Left
+	v.SetAnimations(Animation{Name: "unknown", Duration: tick()})
+	updateTimes(v, 2)
+	require.Equal(t, left, v.Attrs.Left)
+}
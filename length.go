@@ -0,0 +1,320 @@
+package furex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LengthUnit is the unit of a Length.
+type LengthUnit uint8
+
+const (
+	// LengthAuto means the length is not specified and is determined by the layout.
+	LengthAuto LengthUnit = iota
+	// LengthPx is a length in pixels.
+	LengthPx
+	// LengthPct is a length in percent of a reference size,
+	// such as the size of the containing view.
+	LengthPct
+	// LengthContent means the length is determined by the content of the view.
+	LengthContent
+	// LengthEm is a length relative to the font size of the view.
+	LengthEm
+	// LengthRem is a length relative to the font size of the root view.
+	LengthRem
+	// LengthVw is a length in percent of the width of the root view.
+	LengthVw
+	// LengthVh is a length in percent of the height of the root view.
+	LengthVh
+	// LengthCalc is a sum of lengths in different units, see Calc.
+	LengthCalc
+)
+
+func (u LengthUnit) String() string {
+	switch u {
+	case LengthAuto:
+		return "auto"
+	case LengthPx:
+		return "px"
+	case LengthPct:
+		return "%"
+	case LengthContent:
+		return "content"
+	case LengthEm:
+		return "em"
+	case LengthRem:
+		return "rem"
+	case LengthVw:
+		return "vw"
+	case LengthVh:
+		return "vh"
+	case LengthCalc:
+		return "calc"
+	default:
+		return fmt.Sprintf("%d", int(u))
+	}
+}
+
+// Length is a size that can be given in pixels, in relative units or as a keyword.
+// The zero value is auto.
+type Length struct {
+	Value float64
+	Unit  LengthUnit
+
+	// calc holds the terms of a LengthCalc length.
+	calc *calcSum
+}
+
+var (
+	// Auto is the auto length.
+	Auto = Length{}
+	// Content is the length that sizes the view to its content.
+	Content = Length{Unit: LengthContent}
+)
+
+// Px returns a length in pixels.
+func Px(v float64) Length {
+	return Length{Value: v, Unit: LengthPx}
+}
+
+// Pct returns a length in percent.
+func Pct(v float64) Length {
+	return Length{Value: v, Unit: LengthPct}
+}
+
+// Em returns a length relative to the font size of the view.
+func Em(v float64) Length {
+	return Length{Value: v, Unit: LengthEm}
+}
+
+// Rem returns a length relative to the font size of the root view.
+func Rem(v float64) Length {
+	return Length{Value: v, Unit: LengthRem}
+}
+
+// Vw returns a length in percent of the width of the root view.
+func Vw(v float64) Length {
+	return Length{Value: v, Unit: LengthVw}
+}
+
+// Vh returns a length in percent of the height of the root view.
+func Vh(v float64) Length {
+	return Length{Value: v, Unit: LengthVh}
+}
+
+// Calc returns the sum of the lengths, like calc() in CSS.
+// For example Calc(Pct(100), Px(-20)) is calc(100% - 20px).
+// Auto and content lengths can't be summed and are ignored.
+func Calc(terms ...Length) Length {
+	sum := &calcSum{}
+	for _, t := range terms {
+		sum.add(t, 1)
+	}
+	return Length{Unit: LengthCalc, calc: sum}
+}
+
+// IsAuto returns true if the length is auto.
+func (l Length) IsAuto() bool {
+	return l.Unit == LengthAuto
+}
+
+func (l Length) String() string {
+	switch l.Unit {
+	case LengthAuto, LengthContent:
+		return l.Unit.String()
+	case LengthCalc:
+		return l.calc.String()
+	default:
+		return strconv.FormatFloat(l.Value, 'f', -1, 64) + l.Unit.String()
+	}
+}
+
+// lengthContext holds the sizes that relative lengths are resolved against.
+type lengthContext struct {
+	viewportWidth  float64
+	viewportHeight float64
+	fontSize       float64
+	rootFontSize   float64
+}
+
+// resolve returns the length in pixels, resolving percentages against ref.
+// It returns false if the length is not definite, e.g. auto or content.
+func (l Length) resolve(ref float64, ctx lengthContext) (float64, bool) {
+	switch l.Unit {
+	case LengthPx:
+		return l.Value, true
+	case LengthPct:
+		return ref * l.Value / 100, true
+	case LengthEm:
+		return ctx.fontSize * l.Value, true
+	case LengthRem:
+		return ctx.rootFontSize * l.Value, true
+	case LengthVw:
+		return ctx.viewportWidth * l.Value / 100, true
+	case LengthVh:
+		return ctx.viewportHeight * l.Value / 100, true
+	case LengthCalc:
+		if l.calc == nil {
+			return 0, true
+		}
+		v := 0.0
+		for u, n := range l.calc {
+			if n != 0 {
+				r, _ := Length{Value: n, Unit: LengthUnit(u)}.resolve(ref, ctx)
+				v += r
+			}
+		}
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// calcSum is the value of a calc() length, the sum of its terms by unit.
+type calcSum [LengthCalc]float64
+
+// add adds the length l multiplied by n to the sum.
+func (s *calcSum) add(l Length, n float64) {
+	switch l.Unit {
+	case LengthAuto, LengthContent:
+	case LengthCalc:
+		if l.calc != nil {
+			for u, v := range l.calc {
+				s[u] += v * n
+			}
+		}
+	default:
+		s[l.Unit] += l.Value * n
+	}
+}
+
+func (s *calcSum) String() string {
+	var terms []string
+	for u, v := range s {
+		if v != 0 {
+			terms = append(terms, Length{Value: v, Unit: LengthUnit(u)}.String())
+		}
+	}
+	if len(terms) == 0 {
+		return "calc(0px)"
+	}
+	return "calc(" + strings.Join(terms, " + ") + ")"
+}
+
+// usedAttrs holds the attributes of a view that are given as lengths,
+// resolved to pixels against the containing view. Zero means the attribute
+// is auto, except for the insets which are nil when auto.
+type usedAttrs struct {
+	Left          *int
+	Right         *int
+	Top           *int
+	Bottom        *int
+	Width         int
+	Height        int
+	MarginLeft    int
+	MarginTop     int
+	MarginRight   int
+	MarginBottom  int
+	PaddingLeft   int
+	PaddingTop    int
+	PaddingRight  int
+	PaddingBottom int
+	// WidthInPct and HeightInPct are the attributes of ViewAttrs,
+	// which are used only when the width or the height is auto.
+	WidthInPct  float64
+	HeightInPct float64
+}
+
+// equal returns true if the used attributes are the same.
+func (u *usedAttrs) equal(o *usedAttrs) bool {
+	return equalInt(u.Left, o.Left) && equalInt(u.Right, o.Right) &&
+		equalInt(u.Top, o.Top) && equalInt(u.Bottom, o.Bottom) &&
+		u.Width == o.Width && u.Height == o.Height &&
+		u.MarginLeft == o.MarginLeft && u.MarginTop == o.MarginTop &&
+		u.MarginRight == o.MarginRight && u.MarginBottom == o.MarginBottom &&
+		u.PaddingLeft == o.PaddingLeft && u.PaddingTop == o.PaddingTop &&
+		u.PaddingRight == o.PaddingRight && u.PaddingBottom == o.PaddingBottom &&
+		u.WidthInPct == o.WidthInPct && u.HeightInPct == o.HeightInPct
+}
+
+func equalInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// resolveLengths resolves the used attributes of the view against the size
+// of its containing view. Percent margins and paddings are resolved against
+// the width of the containing view, as in CSS.
+func (v *View) resolveLengths(containerWidth, containerHeight float64, ctx lengthContext) {
+	a := &v.Attrs
+	v.used = usedAttrs{
+		Left:          usedInset(a.Left, containerWidth, ctx),
+		Right:         usedInset(a.Right, containerWidth, ctx),
+		Top:           usedInset(a.Top, containerHeight, ctx),
+		Bottom:        usedInset(a.Bottom, containerHeight, ctx),
+		Width:         usedLength(a.Width, containerWidth, ctx),
+		Height:        usedLength(a.Height, containerHeight, ctx),
+		MarginLeft:    usedLength(a.MarginLeft, containerWidth, ctx),
+		MarginTop:     usedLength(a.MarginTop, containerWidth, ctx),
+		MarginRight:   usedLength(a.MarginRight, containerWidth, ctx),
+		MarginBottom:  usedLength(a.MarginBottom, containerWidth, ctx),
+		PaddingLeft:   usedLength(a.PaddingLeft, containerWidth, ctx),
+		PaddingTop:    usedLength(a.PaddingTop, containerWidth, ctx),
+		PaddingRight:  usedLength(a.PaddingRight, containerWidth, ctx),
+		PaddingBottom: usedLength(a.PaddingBottom, containerWidth, ctx),
+	}
+	if v.used.Width == 0 {
+		v.used.WidthInPct = a.WidthInPct
+	}
+	if v.used.Height == 0 {
+		v.used.HeightInPct = a.HeightInPct
+	}
+}
+
+// usedLength resolves the length to whole pixels, or to 0 if it is auto.
+func usedLength(l Length, ref float64, ctx lengthContext) int {
+	if v, ok := l.resolve(ref, ctx); ok {
+		return round(v)
+	}
+	return 0
+}
+
+// usedInset resolves the inset to whole pixels, or to nil if it is auto.
+func usedInset(l Length, ref float64, ctx lengthContext) *int {
+	if v, ok := l.resolve(ref, ctx); ok {
+		return Int(round(v))
+	}
+	return nil
+}
+
+// lengthContext returns the sizes relative lengths of the view are resolved against.
+func (v *View) lengthContext() lengthContext {
+	if v.hasParent {
+		return v.parent.lengthContext().child(v)
+	}
+	ctx := lengthContext{fontSize: BaseFontSize, rootFontSize: BaseFontSize}
+	ctx.fontSize = ctx.usedFontSize(v)
+	ctx.rootFontSize = ctx.fontSize
+	// The root view is the viewport, so its size can't be relative to it.
+	ctx.viewportWidth = float64(usedLength(v.Attrs.Width, 0, ctx))
+	ctx.viewportHeight = float64(usedLength(v.Attrs.Height, 0, ctx))
+	return ctx
+}
+
+// child returns the context of the lengths of a child of the view of the context.
+func (ctx lengthContext) child(c *View) lengthContext {
+	ctx.fontSize = ctx.usedFontSize(c)
+	return ctx
+}
+
+// usedFontSize resolves the font size of a child of the view of the context.
+// Em and percent font sizes are relative to the font size of the parent.
+func (ctx lengthContext) usedFontSize(c *View) float64 {
+	if size, ok := c.Attrs.FontSize.resolve(ctx.fontSize, ctx); ok && size > 0 {
+		return size
+	}
+	return ctx.fontSize
+}
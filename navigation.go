@@ -0,0 +1,237 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// NavAction is an action of gamepad navigation.
+type NavAction int
+
+const (
+	// NavActionUp moves the focus to the nearest focusable view above the focused view.
+	NavActionUp NavAction = iota
+	// NavActionDown moves the focus to the nearest focusable view below the focused view.
+	NavActionDown
+	// NavActionLeft moves the focus to the nearest focusable view left of the focused view.
+	NavActionLeft
+	// NavActionRight moves the focus to the nearest focusable view right of the focused view.
+	NavActionRight
+	// NavActionActivate clicks the center of the focused view.
+	NavActionActivate
+)
+
+func (a NavAction) String() string {
+	switch a {
+	case NavActionUp:
+		return "up"
+	case NavActionDown:
+		return "down"
+	case NavActionLeft:
+		return "left"
+	case NavActionRight:
+		return "right"
+	case NavActionActivate:
+		return "activate"
+	}
+	return fmt.Sprintf("unknown nav action: %d", int(a))
+}
+
+// GamepadNavigation maps the buttons of standard gamepads to navigation actions.
+// Change it to configure the buttons, or set it to nil to disable gamepad navigation.
+var GamepadNavigation = map[ebiten.StandardGamepadButton]NavAction{
+	ebiten.StandardGamepadButtonLeftTop:     NavActionUp,
+	ebiten.StandardGamepadButtonLeftBottom:  NavActionDown,
+	ebiten.StandardGamepadButtonLeftLeft:    NavActionLeft,
+	ebiten.StandardGamepadButtonLeftRight:   NavActionRight,
+	ebiten.StandardGamepadButtonRightBottom: NavActionActivate,
+}
+
+// FocusDirection moves the focus to the nearest focusable view in the direction
+// from the focused view, or to the view given by the NavUp, NavDown, NavLeft or
+// NavRight attribute of the focused view, and scrolls it into view. If no view is
+// focused, the first view in Tab navigation order is focused. It returns false
+// if the focus didn't move.
+func (v *View) FocusDirection(dir NavAction) bool {
+	return v.root().moveFocusDirection(dir)
+}
+
+func (root *View) moveFocusDirection(dir NavAction) bool {
+	focused := root.focusedView()
+	if focused == nil {
+		return root.moveFocus(1)
+	}
+	target := root.navTarget(focused, dir)
+	if target == nil || target == focused || !target.Focus() {
+		return false
+	}
+	target.scrollIntoView()
+	return true
+}
+
+// navTarget returns the view the focus moves to in the direction from the focused view.
+func (root *View) navTarget(focused *View, dir NavAction) *View {
+	if id := focused.navOverride(dir); id != "" {
+		if target, ok := root.GetByID(id); ok {
+			return target
+		}
+	}
+
+	// The views are compared where they are drawn on the screen.
+	from := focused.screenRect(scaleFrame(focused.frame))
+	var best *View
+	var bestScore [3]int
+	for _, c := range root.tabOrder() {
+		if c == focused {
+			continue
+		}
+		score, ok := navScore(dir, from, c.screenRect(scaleFrame(c.frame)))
+		if !ok {
+			continue
+		}
+		if best == nil || lessScore(score, bestScore) {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
+
+func (v *View) navOverride(dir NavAction) string {
+	switch dir {
+	case NavActionUp:
+		return v.Attrs.NavUp
+	case NavActionDown:
+		return v.Attrs.NavDown
+	case NavActionLeft:
+		return v.Attrs.NavLeft
+	case NavActionRight:
+		return v.Attrs.NavRight
+	}
+	return ""
+}
+
+// navScore returns how far the candidate frame is from the focused frame in
+// the direction, or false if it is not in the direction. Candidates overlapping
+// the focused frame across the direction come first, then the candidates are
+// compared by the distance along the direction plus twice the distance across it,
+// then by the distance between their centers across the direction.
+func navScore(dir NavAction, from, to image.Rectangle) ([3]int, bool) {
+	// Rotate the frames so that the direction is to the right.
+	switch dir {
+	case NavActionLeft:
+		from, to = mirrorX(from), mirrorX(to)
+	case NavActionUp:
+		from, to = transpose(mirrorY(from)), transpose(mirrorY(to))
+	case NavActionDown:
+		from, to = transpose(from), transpose(to)
+	case NavActionRight:
+	default:
+		return [3]int{}, false
+	}
+	if to.Min.X+to.Max.X <= from.Min.X+from.Max.X {
+		return [3]int{}, false
+	}
+	along := to.Min.X - from.Max.X
+	if along < 0 {
+		along = 0
+	}
+	across := 0
+	if to.Min.Y >= from.Max.Y {
+		across = to.Min.Y - from.Max.Y + 1
+	} else if from.Min.Y >= to.Max.Y {
+		across = from.Min.Y - to.Max.Y + 1
+	}
+	outside := 0
+	if across > 0 {
+		outside = 1
+	}
+	centers := (to.Min.Y + to.Max.Y) - (from.Min.Y + from.Max.Y)
+	if centers < 0 {
+		centers = -centers
+	}
+	return [3]int{outside, along + 2*across, centers}, true
+}
+
+func lessScore(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func mirrorX(r image.Rectangle) image.Rectangle {
+	return image.Rect(-r.Max.X, r.Min.Y, -r.Min.X, r.Max.Y)
+}
+
+func mirrorY(r image.Rectangle) image.Rectangle {
+	return image.Rect(r.Min.X, -r.Max.Y, r.Max.X, -r.Min.Y)
+}
+
+func transpose(r image.Rectangle) image.Rectangle {
+	return image.Rect(r.Min.Y, r.Min.X, r.Max.Y, r.Max.X)
+}
+
+// handleGamepadEvents performs the navigation actions of the gamepad buttons
+// of this tick. Direction buttons repeat while they are held down.
+func (root *View) handleGamepadEvents() {
+	if len(GamepadNavigation) == 0 {
+		return
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for b, a := range GamepadNavigation {
+			switch {
+			case inpututil.IsStandardGamepadButtonJustPressed(id, b):
+				root.navigate(a, true)
+			case inpututil.IsStandardGamepadButtonJustReleased(id, b):
+				root.navigate(a, false)
+			case a != NavActionActivate:
+				d := inpututil.StandardGamepadButtonPressDuration(id, b)
+				if d > keyRepeatDelay && (d-keyRepeatDelay)%keyRepeatInterval == 0 {
+					root.navigate(a, true)
+				}
+			}
+		}
+	}
+}
+
+// navTouchID is the touch ID of the touch events sent by navigation activation.
+const navTouchID ebiten.TouchID = -2
+
+// navigate performs the navigation action when its button is pressed or released.
+// Activating clicks the center of the focused view where it is drawn: the left mouse
+// button events, or the touch events if no view handles them, go to the views at
+// that point like a click with the mouse, which may be views covering the focused view.
+func (root *View) navigate(a NavAction, pressed bool) {
+	if a != NavActionActivate {
+		if pressed {
+			root.moveFocusDirection(a)
+		}
+		return
+	}
+	if !pressed {
+		if p := root.activated; p != nil {
+			root.activated = nil
+			root.handleMouseButtonLeftReleased(&root.frame, p.X, p.Y)
+			root.HandleJustReleasedTouchID(&root.frame, navTouchID, p.X, p.Y)
+		}
+		return
+	}
+	focused := root.focusedView()
+	if focused == nil || root.activated != nil {
+		return
+	}
+	r := focused.screenRect(scaleFrame(focused.frame))
+	p := image.Pt((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+	root.activated = &p
+	if !root.handleMouseButtonLeftPressed(&root.frame, p.X, p.Y) {
+		root.HandleJustPressedTouchID(&root.frame, navTouchID, p.X, p.Y)
+	}
+}
@@ -0,0 +1,206 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newNavGrid returns a root view with a 3x2 grid of focusable views:
+//
+//	a b c
+//	d   e
+func newNavGrid() (*View, map[string]*View) {
+	views := map[string]*View{}
+	item := func(id string) *View {
+		v := &View{Attrs: ViewAttrs{ID: id, Width: Px(20), Height: Px(20), MarginRight: Px(10), Focusable: id != ""}}
+		views[id] = v
+		return v
+	}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), Direction: Column, AlignItems: AlignItemStart}}
+	root.AddChild(
+		(&View{Attrs: ViewAttrs{Height: Px(30)}}).AddChild(item("a"), item("b"), item("c")),
+		(&View{Attrs: ViewAttrs{Height: Px(30)}}).AddChild(item("d"), item(""), item("e")),
+	)
+	root.Update()
+	root.Draw(nil)
+	return root, views
+}
+
+func TestFocusDirection(t *testing.T) {
+	root, views := newNavGrid()
+
+	// Without a focused view, the first view is focused.
+	require.True(t, root.FocusDirection(NavActionDown))
+	require.True(t, views["a"].IsFocused())
+
+	for _, tt := range []struct {
+		dir  NavAction
+		want string
+		ok   bool
+	}{
+		{NavActionRight, "b", true},
+		{NavActionDown, "d", true},
+		{NavActionRight, "e", true},
+		{NavActionRight, "e", false},
+		{NavActionUp, "c", true},
+		{NavActionUp, "c", false},
+		{NavActionLeft, "b", true},
+		{NavActionLeft, "a", true},
+		{NavActionLeft, "a", false},
+	} {
+		require.Equal(t, tt.ok, root.FocusDirection(tt.dir), "%s to %s", tt.dir, tt.want)
+		require.True(t, views[tt.want].IsFocused(), "%s to %s", tt.dir, tt.want)
+	}
+}
+
+func TestFocusDirectionOverride(t *testing.T) {
+	root, views := newNavGrid()
+	views["c"].Attrs.NavRight = "d"
+	views["c"].Attrs.NavDown = "missing"
+
+	views["c"].Focus()
+	require.True(t, root.FocusDirection(NavActionRight))
+	require.True(t, views["d"].IsFocused())
+
+	// The nearest view is used if the view of the override doesn't exist.
+	views["c"].Focus()
+	require.True(t, root.FocusDirection(NavActionDown))
+	require.True(t, views["e"].IsFocused())
+}
+
+func TestNavigateActivate(t *testing.T) {
+	root, views := newNavGrid()
+	mock := NewMockHandler()
+	released := image.Point{}
+	mock.ViewHandler.JustReleasedMouseButtonLeft = func(frame image.Rectangle, x, y int) {
+		released = image.Pt(x, y)
+	}
+	views["e"].Handler = mock.ViewHandler
+
+	root.navigate(NavActionActivate, true)
+	require.False(t, mock.IsClickPressed)
+
+	views["d"].Focus()
+	root.navigate(NavActionRight, true)
+	root.navigate(NavActionRight, false)
+	require.True(t, views["e"].IsFocused())
+
+	root.navigate(NavActionActivate, true)
+	require.True(t, mock.IsClickPressed)
+	root.navigate(NavActionActivate, false)
+	require.Equal(t, image.Pt(70, 40), released)
+}
+
+func TestNavigateActivateTransformed(t *testing.T) {
+	mock := NewMockHandler()
+	released := image.Point{}
+	mock.ViewHandler.JustReleasedMouseButtonLeft = func(frame image.Rectangle, x, y int) {
+		released = image.Pt(x, y)
+	}
+	button := &View{
+		Attrs: ViewAttrs{
+			Width:     Px(20),
+			Height:    Px(20),
+			Focusable: true,
+			Transform: []TransformFunc{
+				{Func: TransformTranslate, X: 50, Y: 30},
+				{Func: TransformScale, X: 2, Y: 2},
+			},
+		},
+		Handler: mock.ViewHandler,
+	}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(button)
+	root.Update()
+	root.Draw(nil)
+	button.Focus()
+
+	// The center of the button is clicked where the button is drawn.
+	root.navigate(NavActionActivate, true)
+	require.True(t, mock.IsClickPressed)
+	root.navigate(NavActionActivate, false)
+	require.Equal(t, image.Pt(10, 10), released)
+}
+
+func TestNavigateActivateCovered(t *testing.T) {
+	root, views := newNavGrid()
+	mock, overlay := NewMockHandler(), NewMockHandler()
+	views["a"].Handler = mock.ViewHandler
+	root.AddChild(&View{
+		Attrs:   ViewAttrs{Position: PositionAbsolute, Width: Px(100), Height: Px(100), ZIndex: 1},
+		Handler: overlay.ViewHandler,
+	})
+	root.Update()
+	views["a"].Focus()
+
+	// The view covering the focused view is clicked, as with the mouse.
+	root.navigate(NavActionActivate, true)
+	require.True(t, overlay.IsClickPressed)
+	require.False(t, mock.IsClickPressed)
+	root.navigate(NavActionActivate, false)
+	require.True(t, overlay.IsClickReleased)
+	require.True(t, views["a"].IsFocused())
+}
+
+func TestNavigateActivateClipped(t *testing.T) {
+	root, list, _ := newScrollList(OverflowHidden)
+	mock := NewMockHandler()
+	item := list.children[4]
+	item.Handler = mock.ViewHandler
+	item.SetFocusable(true)
+	root.Update()
+	item.Focus()
+
+	// Views clipped out at their center are not clicked.
+	root.navigate(NavActionActivate, true)
+	require.False(t, mock.IsClickPressed)
+	require.False(t, mock.IsPressed)
+	root.navigate(NavActionActivate, false)
+}
+
+func TestNavigateActivateTouch(t *testing.T) {
+	root, views := newNavGrid()
+	mock := NewMockHandler()
+	mock.ViewHandler.JustPressedMouseButtonLeft = nil
+	mock.ViewHandler.JustReleasedMouseButtonLeft = nil
+	views["b"].Handler = mock.ViewHandler
+	views["b"].Focus()
+
+	// Views handling touches only are touched.
+	root.navigate(NavActionActivate, true)
+	require.True(t, mock.IsPressed)
+	root.navigate(NavActionActivate, false)
+	require.True(t, mock.IsReleased)
+	require.False(t, mock.IsCancel)
+}
+
+func TestFocusDirectionTransformed(t *testing.T) {
+	root, views := newNavGrid()
+	// b is drawn below e.
+	views["b"].SetTransform(TransformFunc{Func: TransformTranslate, X: 30, Y: 60})
+	root.Update()
+	views["e"].Focus()
+
+	require.True(t, root.FocusDirection(NavActionDown))
+	require.True(t, views["b"].IsFocused())
+}
+
+func TestFocusDirectionScroll(t *testing.T) {
+	root, list, _ := newScrollList(OverflowScroll)
+	for _, c := range list.children {
+		c.SetFocusable(true)
+	}
+	list.children[2].Focus()
+
+	// The focus is scrolled into view.
+	require.True(t, root.FocusDirection(NavActionDown))
+	require.True(t, list.children[3].IsFocused())
+	_, y := list.ScrollOffset()
+	require.Equal(t, 60, y)
+
+	require.True(t, root.FocusDirection(NavActionDown))
+	_, y = list.ScrollOffset()
+	require.Equal(t, 100, y)
+}
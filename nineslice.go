@@ -0,0 +1,156 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// FlexRepeat is how the edges and the middle of a border image are fitted
+// to the frame of the view.
+type FlexRepeat uint8
+
+const (
+	// RepeatStretch stretches the slices to the frame.
+	RepeatStretch FlexRepeat = iota
+	// RepeatTile tiles the slices from the start, cutting the last tile.
+	RepeatTile
+	// RepeatRound tiles the slices, scaled so that a whole number of tiles fits.
+	RepeatRound
+)
+
+func (r FlexRepeat) String() string {
+	switch r {
+	case RepeatStretch:
+		return "stretch"
+	case RepeatTile:
+		return "repeat"
+	case RepeatRound:
+		return "round"
+	}
+	return fmt.Sprintf("unknown repeat: %d", r)
+}
+
+// NineSlice is the 'border-image' property, an image drawn in nine slices
+// over the frame of the view. The corners keep their size, and the edges
+// and the middle are stretched or tiled to the frame.
+type NineSlice struct {
+	// Image is the name of an image registered with RegisterImage.
+	Image string
+	// Top, Right, Bottom and Left are the sizes of the edges of the image in pixels.
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+	// Fill draws the middle of the image. The middle is left out otherwise,
+	// like in CSS.
+	Fill   bool
+	Repeat FlexRepeat
+}
+
+// slicePart is a part of an image drawn in a rectangle of the frame.
+type slicePart struct {
+	src, dst image.Rectangle
+}
+
+// sliceSpan is a part of an axis of an image drawn along the axis of the frame.
+type sliceSpan struct {
+	s0, s1, d0, d1 int
+}
+
+// parts returns the parts the border image is drawn in over the frame.
+// The edges are shrunk if they don't fit in the frame.
+func (b *NineSlice) parts(src, dst image.Rectangle) []slicePart {
+	f := 1.0
+	if h := b.Left + b.Right; h > dst.Dx() {
+		f = math.Min(f, float64(dst.Dx())/float64(h))
+	}
+	if v := b.Top + b.Bottom; v > dst.Dy() {
+		f = math.Min(f, float64(dst.Dy())/float64(v))
+	}
+	scaled := func(n int) int { return int(math.Round(float64(n) * f)) }
+	xs := [4]int{src.Min.X, src.Min.X + b.Left, src.Max.X - b.Right, src.Max.X}
+	ys := [4]int{src.Min.Y, src.Min.Y + b.Top, src.Max.Y - b.Bottom, src.Max.Y}
+	dxs := [4]int{dst.Min.X, dst.Min.X + scaled(b.Left), dst.Max.X - scaled(b.Right), dst.Max.X}
+	dys := [4]int{dst.Min.Y, dst.Min.Y + scaled(b.Top), dst.Max.Y - scaled(b.Bottom), dst.Max.Y}
+
+	var parts []slicePart
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			if i == 1 && j == 1 && !b.Fill {
+				continue
+			}
+			if xs[i] >= xs[i+1] || ys[j] >= ys[j+1] || dxs[i] >= dxs[i+1] || dys[j] >= dys[j+1] {
+				continue
+			}
+			// The edges are tiled along the frame, and the middle in both directions.
+			// The tiles are scaled as much as the corners.
+			repeatX, repeatY := RepeatStretch, RepeatStretch
+			if i == 1 {
+				repeatX = b.Repeat
+			}
+			if j == 1 {
+				repeatY = b.Repeat
+			}
+			for _, y := range tileSpans(ys[j], ys[j+1], dys[j], dys[j+1], f, repeatY) {
+				for _, x := range tileSpans(xs[i], xs[i+1], dxs[i], dxs[i+1], f, repeatX) {
+					parts = append(parts, slicePart{
+						src: image.Rect(x.s0, y.s0, x.s1, y.s1),
+						dst: image.Rect(x.d0, y.d0, x.d1, y.d1),
+					})
+				}
+			}
+		}
+	}
+	return parts
+}
+
+// tileSpans fits the span s0 to s1 of the image to the span d0 to d1 of the frame,
+// where the tiles are drawn in the scale.
+func tileSpans(s0, s1, d0, d1 int, scale float64, repeat FlexRepeat) []sliceSpan {
+	tile := float64(s1-s0) * scale
+	if repeat == RepeatStretch || tile <= 0 {
+		return []sliceSpan{{s0, s1, d0, d1}}
+	}
+	length := float64(d1 - d0)
+	if repeat == RepeatRound {
+		n := math.Max(1, math.Round(length/tile))
+		spans := make([]sliceSpan, int(n))
+		for k := range spans {
+			spans[k] = sliceSpan{s0, s1, d0 + int(math.Round(float64(k)*length/n)), d0 + int(math.Round(float64(k+1)*length/n))}
+		}
+		return spans
+	}
+	var spans []sliceSpan
+	for x := 0.0; x < length; x += tile {
+		span := sliceSpan{s0, s1, d0 + int(math.Round(x)), d0 + int(math.Round(x+tile))}
+		if span.d1 > d1 {
+			// Cut the last tile.
+			span.s1 = s0 + int(math.Round(float64(d1-span.d0)/scale))
+			span.d1 = d1
+		}
+		if span.d0 < span.d1 && span.s0 < span.s1 {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// drawBorderImage draws the border image of the view over its frame, in the opacity.
+func (v *View) drawBorderImage(screen *ebiten.Image, alpha float64) {
+	b := v.Attrs.BorderImage
+	img := lookupImage(b.Image)
+	if img == nil {
+		return
+	}
+	for _, p := range b.parts(img.Bounds(), v.frame) {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(float64(p.dst.Dx())/float64(p.src.Dx()), float64(p.dst.Dy())/float64(p.src.Dy()))
+		op.GeoM.Translate(float64(p.dst.Min.X), float64(p.dst.Min.Y))
+		op.GeoM.Scale(GlobalScale, GlobalScale)
+		op.ColorScale.ScaleAlpha(float32(alpha))
+		screen.DrawImage(img.SubImage(p.src).(*ebiten.Image), op)
+	}
+}
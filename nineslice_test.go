@@ -0,0 +1,130 @@
+package furex
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNineSliceParts(t *testing.T) {
+	src := image.Rect(0, 0, 30, 30)
+	dsts := func(parts []slicePart) []image.Rectangle {
+		var rects []image.Rectangle
+		for _, p := range parts {
+			rects = append(rects, p.dst)
+		}
+		return rects
+	}
+
+	// The corners keep their size and the edges are stretched. The middle is left out.
+	b := &NineSlice{Top: 10, Right: 10, Bottom: 10, Left: 10}
+	parts := b.parts(src, image.Rect(0, 0, 100, 50))
+	require.Equal(t, []image.Rectangle{
+		image.Rect(0, 0, 10, 10), image.Rect(10, 0, 90, 10), image.Rect(90, 0, 100, 10),
+		image.Rect(0, 10, 10, 40), image.Rect(90, 10, 100, 40),
+		image.Rect(0, 40, 10, 50), image.Rect(10, 40, 90, 50), image.Rect(90, 40, 100, 50),
+	}, dsts(parts))
+	require.Equal(t, image.Rect(10, 0, 20, 10), parts[1].src)
+
+	b.Fill = true
+	parts = b.parts(src, image.Rect(0, 0, 100, 50))
+	require.Len(t, parts, 9)
+	require.Equal(t, slicePart{src: image.Rect(10, 10, 20, 20), dst: image.Rect(10, 10, 90, 40)}, parts[4])
+
+	// The edges are shrunk if they don't fit.
+	b.Fill = false
+	require.Equal(t, []image.Rectangle{
+		image.Rect(0, 0, 5, 5), image.Rect(5, 0, 10, 5),
+		image.Rect(0, 5, 5, 10), image.Rect(5, 5, 10, 10),
+	}, dsts(b.parts(src, image.Rect(0, 0, 10, 10))))
+}
+
+func TestTileSpans(t *testing.T) {
+	tests := []struct {
+		name   string
+		repeat FlexRepeat
+		d1     int
+		scale  float64
+		want   []sliceSpan
+	}{
+		{
+			name:   "stretch",
+			repeat: RepeatStretch,
+			d1:     25,
+			scale:  1,
+			want:   []sliceSpan{{10, 20, 0, 25}},
+		},
+		{
+			name:   "repeat",
+			repeat: RepeatTile,
+			d1:     25,
+			scale:  1,
+			want:   []sliceSpan{{10, 20, 0, 10}, {10, 20, 10, 20}, {10, 15, 20, 25}},
+		},
+		{
+			name:   "repeat scaled",
+			repeat: RepeatTile,
+			d1:     12,
+			scale:  0.5,
+			want:   []sliceSpan{{10, 20, 0, 5}, {10, 20, 5, 10}, {10, 14, 10, 12}},
+		},
+		{
+			name:   "round",
+			repeat: RepeatRound,
+			d1:     24,
+			scale:  1,
+			want:   []sliceSpan{{10, 20, 0, 12}, {10, 20, 12, 24}},
+		},
+		{
+			name:   "round at least one",
+			repeat: RepeatRound,
+			d1:     4,
+			scale:  1,
+			want:   []sliceSpan{{10, 20, 0, 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tileSpans(10, 20, 0, tt.d1, tt.scale, tt.repeat))
+		})
+	}
+}
+
+func TestRegisterAtlas(t *testing.T) {
+	sheet := ebiten.NewImage(64, 32)
+	atlas := `<TextureAtlas imagePath="sheet.png">
+		<SubTexture name="panel_blue.png" x="0" y="0" width="32" height="32"/>
+		<SubTexture name="button" x="32" y="8" width="16" height="8"/>
+	</TextureAtlas>`
+	require.NoError(t, RegisterAtlas(strings.NewReader(atlas), sheet))
+	defer func() {
+		delete(images, "panel_blue.png")
+		delete(images, "panel_blue")
+		delete(images, "button")
+	}()
+
+	require.Equal(t, image.Rect(0, 0, 32, 32), lookupImage("panel_blue.png").Bounds())
+	require.Same(t, lookupImage("panel_blue.png"), lookupImage("panel_blue"))
+	require.Equal(t, image.Rect(32, 8, 48, 16), lookupImage("button").Bounds())
+
+	require.Error(t, RegisterAtlas(strings.NewReader(`<TextureAtlas>`), sheet))
+	require.Error(t, RegisterAtlas(strings.NewReader(`<TextureAtlas><SubTexture name="x" x="60" y="0" width="8" height="8"/></TextureAtlas>`), sheet))
+	// Nothing is registered from an atlas with an invalid sub texture.
+	require.Error(t, RegisterAtlas(strings.NewReader(`<TextureAtlas>
+		<SubTexture name="valid" x="0" y="0" width="8" height="8"/>
+		<SubTexture name="" x="0" y="0" width="8" height="8"/>
+	</TextureAtlas>`), sheet))
+	require.Nil(t, lookupImage("valid"))
+
+	// Views draw the nine slices of the image over their frame.
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(60)}}
+	root.AddChild(&View{Attrs: ViewAttrs{
+		Grow:        1,
+		BorderImage: &NineSlice{Image: "panel_blue", Top: 8, Right: 8, Bottom: 8, Left: 8, Fill: true, Repeat: RepeatRound},
+	}})
+	root.Update()
+	root.Draw(ebiten.NewImage(100, 60))
+}
@@ -0,0 +1,288 @@
+package furex
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// scrollState is the scroll state of a scroll container.
+type scrollState struct {
+	// x and y is the scroll offset of the content.
+	x, y float64
+	// velocityX and velocityY is the speed of inertia scrolling in pixels per tick.
+	velocityX, velocityY float64
+	// contentWidth and contentHeight is the size of the content including the padding.
+	contentWidth, contentHeight int
+
+	isDragging   bool
+	dragTouchID  ebiten.TouchID
+	lastX, lastY int
+}
+
+const scrollWheelStep = 40.
+const scrollFriction = 0.95
+const scrollMinVelocity = 0.5
+
+// isScrollContainer returns true if the children of the view are clipped and scrolled.
+func (v *View) isScrollContainer() bool {
+	return v.Attrs.Overflow != OverflowVisible
+}
+
+// ScrollOffset returns how far the content of the view is scrolled.
+func (v *View) ScrollOffset() (x, y int) {
+	return round(v.scroll.x), round(v.scroll.y)
+}
+
+// ScrollTo scrolls the view so that the descendant view is visible in its frame.
+// It scrolls as little as needed, aligning the descendant to the nearest edge.
+func (v *View) ScrollTo(view *View) {
+	if !v.isScrollContainer() {
+		return
+	}
+	v.relayout()
+	dx := scrollDelta(view.frame.Min.X, view.frame.Max.X, v.frame.Min.X, v.frame.Max.X)
+	dy := scrollDelta(view.frame.Min.Y, view.frame.Max.Y, v.frame.Min.Y, v.frame.Max.Y)
+	v.scrollBy(float64(dx), float64(dy))
+}
+
+// scrollIntoView scrolls the scroll containers of the view so that it is visible.
+func (v *View) scrollIntoView() {
+	for p := v.parent; p != nil; p = p.parent {
+		p.ScrollTo(v)
+	}
+}
+
+func scrollDelta(min, max, portMin, portMax int) int {
+	switch {
+	case min < portMin:
+		return min - portMin
+	case max > portMax:
+		if max-portMax > min-portMin {
+			return min - portMin
+		}
+		return max - portMax
+	}
+	return 0
+}
+
+// layoutScroll measures the content of the scroll container after its layout
+// and moves the children by the scroll offset.
+func (v *View) layoutScroll() {
+	w, h := 0, 0
+	for _, c := range v.children {
+		if c.Attrs.Display == DisplayNone {
+			continue
+		}
+		if r := c.bounds.Max.X + c.used.MarginRight; r > w {
+			w = r
+		}
+		if b := c.bounds.Max.Y + c.used.MarginBottom; b > h {
+			h = b
+		}
+	}
+	v.scroll.contentWidth = w + v.used.PaddingRight
+	v.scroll.contentHeight = h + v.used.PaddingBottom
+
+	maxX, maxY := v.maxScroll()
+	v.scroll.x = clamp(v.scroll.x, 0, maxX)
+	v.scroll.y = clamp(v.scroll.y, 0, maxY)
+	v.translateChildren(image.Pt(-round(v.scroll.x), -round(v.scroll.y)))
+}
+
+func (v *View) maxScroll() (float64, float64) {
+	x := float64(v.scroll.contentWidth - v.frame.Dx())
+	y := float64(v.scroll.contentHeight - v.frame.Dy())
+	return math.Max(0, x), math.Max(0, y)
+}
+
+// scrollBy scrolls the content by (dx, dy) within the scrollable range.
+// It returns false if the content didn't move.
+func (v *View) scrollBy(dx, dy float64) bool {
+	maxX, maxY := v.maxScroll()
+	x := clamp(v.scroll.x+dx, 0, maxX)
+	y := clamp(v.scroll.y+dy, 0, maxY)
+	if x == v.scroll.x && y == v.scroll.y {
+		return false
+	}
+	d := image.Pt(round(v.scroll.x)-round(x), round(v.scroll.y)-round(y))
+	v.scroll.x, v.scroll.y = x, y
+	v.translateChildren(d)
+	return true
+}
+
+// translateChildren moves the children and all their descendants by d,
+// without laying them out again.
+func (v *View) translateChildren(d image.Point) {
+	if d == (image.Point{}) {
+		return
+	}
+	for _, c := range v.children {
+		c.bounds = c.bounds.Add(d)
+		c.translate(d)
+	}
+}
+
+func (v *View) translate(d image.Point) {
+	v.frame = v.frame.Add(d)
+	for _, c := range v.children {
+		c.translate(d)
+	}
+}
+
+// updateScroll continues scrolling after a drag is released, slowing down by friction.
+func (v *View) updateScroll() {
+	s := &v.scroll
+	if s.isDragging || (s.velocityX == 0 && s.velocityY == 0) {
+		return
+	}
+	if !v.scrollBy(s.velocityX, s.velocityY) {
+		s.velocityX, s.velocityY = 0, 0
+		return
+	}
+	s.velocityX *= scrollFriction
+	s.velocityY *= scrollFriction
+	if math.Abs(s.velocityX) < scrollMinVelocity {
+		s.velocityX = 0
+	}
+	if math.Abs(s.velocityY) < scrollMinVelocity {
+		s.velocityY = 0
+	}
+}
+
+// scrollTarget returns the innermost view that can be scrolled by the user
+// at (x, y) in the direction of (dx, dy). Any direction matches if both are 0.
+func (ct *View) scrollTarget(x, y int, dx, dy float64) *View {
+	for v := ct.viewAt(x, y); v != nil; v = v.parent {
+		if v.Attrs.Overflow == OverflowScroll && v.canScroll(dx, dy) {
+			return v
+		}
+		if v == ct {
+			break
+		}
+	}
+	return nil
+}
+
+func (v *View) canScroll(dx, dy float64) bool {
+	maxX, maxY := v.maxScroll()
+	if dx == 0 && dy == 0 {
+		return maxX > 0 || maxY > 0
+	}
+	return (dx < 0 && v.scroll.x > 0) || (dx > 0 && v.scroll.x < maxX) ||
+		(dy < 0 && v.scroll.y > 0) || (dy > 0 && v.scroll.y < maxY)
+}
+
+// handleWheel scrolls the scroll container under the cursor by the mouse wheel.
+func (ct *View) handleWheel(x, y int, wheelX, wheelY float64) bool {
+	dx, dy := -wheelX*scrollWheelStep, -wheelY*scrollWheelStep
+	if v := ct.scrollTarget(x, y, dx, dy); v != nil {
+		v.scroll.velocityX, v.scroll.velocityY = 0, 0
+		return v.scrollBy(dx, dy)
+	}
+	return false
+}
+
+// startScrollDrag starts dragging the scroll container under the touch.
+func (ct *View) startScrollDrag(touchID ebiten.TouchID, x, y int) {
+	if v := ct.scrollTarget(x, y, 0, 0); v != nil {
+		s := &v.scroll
+		s.isDragging, s.dragTouchID = true, touchID
+		s.lastX, s.lastY = x, y
+		s.velocityX, s.velocityY = 0, 0
+	}
+}
+
+// dragScroll scrolls the scroll container dragged by the touch to follow it.
+func (ct *View) dragScroll(touchID ebiten.TouchID, x, y int) {
+	v := ct.findScrollDrag(touchID)
+	if v == nil {
+		return
+	}
+	s := &v.scroll
+	dx, dy := float64(s.lastX-x), float64(s.lastY-y)
+	s.lastX, s.lastY = x, y
+	v.scrollBy(dx, dy)
+	s.velocityX, s.velocityY = dx, dy
+}
+
+// endScrollDrag releases the scroll container dragged by the touch,
+// which keeps scrolling by inertia.
+func (ct *View) endScrollDrag(touchID ebiten.TouchID) {
+	if v := ct.findScrollDrag(touchID); v != nil {
+		v.scroll.isDragging = false
+	}
+}
+
+func (v *View) findScrollDrag(touchID ebiten.TouchID) *View {
+	if v.scroll.isDragging && v.scroll.dragTouchID == touchID {
+		return v
+	}
+	for _, c := range v.children {
+		if found := c.findScrollDrag(touchID); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (v *View) hasScrollbars() bool {
+	return v.Attrs.Overflow == OverflowScroll && v.Attrs.ScrollbarWidth > 0
+}
+
+// drawScrollbars draws the scrollbar thumbs of the scroll container over its content.
+// The thumbs of a transformed container are drawn on its offscreen image,
+// which is drawn on the screen with its transform.
+func (v *View) drawScrollbars(screen *ebiten.Image) {
+	w := v.Attrs.ScrollbarWidth
+	if w == 0 || screen == nil {
+		return
+	}
+	maxX, maxY := v.maxScroll()
+	if maxX <= 0 && maxY <= 0 {
+		return
+	}
+	m, transformed := v.transform()
+	frame := scaleFrame(v.frame)
+	dst := screen
+	if transformed {
+		dst = v.offscreen(frame)
+	}
+	f := v.frame
+	if maxY > 0 {
+		length := scrollbarThumbLength(f.Dy(), v.scroll.contentHeight, w)
+		pos := f.Min.Y + round(v.scroll.y/maxY*float64(f.Dy()-length))
+		graphic.FillRect(dst, &graphic.FillRectOpts{
+			Rect:  scaleFrame(image.Rect(f.Max.X-w, pos, f.Max.X, pos+length)),
+			Color: ScrollbarColor,
+		})
+	}
+	if maxX > 0 {
+		length := scrollbarThumbLength(f.Dx(), v.scroll.contentWidth, w)
+		pos := f.Min.X + round(v.scroll.x/maxX*float64(f.Dx()-length))
+		graphic.FillRect(dst, &graphic.FillRectOpts{
+			Rect:  scaleFrame(image.Rect(pos, f.Max.Y-w, pos+length, f.Max.Y)),
+			Color: ScrollbarColor,
+		})
+	}
+	if transformed {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(frame.Min.X), float64(frame.Min.Y))
+		op.GeoM.Concat(m)
+		op.Filter = ebiten.FilterLinear
+		screen.DrawImage(dst, op)
+	}
+}
+
+func scrollbarThumbLength(portSize, contentSize, width int) int {
+	length := portSize * portSize / contentSize
+	if length < width*2 {
+		length = width * 2
+	}
+	if length > portSize {
+		length = portSize
+	}
+	return length
+}
@@ -0,0 +1,160 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func newScrollList(overflow FlexOverflow) (*View, *View, []*mockHandler) {
+	list := &View{
+		Attrs: ViewAttrs{
+			Width:     Px(100),
+			Height:    Px(100),
+			Direction: Column,
+			Overflow:  overflow,
+		},
+	}
+	var mocks []*mockHandler
+	for i := 0; i < 5; i++ {
+		mock := NewMockHandler()
+		mocks = append(mocks, mock)
+		list.AddChild(&View{
+			Attrs:   ViewAttrs{Width: Px(100), Height: Px(40)},
+			Handler: mock.ViewHandler,
+		})
+	}
+	root := &View{
+		Attrs: ViewAttrs{
+			Width:      Px(200),
+			Height:     Px(300),
+			Direction:  Column,
+			AlignItems: AlignItemStart,
+		},
+	}
+	root.AddChild(list)
+	root.Update()
+	root.Draw(nil)
+	return root, list, mocks
+}
+
+func TestScrollWheel(t *testing.T) {
+	root, list, mocks := newScrollList(OverflowScroll)
+
+	require.True(t, root.handleWheel(50, 50, 0, -1))
+	root.Draw(nil)
+	x, y := list.ScrollOffset()
+	require.Equal(t, 0, x)
+	require.Equal(t, 40, y)
+	// The first item is scrolled out of the list, so it is laid out but not drawn.
+	require.Equal(t, image.Rect(0, -40, 100, 0), list.children[0].frame)
+	require.Equal(t, image.Rect(0, 0, 100, 40), mocks[1].Frame)
+
+	// The offset is clamped to the size of the content.
+	root.handleWheel(50, 50, 0, -10)
+	_, y = list.ScrollOffset()
+	require.Equal(t, 100, y)
+	require.False(t, root.handleWheel(50, 50, 0, -1))
+
+	// Wheel events outside of the list are ignored.
+	require.False(t, root.handleWheel(150, 150, 0, 1))
+}
+
+func TestScrollHitTestClipped(t *testing.T) {
+	root, list, mocks := newScrollList(OverflowScroll)
+
+	root.handleWheel(50, 50, 0, -1)
+	root.Draw(nil)
+	// The last item is below the frame of the list, so it is laid out but not drawn.
+	require.Equal(t, image.Rect(0, 120, 100, 160), list.children[4].frame)
+
+	root.handleMouseButtonLeftPressed(nil, 50, 130)
+	require.False(t, mocks[4].IsClickPressed)
+
+	root.handleMouseButtonLeftPressed(nil, 50, 90)
+	require.True(t, mocks[3].IsClickPressed)
+
+	_, y := list.ScrollOffset()
+	require.Equal(t, 40, y)
+}
+
+func TestScrollTo(t *testing.T) {
+	root, list, mocks := newScrollList(OverflowHidden)
+
+	// Hidden overflow can't be scrolled by the user.
+	require.False(t, root.handleWheel(50, 50, 0, -1))
+
+	list.ScrollTo(list.children[3])
+	root.Draw(nil)
+	_, y := list.ScrollOffset()
+	require.Equal(t, 60, y)
+	require.Equal(t, image.Rect(0, 60, 100, 100), mocks[3].Frame)
+
+	list.ScrollTo(list.children[2])
+	_, y = list.ScrollOffset()
+	require.Equal(t, 60, y)
+
+	list.ScrollTo(list.children[0])
+	_, y = list.ScrollOffset()
+	require.Equal(t, 0, y)
+}
+
+func TestScrollDrag(t *testing.T) {
+	root, list, _ := newScrollList(OverflowScroll)
+
+	root.startScrollDrag(1, 50, 50)
+	root.dragScroll(1, 50, 40)
+	_, y := list.ScrollOffset()
+	require.Equal(t, 10, y)
+
+	root.dragScroll(1, 50, 30)
+	root.endScrollDrag(1)
+	_, y = list.ScrollOffset()
+	require.Equal(t, 20, y)
+
+	// The list keeps scrolling by inertia after the touch is released.
+	root.Update()
+	_, y = list.ScrollOffset()
+	require.Equal(t, 30, y)
+	for i := 0; i < 100; i++ {
+		root.Update()
+	}
+	_, y = list.ScrollOffset()
+	require.Greater(t, y, 30)
+	require.Zero(t, list.scroll.velocityY)
+}
+
+func TestScrollRelayout(t *testing.T) {
+	root, list, mocks := newScrollList(OverflowScroll)
+
+	root.handleWheel(50, 50, 0, -1)
+	list.SetPaddingTop(10)
+	root.Update()
+	root.Draw(nil)
+
+	_, y := list.ScrollOffset()
+	require.Equal(t, 40, y)
+	require.Equal(t, image.Rect(0, -30, 100, 10), mocks[0].Frame)
+
+	// The offset is clamped when the content gets smaller.
+	list.RemoveChild(list.children[4])
+	list.RemoveChild(list.children[3])
+	root.Update()
+	root.Draw(nil)
+	_, y = list.ScrollOffset()
+	require.Equal(t, 30, y)
+}
+
+func TestScrollbarsTransformed(t *testing.T) {
+	root, list, _ := newScrollList(OverflowScroll)
+	list.SetScrollbarWidth(4)
+	list.SetTransform(TransformFunc{Func: TransformRotate, X: 0.5})
+	screen := ebiten.NewImage(200, 300)
+
+	// The thumbs are drawn on the offscreen image of the list with its transform.
+	root.Draw(screen)
+	require.NotNil(t, list.layer)
+	require.Equal(t, image.Rect(0, 0, 100, 100), list.layer.Bounds())
+}
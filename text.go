@@ -0,0 +1,812 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/basicfont"
+)
+
+// FlexTextAlign is the 'text-align' property
+type FlexTextAlign uint8
+
+const (
+	// TextAlignInherit takes the text alignment of the parent view.
+	TextAlignInherit FlexTextAlign = iota
+	TextAlignLeft
+	TextAlignCenter
+	TextAlignRight
+)
+
+func (a FlexTextAlign) String() string {
+	switch a {
+	case TextAlignInherit:
+		return "inherit"
+	case TextAlignLeft:
+		return "left"
+	case TextAlignCenter:
+		return "center"
+	case TextAlignRight:
+		return "right"
+	}
+	return fmt.Sprintf("unknown text align: %d", a)
+}
+
+// FlexVerticalAlign is the 'vertical-align' property,
+// the position of the lines of text in the frame of the view.
+type FlexVerticalAlign uint8
+
+const (
+	VerticalAlignTop FlexVerticalAlign = iota
+	VerticalAlignMiddle
+	VerticalAlignBottom
+)
+
+func (a FlexVerticalAlign) String() string {
+	switch a {
+	case VerticalAlignTop:
+		return "top"
+	case VerticalAlignMiddle:
+		return "middle"
+	case VerticalAlignBottom:
+		return "bottom"
+	}
+	return fmt.Sprintf("unknown vertical align: %d", a)
+}
+
+// FlexWhiteSpace is the 'white-space' property
+type FlexWhiteSpace uint8
+
+const (
+	// WhiteSpaceInherit takes the white space handling of the parent view.
+	WhiteSpaceInherit FlexWhiteSpace = iota
+	// WhiteSpaceNormal collapses white spaces and line breaks, and wraps lines.
+	WhiteSpaceNormal
+	// WhiteSpaceNoWrap collapses white spaces and line breaks, and doesn't wrap lines.
+	WhiteSpaceNoWrap
+	// WhiteSpacePre keeps white spaces and line breaks, and doesn't wrap lines.
+	WhiteSpacePre
+	// WhiteSpacePreWrap keeps white spaces and line breaks, and wraps lines.
+	WhiteSpacePreWrap
+	// WhiteSpacePreLine collapses white spaces but keeps line breaks, and wraps lines.
+	WhiteSpacePreLine
+)
+
+func (w FlexWhiteSpace) String() string {
+	switch w {
+	case WhiteSpaceInherit:
+		return "inherit"
+	case WhiteSpaceNormal:
+		return "normal"
+	case WhiteSpaceNoWrap:
+		return "nowrap"
+	case WhiteSpacePre:
+		return "pre"
+	case WhiteSpacePreWrap:
+		return "pre-wrap"
+	case WhiteSpacePreLine:
+		return "pre-line"
+	}
+	return fmt.Sprintf("unknown white space: %d", w)
+}
+
+func (w FlexWhiteSpace) wraps() bool {
+	return w == WhiteSpaceNormal || w == WhiteSpacePreWrap || w == WhiteSpacePreLine
+}
+
+// FlexTextOverflow is the 'text-overflow' property
+type FlexTextOverflow uint8
+
+const (
+	// TextOverflowClip lets lines wider than the view overflow it.
+	TextOverflowClip FlexTextOverflow = iota
+	// TextOverflowEllipsis cuts lines wider than the view and ends them with an ellipsis.
+	TextOverflowEllipsis
+)
+
+func (o FlexTextOverflow) String() string {
+	switch o {
+	case TextOverflowClip:
+		return "clip"
+	case TextOverflowEllipsis:
+		return "ellipsis"
+	}
+	return fmt.Sprintf("unknown text overflow: %d", o)
+}
+
+// FontStyle selects the bold and italic faces of a font family.
+type FontStyle uint8
+
+const (
+	FontStyleRegular FontStyle = 0
+	FontStyleBold    FontStyle = 1
+	FontStyleItalic  FontStyle = 2
+)
+
+// FontFace returns the face of a font in the size in pixels.
+type FontFace func(size float64) text.Face
+
+type fontKey struct {
+	family string
+	style  FontStyle
+}
+
+var fontFaces = map[fontKey]FontFace{}
+
+// faces caches the faces of the registered fonts by size.
+var faces = map[faceKey]text.Face{}
+
+type faceKey struct {
+	fontKey
+	size float64
+}
+
+// RegisterFont registers the font of a font family, which is selected with
+// the FontFamily attribute or the font-family CSS property. The font registered
+// with the empty name is used by the views without a font family.
+//
+// Without registered fonts, text is drawn in a 7x13 bitmap font
+// scaled by whole numbers to the nearest font size.
+func RegisterFont(family string, face FontFace) {
+	RegisterFontStyle(family, FontStyleRegular, face)
+}
+
+// RegisterFontStyle registers the face of a font family in a style, such as
+// the bold face of <b> in rich text. The text in a style without a registered
+// face is drawn in the regular face, made bold or slanted.
+func RegisterFontStyle(family string, style FontStyle, face FontFace) {
+	fontFaces[fontKey{family, style}] = face
+	faces = map[faceKey]text.Face{}
+}
+
+// GoTextFont returns the FontFace of an OpenType or TrueType font.
+func GoTextFont(src *text.GoTextFaceSource) FontFace {
+	return func(size float64) text.Face {
+		return &text.GoTextFace{Source: src, Size: size}
+	}
+}
+
+var defaultFontFace = text.NewGoXFace(basicfont.Face7x13)
+
+// defaultColor is the color of text when no view sets it.
+var defaultColor color.Color = color.White
+
+// italicSkew is the slant of the glyphs of fonts without an italic face.
+const italicSkew = 0.2
+
+// fontStyle is the font a run of text is drawn in.
+type fontStyle struct {
+	face text.Face
+	// scale is the scale the face is drawn in.
+	scale    float64
+	ellipsis string
+	color    color.Color
+	// synthetic is the style the face is made bold or slanted in,
+	// if the font has no face for it.
+	synthetic FontStyle
+}
+
+// resolveFont returns the font of the family in the size and style.
+// Families without registered fonts fall back to the font registered
+// with the empty name, then to the bitmap font.
+func resolveFont(family string, size float64, style FontStyle, clr color.Color) fontStyle {
+	f := fontStyle{color: clr}
+	for _, family := range []string{family, ""} {
+		for _, st := range []FontStyle{style, style &^ FontStyleItalic, style &^ FontStyleBold, FontStyleRegular} {
+			key := fontKey{family, st}
+			face, ok := fontFaces[key]
+			if !ok {
+				continue
+			}
+			fk := faceKey{key, size}
+			if faces[fk] == nil {
+				faces[fk] = face(size)
+			}
+			f.face, f.scale, f.ellipsis, f.synthetic = faces[fk], 1, "…", style&^st
+			return f
+		}
+	}
+	// The bitmap font has no glyph for the ellipsis character.
+	h := float64(basicfont.Face7x13.Height)
+	f.face, f.scale, f.ellipsis, f.synthetic = defaultFontFace, math.Max(1, math.Round(size/h)), "...", style
+	return f
+}
+
+// normalLineHeight is the height of a line given by the metrics of the font.
+func (f *fontStyle) normalLineHeight() float64 {
+	m := f.face.Metrics()
+	return (m.HAscent + m.HDescent + m.HLineGap) * f.scale
+}
+
+// ascent is the height of the glyphs above the baseline.
+func (f *fontStyle) ascent() float64 {
+	return f.face.Metrics().HAscent * f.scale
+}
+
+// iconSize returns the size of an icon drawn as a glyph, which is as high
+// as the glyphs of the font.
+func (f *fontStyle) iconSize(img *ebiten.Image) (float64, float64) {
+	m := f.face.Metrics()
+	h := (m.HAscent + m.HDescent) * f.scale
+	size := img.Bounds().Size()
+	return h * float64(size.X) / float64(size.Y), h
+}
+
+// advance returns the width of the text.
+func (f *fontStyle) advance(str string) float64 {
+	return text.Advance(str, f.face) * f.scale
+}
+
+// fit returns the length in bytes of the longest prefix of str that fits in the width.
+// The prefix has one character at least if atLeastOne is true.
+func (f *fontStyle) fit(str string, width float64, atLeastOne bool) int {
+	n := 0
+	if atLeastOne {
+		_, n = utf8.DecodeRuneInString(str)
+	}
+	for i := range str {
+		if i <= n {
+			continue
+		}
+		if f.advance(str[:i]) > width {
+			return n
+		}
+		n = i
+	}
+	if f.advance(str) <= width {
+		return len(str)
+	}
+	return n
+}
+
+// drawString draws the text with its baseline at (x, baseline), in the color.
+func (f *fontStyle) drawString(screen *ebiten.Image, str string, x, baseline float64, clr color.Color) {
+	op := &text.DrawOptions{}
+	op.GeoM.Scale(f.scale, f.scale)
+	op.GeoM.Translate(0, -f.ascent())
+	if f.synthetic&FontStyleItalic != 0 {
+		op.GeoM.Skew(-italicSkew, 0)
+	}
+	op.GeoM.Translate(x, baseline)
+	op.ColorScale.ScaleWithColor(clr)
+	geoM := op.GeoM
+	op.GeoM.Scale(GlobalScale, GlobalScale)
+	text.Draw(screen, str, f.face, op)
+	if f.synthetic&FontStyleBold != 0 {
+		// Draw the glyphs again one pixel of the font to the right.
+		op.GeoM = geoM
+		op.GeoM.Translate(f.scale, 0)
+		op.GeoM.Scale(GlobalScale, GlobalScale)
+		text.Draw(screen, str, f.face, op)
+	}
+}
+
+// drawIcon draws the icon as a glyph with the baseline at (x, baseline), in the opacity.
+func (f *fontStyle) drawIcon(screen *ebiten.Image, img *ebiten.Image, x, baseline, alpha float64) {
+	_, h := f.iconSize(img)
+	scale := h / float64(img.Bounds().Dy())
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(x, baseline-f.ascent())
+	op.GeoM.Scale(GlobalScale, GlobalScale)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(img, op)
+}
+
+// TextSpan is a run of text in the same style, a line break or an icon,
+// in the rich text of a view.
+type TextSpan struct {
+	// Text is the text of the span.
+	Text string
+	// Bold and Italic draw the text in the bold and italic faces of the font family.
+	Bold   bool
+	Italic bool
+	// Color is the color of the text. The color of the view is used if it is nil.
+	Color color.Color
+	// Icon is the name of an image registered with RegisterImage, which is drawn
+	// in place of the text as a glyph as high as the font.
+	Icon string
+	// Break breaks the line after the span.
+	Break bool
+}
+
+func (s TextSpan) fontStyle() FontStyle {
+	style := FontStyleRegular
+	if s.Bold {
+		style |= FontStyleBold
+	}
+	if s.Italic {
+		style |= FontStyleItalic
+	}
+	return style
+}
+
+// spansText returns the text of the spans, with a new line for each line break.
+func spansText(spans []TextSpan) string {
+	var sb strings.Builder
+	for _, s := range spans {
+		sb.WriteString(s.Text)
+		if s.Break {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// textStyle is the resolved style of the text of a view.
+type textStyle struct {
+	fontStyle
+	family        string
+	size          float64
+	lineHeight    float64
+	align         FlexTextAlign
+	verticalAlign FlexVerticalAlign
+	whiteSpace    FlexWhiteSpace
+	overflow      FlexTextOverflow
+}
+
+// textStyle resolves the text style of the view. Color, font family, font size,
+// line height, text alignment and white space are inherited from the ancestors
+// if the view doesn't set them, as in CSS. It returns the default style for nil.
+func (v *View) textStyle() textStyle {
+	s := textStyle{}
+	var clr color.Color
+	lineHeight := Auto
+	if v != nil {
+		s.verticalAlign, s.overflow = v.Attrs.VerticalAlign, v.Attrs.TextOverflow
+	}
+	for p := v; p != nil; p = p.parent {
+		a := &p.Attrs
+		if clr == nil {
+			clr = a.Color
+		}
+		if s.family == "" {
+			s.family = a.FontFamily
+		}
+		if lineHeight.Unit == LengthAuto {
+			lineHeight = a.LineHeight
+		}
+		if s.align == TextAlignInherit {
+			s.align = a.TextAlign
+		}
+		if s.whiteSpace == WhiteSpaceInherit {
+			s.whiteSpace = a.WhiteSpace
+		}
+	}
+	if clr == nil {
+		clr = defaultColor
+	}
+	ctx := lengthContext{fontSize: BaseFontSize, rootFontSize: BaseFontSize}
+	if v != nil {
+		ctx = v.lengthContext()
+	}
+	s.size = ctx.fontSize
+	if s.align == TextAlignInherit {
+		s.align = TextAlignLeft
+	}
+	if s.whiteSpace == WhiteSpaceInherit {
+		s.whiteSpace = WhiteSpaceNormal
+	}
+	s.fontStyle = resolveFont(s.family, s.size, FontStyleRegular, clr)
+
+	s.lineHeight = s.normalLineHeight()
+	if lineHeight.Unit != LengthAuto {
+		if h, ok := lineHeight.resolve(s.size, ctx); ok {
+			s.lineHeight = h
+		}
+	}
+	return s
+}
+
+// spanFont returns the font of a span of the text.
+func (s *textStyle) spanFont(span TextSpan) *fontStyle {
+	clr := s.color
+	if span.Color != nil {
+		clr = span.Color
+	}
+	f := resolveFont(s.family, s.size, span.fontStyle(), clr)
+	return &f
+}
+
+// baseline is the offset of the baseline from the top of a line box.
+// The glyphs of the font are centered in the line box.
+func (s *textStyle) baseline() float64 {
+	return (s.lineHeight-s.normalLineHeight())/2 + s.ascent()
+}
+
+// draw draws a line of text in the line box at (x, y), in the color.
+func (s *textStyle) draw(screen *ebiten.Image, str string, x, y float64, clr color.Color) {
+	s.drawString(screen, str, x, y+s.baseline(), clr)
+}
+
+// textRun is a part of a line of text drawn in the same font, or an icon.
+type textRun struct {
+	text  string
+	font  *fontStyle
+	icon  *ebiten.Image
+	width float64
+}
+
+// textLine is a line of text broken to the width of a view.
+type textLine struct {
+	runs  []textRun
+	width float64
+}
+
+// text returns the text of the line.
+func (l *textLine) text() string {
+	var sb strings.Builder
+	for _, r := range l.runs {
+		sb.WriteString(r.text)
+	}
+	return sb.String()
+}
+
+// appendRun appends a run to the runs. Text in the same font as the last run
+// is joined to it, and empty text is dropped.
+func appendRun(runs []textRun, r textRun) []textRun {
+	if r.icon == nil && r.text == "" {
+		return runs
+	}
+	if n := len(runs); n > 0 && r.icon == nil && runs[n-1].icon == nil && runs[n-1].font == r.font {
+		last := &runs[n-1]
+		last.text += r.text
+		last.width = last.font.advance(last.text)
+		return runs
+	}
+	if r.icon == nil {
+		r.width = r.font.advance(r.text)
+	}
+	return append(runs, r)
+}
+
+// joinRuns returns a copy of the runs with the other runs appended.
+func joinRuns(runs, other []textRun) []textRun {
+	runs = append([]textRun(nil), runs...)
+	for _, r := range other {
+		runs = appendRun(runs, r)
+	}
+	return runs
+}
+
+// trimRuns returns a copy of the runs without the white spaces at the end.
+func trimRuns(runs []textRun) []textRun {
+	runs = append([]textRun(nil), runs...)
+	for len(runs) > 0 {
+		last := &runs[len(runs)-1]
+		if last.icon != nil {
+			break
+		}
+		if t := strings.TrimRightFunc(last.text, unicode.IsSpace); t != "" {
+			if t != last.text {
+				last.text, last.width = t, last.font.advance(t)
+			}
+			break
+		}
+		runs = runs[:len(runs)-1]
+	}
+	return runs
+}
+
+func runsWidth(runs []textRun) float64 {
+	w := 0.0
+	for _, r := range runs {
+		w += r.width
+	}
+	return w
+}
+
+// paragraphs splits the spans into paragraphs of runs at the line breaks,
+// handling the white spaces as the white space property of the text says.
+func (s *textStyle) paragraphs(spans []TextSpan) [][]textRun {
+	collapse := s.whiteSpace != WhiteSpacePre && s.whiteSpace != WhiteSpacePreWrap
+	keepNewLines := s.whiteSpace != WhiteSpaceNormal && s.whiteSpace != WhiteSpaceNoWrap
+
+	paragraphs := [][]textRun{nil}
+	// space is true after a collapsed white space or at the start of a paragraph.
+	space := true
+	endParagraph := func() {
+		p := &paragraphs[len(paragraphs)-1]
+		if collapse {
+			*p = trimRuns(*p)
+		}
+		paragraphs = append(paragraphs, nil)
+		space = true
+	}
+	for _, span := range spans {
+		f := s.spanFont(span)
+		if span.Icon != "" {
+			if img := lookupImage(span.Icon); img != nil {
+				w, _ := f.iconSize(img)
+				p := &paragraphs[len(paragraphs)-1]
+				*p = appendRun(*p, textRun{font: f, icon: img, width: w})
+				space = false
+			}
+		} else {
+			var sb strings.Builder
+			flush := func() {
+				p := &paragraphs[len(paragraphs)-1]
+				*p = appendRun(*p, textRun{text: sb.String(), font: f})
+				sb.Reset()
+			}
+			for _, r := range span.Text {
+				switch {
+				case r == '\n' && keepNewLines:
+					flush()
+					endParagraph()
+				case collapse && unicode.IsSpace(r):
+					if !space {
+						sb.WriteByte(' ')
+						space = true
+					}
+				case r == '\t':
+					sb.WriteString("    ")
+				default:
+					sb.WriteRune(r)
+					space = false
+				}
+			}
+			flush()
+		}
+		if span.Break {
+			endParagraph()
+		}
+	}
+	if p := &paragraphs[len(paragraphs)-1]; collapse {
+		*p = trimRuns(*p)
+	}
+	return paragraphs
+}
+
+// breakLines breaks the spans into lines as wide as maxWidth at most,
+// if the white space handling wraps lines. Words wider than maxWidth
+// are broken between characters.
+func (s *textStyle) breakLines(spans []TextSpan, maxWidth float64) []textLine {
+	var lines []textLine
+	for _, p := range s.paragraphs(spans) {
+		if !s.whiteSpace.wraps() || math.IsInf(maxWidth, 1) {
+			lines = append(lines, textLine{runs: p, width: runsWidth(p)})
+			continue
+		}
+		lines = append(lines, wrapLine(p, maxWidth)...)
+	}
+	return lines
+}
+
+// wrapLine returns the lines of the paragraph wrapped at white spaces.
+// White spaces at the end of a line hang outside of it.
+func wrapLine(p []textRun, maxWidth float64) []textLine {
+	var lines []textLine
+	var line []textRun
+	flush := func() {
+		t := trimRuns(line)
+		lines = append(lines, textLine{runs: t, width: runsWidth(t)})
+		line = nil
+	}
+	for _, word := range splitWords(p) {
+		if next := joinRuns(line, word); runsWidth(trimRuns(next)) <= maxWidth {
+			line = next
+			continue
+		}
+		if len(line) > 0 {
+			flush()
+		}
+		// Break the word between characters if it doesn't fit in a line by itself.
+		for runsWidth(trimRuns(word)) > maxWidth {
+			line, word = fitRuns(word, maxWidth)
+			flush()
+		}
+		line = word
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		flush()
+	}
+	return lines
+}
+
+// splitWords splits the runs after each sequence of white spaces.
+func splitWords(runs []textRun) [][]textRun {
+	var words [][]textRun
+	var word []textRun
+	space := false
+	for _, r := range runs {
+		if r.icon != nil {
+			if space {
+				words, word = append(words, word), nil
+			}
+			word, space = append(word, r), false
+			continue
+		}
+		start := 0
+		for i, c := range r.text {
+			isSpace := unicode.IsSpace(c)
+			if space && !isSpace {
+				word = appendRun(word, textRun{text: r.text[start:i], font: r.font})
+				words, word = append(words, word), nil
+				start = i
+			}
+			space = isSpace
+		}
+		word = appendRun(word, textRun{text: r.text[start:], font: r.font})
+	}
+	if len(word) > 0 {
+		words = append(words, word)
+	}
+	return words
+}
+
+// fitRuns splits the runs after the longest prefix that fits in the width,
+// which has one character or icon at least.
+func fitRuns(runs []textRun, width float64) ([]textRun, []textRun) {
+	x := 0.0
+	for i, r := range runs {
+		if x+r.width <= width {
+			x += r.width
+			continue
+		}
+		if r.icon != nil {
+			if i == 0 {
+				return runs[:1], runs[1:]
+			}
+			return runs[:i], runs[i:]
+		}
+		n := r.font.fit(r.text, width-x, i == 0)
+		head := append([]textRun(nil), runs[:i]...)
+		head = appendRun(head, textRun{text: r.text[:n], font: r.font})
+		tail := appendRun(nil, textRun{text: r.text[n:], font: r.font})
+		return head, append(tail, runs[i+1:]...)
+	}
+	return runs, nil
+}
+
+// truncate cuts the line to the width, ending it with an ellipsis
+// in the font of the view.
+func (s *textStyle) truncate(l textLine, width float64) textLine {
+	if l.width <= width {
+		return l
+	}
+	font := s.fontStyle
+	ellipsis := textRun{text: s.ellipsis, font: &font, width: s.advance(s.ellipsis)}
+	runs := trimRuns(l.runs)
+	for len(runs) > 0 {
+		if last := &runs[len(runs)-1]; last.icon != nil {
+			runs = runs[:len(runs)-1]
+		} else {
+			_, size := utf8.DecodeLastRuneInString(last.text)
+			last.text = last.text[:len(last.text)-size]
+			last.width = last.font.advance(last.text)
+		}
+		runs = trimRuns(runs)
+		if w := runsWidth(runs) + ellipsis.width; w <= width {
+			return textLine{runs: append(runs, ellipsis), width: w}
+		}
+	}
+	return textLine{runs: []textRun{ellipsis}, width: ellipsis.width}
+}
+
+// textLayout caches the lines of the text of a view broken to a width.
+type textLayout struct {
+	spans []TextSpan
+	width float64
+	style textStyle
+	lines []textLine
+}
+
+func (c *textLayout) matches(spans []TextSpan, width float64, s textStyle) bool {
+	if c.lines == nil || c.width != width || c.style != s || len(c.spans) != len(spans) {
+		return false
+	}
+	for i := range spans {
+		if c.spans[i] != spans[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// textSpans returns the spans of the text of the view, which are the plain text
+// of the view if it has no spans.
+func (v *View) textSpans() []TextSpan {
+	if len(v.Attrs.Spans) > 0 {
+		return v.Attrs.Spans
+	}
+	return []TextSpan{{Text: v.Attrs.Text}}
+}
+
+// textLines returns the lines of the text of the view broken to the width.
+func (v *View) textLines(s textStyle, width float64) []textLine {
+	c := &v.textLayout
+	spans := v.textSpans()
+	if c.matches(spans, width, s) {
+		return c.lines
+	}
+	lines := s.breakLines(spans, width)
+	if s.overflow == TextOverflowEllipsis && !math.IsInf(width, 1) {
+		for i := range lines {
+			lines[i] = s.truncate(lines[i], width)
+		}
+	}
+	*c = textLayout{spans: append([]TextSpan(nil), spans...), width: width, style: s, lines: lines}
+	return lines
+}
+
+// hasText returns true if the text of the view is drawn and measured by furex,
+// which is when the view opts in with DrawText and its handler doesn't draw it.
+func (v *View) hasText() bool {
+	return v.Attrs.DrawText && (v.Attrs.Text != "" || len(v.Attrs.Spans) > 0) && v.Handler.Draw == nil
+}
+
+// isMeasured returns true if the content size of the view is measured,
+// by its handler or as text.
+func (v *View) isMeasured() bool {
+	return v.Handler.Measure != nil || (v.hasText() && len(v.children) == 0)
+}
+
+// measureText returns the size of the text of the view, wrapped to the available width.
+func (v *View) measureText(availableW, availableH int, mode MeasureMode) (int, int) {
+	s := v.textStyle()
+	maxWidth := math.Inf(1)
+	if mode != MeasureModeUndefined {
+		maxWidth = float64(availableW)
+	}
+	lines := v.textLines(s, maxWidth)
+	w := 0.0
+	for _, l := range lines {
+		w = math.Max(w, l.width)
+	}
+	return int(math.Ceil(w)), int(math.Ceil(s.lineHeight * float64(len(lines))))
+}
+
+// drawText draws the text of the view in its content box, in the opacity.
+func (v *View) drawText(screen *ebiten.Image, alpha float64) {
+	if screen == nil {
+		return
+	}
+	s := v.textStyle()
+	u := &v.used
+	content := image.Rect(
+		v.frame.Min.X+u.PaddingLeft, v.frame.Min.Y+u.PaddingTop,
+		v.frame.Max.X-u.PaddingRight, v.frame.Max.Y-u.PaddingBottom,
+	)
+	width := math.Inf(1)
+	if s.whiteSpace.wraps() || s.overflow == TextOverflowEllipsis {
+		width = float64(content.Dx())
+	}
+	lines := v.textLines(s, width)
+
+	height := s.lineHeight * float64(len(lines))
+	y := float64(content.Min.Y)
+	switch s.verticalAlign {
+	case VerticalAlignMiddle:
+		y += (float64(content.Dy()) - height) / 2
+	case VerticalAlignBottom:
+		y += float64(content.Dy()) - height
+	}
+	baseline := s.baseline()
+	for _, l := range lines {
+		x := float64(content.Min.X)
+		switch s.align {
+		case TextAlignCenter:
+			x += (float64(content.Dx()) - l.width) / 2
+		case TextAlignRight:
+			x += float64(content.Dx()) - l.width
+		}
+		for _, r := range l.runs {
+			if r.icon != nil {
+				r.font.drawIcon(screen, r.icon, x, y+baseline, alpha)
+			} else {
+				r.font.drawString(screen, r.text, x, y+baseline, fade(r.font.color, alpha))
+			}
+			x += r.width
+		}
+		y += s.lineHeight
+	}
+}
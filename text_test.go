@@ -0,0 +1,323 @@
+package furex
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lineTexts(lines []textLine) []string {
+	var texts []string
+	for _, l := range lines {
+		texts = append(texts, l.text())
+	}
+	return texts
+}
+
+func TestBreakLines(t *testing.T) {
+	// The characters of the default font are 7 pixels wide.
+	tests := []struct {
+		name       string
+		whiteSpace FlexWhiteSpace
+		text       string
+		width      float64
+		want       []string
+	}{
+		{
+			name:       "wrap at spaces",
+			whiteSpace: WhiteSpaceNormal,
+			text:       "the quick  brown\nfox jumps",
+			width:      70,
+			want:       []string{"the quick", "brown fox", "jumps"},
+		},
+		{
+			name:       "break long words",
+			whiteSpace: WhiteSpaceNormal,
+			text:       "a abcdefghijklmn",
+			width:      35,
+			want:       []string{"a", "abcde", "fghij", "klmn"},
+		},
+		{
+			name:       "no wrap",
+			whiteSpace: WhiteSpaceNoWrap,
+			text:       "the quick  brown\nfox",
+			width:      35,
+			want:       []string{"the quick brown fox"},
+		},
+		{
+			name:       "pre",
+			whiteSpace: WhiteSpacePre,
+			text:       "the  quick\n brown fox",
+			width:      35,
+			want:       []string{"the  quick", " brown fox"},
+		},
+		{
+			name:       "pre-wrap",
+			whiteSpace: WhiteSpacePreWrap,
+			text:       "the  quick\n brown fox",
+			width:      70,
+			want:       []string{"the  quick", " brown fox"},
+		},
+		{
+			name:       "pre-line",
+			whiteSpace: WhiteSpacePreLine,
+			text:       "the  quick brown\n fox",
+			width:      70,
+			want:       []string{"the quick", "brown", "fox"},
+		},
+		{
+			name:       "pre-wrap empty lines",
+			whiteSpace: WhiteSpacePreWrap,
+			text:       "the\n\nfox",
+			width:      70,
+			want:       []string{"the", "", "fox"},
+		},
+		{
+			name:       "empty",
+			whiteSpace: WhiteSpaceNormal,
+			text:       "",
+			width:      70,
+			want:       []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &View{Attrs: ViewAttrs{WhiteSpace: tt.whiteSpace}}
+			s := v.textStyle()
+			lines := s.breakLines([]TextSpan{{Text: tt.text}}, tt.width)
+			require.Equal(t, tt.want, lineTexts(lines))
+			for _, l := range lines {
+				require.Equal(t, float64(7*len(l.text())), l.width)
+			}
+		})
+	}
+}
+
+func TestTextEllipsis(t *testing.T) {
+	v := &View{Attrs: ViewAttrs{
+		Text:         "hello world",
+		WhiteSpace:   WhiteSpaceNoWrap,
+		TextOverflow: TextOverflowEllipsis,
+	}}
+	s := v.textStyle()
+
+	require.Equal(t, []string{"hell..."}, lineTexts(v.textLines(s, 50)))
+	require.Equal(t, []string{"hello..."}, lineTexts(v.textLines(s, 60)))
+	require.Equal(t, []string{"hello world"}, lineTexts(v.textLines(s, 77)))
+	require.Equal(t, []string{"..."}, lineTexts(v.textLines(s, 10)))
+}
+
+func TestTextStyleInheritance(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	label := &View{Attrs: ViewAttrs{Text: "label", TextOverflow: TextOverflowEllipsis}}
+	parent := &View{Attrs: ViewAttrs{
+		FontSize:      Px(26),
+		VerticalAlign: VerticalAlignBottom,
+		TextOverflow:  TextOverflowClip,
+	}}
+	root := &View{Attrs: ViewAttrs{
+		Color:      red,
+		FontSize:   Px(13),
+		LineHeight: Length{Value: 1.5, Unit: LengthEm},
+		TextAlign:  TextAlignCenter,
+		WhiteSpace: WhiteSpacePre,
+	}}
+	root.AddChild(parent.AddChild(label))
+
+	s := label.textStyle()
+	assert.Equal(t, color.Color(red), s.color)
+	assert.Equal(t, 2.0, s.scale)
+	assert.Equal(t, 39.0, s.lineHeight)
+	assert.Equal(t, TextAlignCenter, s.align)
+	assert.Equal(t, WhiteSpacePre, s.whiteSpace)
+	// Vertical align and text overflow are not inherited.
+	assert.Equal(t, VerticalAlignTop, s.verticalAlign)
+	assert.Equal(t, TextOverflowEllipsis, s.overflow)
+
+	s = (*View)(nil).textStyle()
+	assert.Equal(t, defaultColor, s.color)
+	assert.Equal(t, 1.0, s.scale)
+	assert.Equal(t, 13.0, s.lineHeight)
+	assert.Equal(t, TextAlignLeft, s.align)
+	assert.Equal(t, WhiteSpaceNormal, s.whiteSpace)
+}
+
+func TestRelativeFontSize(t *testing.T) {
+	view, err := ParseE(`
+		<view style="width: 200px; height: 100px; font-size: 20px; align-items: flex-start;">
+			<view id="em" style="font-size: 1.5em; width: 2em; height: 1rem;">
+				<view id="pct" style="font-size: 50%; width: 2em; height: 10px;"></view>
+			</view>
+			<view id="rem" style="font-size: 2rem; width: 1em; height: 10px;"></view>
+		</view>`, nil)
+	require.NoError(t, err)
+	view.Update()
+
+	// Em and percent font sizes are relative to the font size of the parent,
+	// em lengths to the font size of the view and rem lengths to that of the root.
+	em, pct, rem := view.MustGetByID("em"), view.MustGetByID("pct"), view.MustGetByID("rem")
+	assert.Equal(t, 30.0, em.textStyle().size)
+	assert.Equal(t, image.Rect(0, 0, 60, 20), em.frame)
+	assert.Equal(t, 15.0, pct.textStyle().size)
+	assert.Equal(t, image.Rect(0, 0, 30, 10), pct.frame)
+	assert.Equal(t, 40.0, rem.textStyle().size)
+	assert.Equal(t, image.Rect(60, 0, 100, 10), rem.frame)
+
+	// A change of the font size of the parent changes the em lengths of the children.
+	em.SetFontSize(Px(10))
+	view.Update()
+	assert.Equal(t, 5.0, pct.textStyle().size)
+	assert.Equal(t, image.Rect(0, 0, 10, 10), pct.frame)
+}
+
+func TestTextMeasure(t *testing.T) {
+	label := &View{Attrs: ViewAttrs{Text: "the quick brown fox", DrawText: true, PaddingLeft: Px(5), PaddingTop: Px(2)}}
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100), Direction: Column, AlignItems: AlignItemStart}}
+	root.AddChild(label)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 5+19*7, 2+13), label.frame)
+
+	// The text wraps in a narrower view.
+	label.SetWidth(75)
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 75, 2+2*13), label.frame)
+
+	label.SetText("the quick brown fox jumps")
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 75, 2+3*13), label.frame)
+
+	// Views drawing their text themselves are not measured.
+	handled := &View{Attrs: ViewAttrs{Text: "the quick brown fox", DrawText: true}}
+	handled.Handler.Draw = func(screen *ebiten.Image, frame image.Rectangle, v *View) {}
+	root.AddChild(handled)
+	root.Update()
+	require.Equal(t, image.Rect(0, 41, 0, 41), handled.frame)
+
+	// Nor are views which don't opt in to drawing their text.
+	plain := &View{Attrs: ViewAttrs{Text: "the quick brown fox"}}
+	root.AddChild(plain)
+	root.Update()
+	require.Equal(t, image.Rect(0, 41, 0, 41), plain.frame)
+
+	plain.SetDrawText(true)
+	root.Update()
+	require.Equal(t, image.Rect(0, 41, 19*7, 41+13), plain.frame)
+}
+
+func TestTextDraw(t *testing.T) {
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), Direction: Column}}
+	label := &View{Attrs: ViewAttrs{
+		Height:        Px(50),
+		Text:          "ab",
+		DrawText:      true,
+		TextAlign:     TextAlignRight,
+		VerticalAlign: VerticalAlignMiddle,
+		Color:         color.RGBA{0, 0xff, 0, 0xff},
+	}}
+	root.AddChild(label)
+	screen := ebiten.NewImage(100, 100)
+	root.Update()
+	root.Draw(screen)
+
+	require.Equal(t, []string{"ab"}, lineTexts(label.textLayout.lines))
+	require.Equal(t, image.Rect(0, 0, 100, 50), label.frame)
+}
+
+func TestRegisterFont(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	require.NoError(t, err)
+	RegisterFont("mplus", GoTextFont(src))
+	defer delete(fontFaces, fontKey{family: "mplus"})
+
+	v := &View{Attrs: ViewAttrs{FontFamily: "mplus", FontSize: Px(20)}}
+	s := v.textStyle()
+	face, ok := s.face.(*text.GoTextFace)
+	require.True(t, ok)
+	require.Equal(t, 20.0, face.Size)
+	require.Equal(t, 1.0, s.scale)
+	require.Equal(t, "…", s.ellipsis)
+	// The faces are cached by size.
+	require.Same(t, s.face, v.textStyle().face)
+
+	// Styles without a registered face are synthesized from the nearest one.
+	bold := func(size float64) text.Face { return GoTextFont(src)(size) }
+	RegisterFontStyle("mplus", FontStyleBold, bold)
+	defer delete(fontFaces, fontKey{family: "mplus", style: FontStyleBold})
+	s = v.textStyle()
+	f := s.spanFont(TextSpan{Bold: true, Italic: true})
+	require.NotSame(t, s.face, f.face)
+	require.Equal(t, FontStyleItalic, f.synthetic)
+	f = s.spanFont(TextSpan{Italic: true})
+	require.Same(t, s.face, f.face)
+	require.Equal(t, FontStyleItalic, f.synthetic)
+
+	// Unknown font families fall back to the default font.
+	v.SetFontFamily("unknown")
+	require.Equal(t, defaultFontFace, v.textStyle().face)
+}
+
+func TestRichText(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	icon := ebiten.NewImage(14, 26)
+	RegisterImage("coin", icon)
+	defer delete(images, "coin")
+
+	v := &View{Attrs: ViewAttrs{DrawText: true}}
+	v.SetSpans(
+		TextSpan{Text: "Deal "},
+		TextSpan{Text: "20", Bold: true, Color: red},
+		TextSpan{Text: " damage "},
+		TextSpan{Icon: "coin"},
+		TextSpan{Text: "x2", Italic: true, Break: true},
+		TextSpan{Text: "  to all  "},
+	)
+	require.Equal(t, "Deal 20 damage x2\n  to all  ", v.Attrs.Text)
+	s := v.textStyle()
+
+	lines := v.textLines(s, math.Inf(1))
+	require.Equal(t, []string{"Deal 20 damage x2", "to all"}, lineTexts(lines))
+	runs := lines[0].runs
+	require.Len(t, runs, 5)
+	require.Equal(t, "20", runs[1].text)
+	require.Equal(t, color.Color(red), runs[1].font.color)
+	require.Equal(t, FontStyleBold, runs[1].font.synthetic)
+	require.Equal(t, defaultColor, runs[2].font.color)
+	// The icon is as high as the font.
+	require.Equal(t, icon, runs[3].icon)
+	require.Equal(t, 7.0, runs[3].width)
+	require.Equal(t, FontStyleItalic, runs[4].font.synthetic)
+	require.Equal(t, float64(7*18), lines[0].width)
+
+	// Lines are broken across the spans.
+	lines = v.textLines(s, 70)
+	require.Equal(t, []string{"Deal 20", "damage x2", "to all"}, lineTexts(lines))
+	lines = v.textLines(s, 30)
+	require.Equal(t, []string{"Deal", "20", "dama", "ge", "x2", "to", "all"}, lineTexts(lines))
+
+	v.SetTextOverflow(TextOverflowEllipsis)
+	v.SetWhiteSpace(WhiteSpaceNoWrap)
+	s = v.textStyle()
+	lines = v.textLines(s, 70)
+	require.Equal(t, []string{"Deal 20...", "to all"}, lineTexts(lines))
+	lines = v.textLines(s, 119)
+	require.Equal(t, []string{"Deal 20 damage...", "to all"}, lineTexts(lines))
+	require.Len(t, lines[0].runs, 4)
+
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100)}}
+	root.AddChild(v)
+	root.Update()
+	root.Draw(ebiten.NewImage(200, 100))
+
+	// Setting the text removes the spans.
+	v.SetText("plain")
+	require.Nil(t, v.Attrs.Spans)
+	require.Equal(t, []string{"plain"}, lineTexts(v.textLines(v.textStyle(), 100)))
+}
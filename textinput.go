@@ -0,0 +1,433 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/internal/graphic"
+)
+
+// textInputInset is the space between the frame of a text input and its text.
+const textInputInset = 4
+
+// textInputSize is the width of a text input in characters when it has no width.
+const textInputSize = 20
+
+// caretBlinkTicks is the ticks the caret is shown and then hidden while blinking.
+const caretBlinkTicks = 30
+
+// TextInput is a handler of a single line text field. The view must be focusable,
+// characters are typed into the field while it is focused. The text is drawn
+// in the font, font size and color of the view.
+//
+// The caret is moved with the arrow keys, Home and End, and by clicking.
+// Holding Shift down selects text. Ctrl (or Cmd) with A, C, X and V selects all,
+// copies, cuts and pastes, the copied text is kept by the field itself.
+type TextInput struct {
+	// Value is the text in the field.
+	Value string
+	// Placeholder is the text shown while the field is empty.
+	Placeholder string
+	// MaxLength is the maximum number of characters of the value, 0 means no limit.
+	MaxLength int
+	// OnChange is called with the new value when the user changes it.
+	OnChange func(value string)
+	// OnSubmit is called with the value when Enter is pressed in the field.
+	OnSubmit func(value string)
+
+	// Color is the color of the text and the caret, the text color of the view if nil.
+	Color color.Color
+	// PlaceholderColor is the color of the placeholder, gray if nil.
+	PlaceholderColor color.Color
+	// SelectionColor is the color of the selection, translucent blue if nil.
+	SelectionColor color.Color
+	// BorderColor is the color of the border, no border is drawn if nil.
+	BorderColor color.Color
+
+	// caret and anchor are the positions in runes of the caret and of the other end of the selection.
+	caret, anchor int
+	// scrollX is how far the value is scrolled when it doesn't fit in the field.
+	scrollX   float64
+	clipboard string
+	focused   bool
+	blink     int
+	chars     []rune
+	// view is the view of the field, whose text style the value is drawn in.
+	view *View
+}
+
+// NewTextInputView returns a focusable view with a TextInput handler.
+// The handler can be got from the view by v.Handler.Extra.(*TextInput).
+func NewTextInputView(t *TextInput, opts ...ViewOption) *View {
+	t.view = NewView(append([]ViewOption{Focusable(true), Handler(t)}, opts...)...)
+	return t.view
+}
+
+// Handler implements HandlerProvider.
+func (t *TextInput) Handler() ViewHandler {
+	return ViewHandler{
+		Extra:  t,
+		Update: t.HandleUpdate,
+		Draw:   t.HandleDraw,
+		Key:    t.HandleKey,
+		Focus:  t.HandleFocus,
+		Blur:   t.HandleBlur,
+		JustPressedMouseButtonLeft: func(frame image.Rectangle, x, y int) bool {
+			t.moveCaretTo(frame, x)
+			return true
+		},
+		JustReleasedMouseButtonLeft: func(frame image.Rectangle, x, y int) {},
+		Measure:                     t.HandleMeasure,
+	}
+}
+
+// SetValue replaces the value and moves the caret to its end.
+// OnChange is not called.
+func (t *TextInput) SetValue(value string) {
+	t.Value = value
+	t.caret = len([]rune(value))
+	t.anchor = t.caret
+	t.invalidate()
+}
+
+// Selection returns the start and end of the selected characters.
+// They are the same if nothing is selected.
+func (t *TextInput) Selection() (start, end int) {
+	t.clampCaret()
+	if t.anchor < t.caret {
+		return t.anchor, t.caret
+	}
+	return t.caret, t.anchor
+}
+
+// Select selects the characters from start to end, and moves the caret to end.
+func (t *TextInput) Select(start, end int) {
+	t.anchor, t.caret = start, end
+	t.clampCaret()
+	t.invalidate()
+}
+
+// SelectedText returns the selected text.
+func (t *TextInput) SelectedText() string {
+	start, end := t.Selection()
+	return string([]rune(t.Value)[start:end])
+}
+
+// clampCaret keeps the caret and the anchor in the value,
+// as the value can be changed from outside.
+func (t *TextInput) clampCaret() {
+	n := len([]rune(t.Value))
+	t.caret = clampInt(t.caret, 0, n)
+	t.anchor = clampInt(t.anchor, 0, n)
+}
+
+func clampInt(i, min, max int) int {
+	if i < min {
+		return min
+	}
+	if i > max {
+		return max
+	}
+	return i
+}
+
+// HandleFocus implements FocusHandler.
+func (t *TextInput) HandleFocus() {
+	t.focused = true
+	t.blink = 0
+}
+
+// HandleBlur implements FocusHandler.
+func (t *TextInput) HandleBlur() {
+	t.focused = false
+	t.anchor = t.caret
+	t.invalidate()
+}
+
+// setView sets the view of the input. The input is measured in the default
+// text style until its view is known, so the view is laid out again then.
+func (t *TextInput) setView(v *View) {
+	if t.view != v {
+		t.view = v
+		v.Layout()
+	}
+}
+
+// invalidate invalidates the render caches the input is drawn on.
+func (t *TextInput) invalidate() {
+	if t.view != nil {
+		t.view.Invalidate()
+	}
+}
+
+// HandleUpdate implements Updater. It inserts the characters typed in this tick.
+func (t *TextInput) HandleUpdate(v *View) {
+	t.setView(v)
+	if !t.focused {
+		return
+	}
+	// The caret blinks and the value changes while the input is focused.
+	v.Invalidate()
+	t.blink++
+	t.chars = ebiten.AppendInputChars(t.chars[:0])
+	if len(t.chars) > 0 {
+		t.insert(string(t.chars))
+	}
+}
+
+// insert replaces the selection with s, cut to the max length.
+// Control characters are dropped.
+func (t *TextInput) insert(s string) {
+	start, end := t.Selection()
+	value := []rune(t.Value)
+	var rs []rune
+	for _, r := range s {
+		if unicode.IsPrint(r) {
+			rs = append(rs, r)
+		}
+	}
+	if t.MaxLength > 0 {
+		room := t.MaxLength - (len(value) - (end - start))
+		if room < 0 {
+			room = 0
+		}
+		if len(rs) > room {
+			rs = rs[:room]
+		}
+	}
+	if len(rs) == 0 && start == end {
+		return
+	}
+	t.replace(start, end, rs)
+}
+
+// replace replaces the characters from start to end with rs,
+// moves the caret after them and calls OnChange.
+func (t *TextInput) replace(start, end int, rs []rune) {
+	value := []rune(t.Value)
+	next := make([]rune, 0, len(value)-(end-start)+len(rs))
+	next = append(next, value[:start]...)
+	next = append(next, rs...)
+	next = append(next, value[end:]...)
+	t.Value = string(next)
+	t.caret = start + len(rs)
+	t.anchor = t.caret
+	t.blink = 0
+	if t.OnChange != nil {
+		t.OnChange(t.Value)
+	}
+}
+
+// HandleKey implements KeyHandler.
+func (t *TextInput) HandleKey(e KeyEvent) bool {
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	return t.key(e, shift, ctrl)
+}
+
+// key edits the value by the key event. Shift extends the selection and ctrl
+// makes the shortcuts. The field takes all the keys but Tab and Escape,
+// so that typing doesn't trigger the key handlers of its ancestors.
+func (t *TextInput) key(e KeyEvent, shift, ctrl bool) bool {
+	if e.Released {
+		return false
+	}
+	t.clampCaret()
+	n := len([]rune(t.Value))
+	start, end := t.Selection()
+	switch e.Key {
+	case ebiten.KeyArrowLeft:
+		if start != end && !shift {
+			t.moveCaret(start, false)
+		} else {
+			t.moveCaret(t.caret-1, shift)
+		}
+	case ebiten.KeyArrowRight:
+		if start != end && !shift {
+			t.moveCaret(end, false)
+		} else {
+			t.moveCaret(t.caret+1, shift)
+		}
+	case ebiten.KeyHome, ebiten.KeyArrowUp:
+		t.moveCaret(0, shift)
+	case ebiten.KeyEnd, ebiten.KeyArrowDown:
+		t.moveCaret(n, shift)
+	case ebiten.KeyBackspace:
+		if start == end && start > 0 {
+			start--
+		}
+		if start != end {
+			t.replace(start, end, nil)
+		}
+	case ebiten.KeyDelete:
+		if start == end && end < n {
+			end++
+		}
+		if start != end {
+			t.replace(start, end, nil)
+		}
+	case ebiten.KeyEnter, ebiten.KeyNumpadEnter:
+		if t.OnSubmit != nil {
+			t.OnSubmit(t.Value)
+		}
+	case ebiten.KeyA, ebiten.KeyC, ebiten.KeyX, ebiten.KeyV:
+		if !ctrl {
+			// The character is typed by input chars.
+			return true
+		}
+		switch e.Key {
+		case ebiten.KeyA:
+			t.Select(0, n)
+		case ebiten.KeyC:
+			if start != end {
+				t.clipboard = t.SelectedText()
+			}
+		case ebiten.KeyX:
+			if start != end {
+				t.clipboard = t.SelectedText()
+				t.replace(start, end, nil)
+			}
+		case ebiten.KeyV:
+			t.insert(t.clipboard)
+		}
+	default:
+		return e.Key != ebiten.KeyTab && e.Key != ebiten.KeyEscape
+	}
+	return true
+}
+
+// moveCaret moves the caret to i. The selection is extended if extend is true,
+// otherwise it is cleared.
+func (t *TextInput) moveCaret(i int, extend bool) {
+	t.caret = clampInt(i, 0, len([]rune(t.Value)))
+	if !extend {
+		t.anchor = t.caret
+	}
+	t.blink = 0
+}
+
+// moveCaretTo moves the caret to the character boundary nearest to x.
+// The frame and x are scaled by GlobalScale, the advances of the text are not.
+func (t *TextInput) moveCaretTo(frame image.Rectangle, x int) {
+	s := t.view.textStyle()
+	target := float64(x-frame.Min.X)/GlobalScale - textInputInset + t.scrollX
+	value := []rune(t.Value)
+	caret, dist := 0, math.Inf(1)
+	for i := 0; i <= len(value); i++ {
+		d := math.Abs(s.advance(string(value[:i])) - target)
+		if d >= dist {
+			break
+		}
+		caret, dist = i, d
+	}
+	t.moveCaret(caret, false)
+}
+
+// HandleMeasure implements Measurer.
+func (t *TextInput) HandleMeasure(availableW, availableH int, mode MeasureMode) (int, int) {
+	s := t.view.textStyle()
+	w := int(math.Ceil(s.advance("0")*textInputSize)) + textInputInset*2
+	if mode == MeasureModeExactly {
+		w = availableW
+	}
+	return w, int(math.Ceil(s.lineHeight)) + textInputInset*2
+}
+
+// scrollToCaret scrolls the value so that the caret is in the field of the width.
+func (t *TextInput) scrollToCaret(s textStyle, value []rune, width float64) {
+	caretX := s.advance(string(value[:t.caret]))
+	if caretX < t.scrollX {
+		t.scrollX = caretX
+	}
+	if caretX > t.scrollX+width {
+		t.scrollX = caretX - width
+	}
+	// Don't leave space after the value when it gets shorter.
+	t.scrollX = clamp(t.scrollX, 0, math.Max(0, s.advance(string(value))-width))
+}
+
+// HandleDraw implements Drawer.
+// The frame is scaled by GlobalScale, the value is laid out unscaled
+// and drawn scaled.
+func (t *TextInput) HandleDraw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	t.setView(v)
+	t.clampCaret()
+	s := v.textStyle()
+	value := []rune(t.Value)
+	inner := frame.Inset(int(math.Round(textInputInset * GlobalScale)))
+	left, top := float64(inner.Min.X)/GlobalScale, float64(inner.Min.Y)/GlobalScale
+	width, height := float64(inner.Dx())/GlobalScale, float64(inner.Dy())/GlobalScale
+	t.scrollToCaret(s, value, width)
+	if screen == nil {
+		return
+	}
+	if t.BorderColor != nil {
+		graphic.DrawRect(screen, &graphic.DrawRectOpts{
+			Rect:        frame,
+			Color:       t.BorderColor,
+			StrokeWidth: 1,
+		})
+	}
+	clipped := screen.SubImage(inner).(*ebiten.Image)
+	x := left - t.scrollX
+	y := top + (height-s.lineHeight)/2
+	lineRect := func(x0, x1 float64) image.Rectangle {
+		return scaleFrame(image.Rect(int(x0), int(y), int(x1), int(math.Ceil(y+s.lineHeight))))
+	}
+	textColor := colorOr(t.Color, s.color)
+
+	if len(value) == 0 {
+		if t.Placeholder != "" {
+			s.draw(clipped, t.Placeholder, x, y, colorOr(t.PlaceholderColor, color.Gray{0x80}))
+		}
+	} else {
+		if start, end := t.Selection(); start != end {
+			graphic.FillRect(clipped, &graphic.FillRectOpts{
+				Rect: lineRect(
+					x+s.advance(string(value[:start])),
+					x+s.advance(string(value[:end])),
+				),
+				Color: colorOr(t.SelectionColor, color.RGBA{0x30, 0x60, 0xc0, 0x80}),
+			})
+		}
+		s.draw(clipped, t.Value, x, y, textColor)
+	}
+
+	if t.focused && (t.blink/caretBlinkTicks)%2 == 0 {
+		// The caret at the end of a scrolled value is kept inside the field.
+		cx := math.Min(x+s.advance(string(value[:t.caret])), left+width-1)
+		graphic.FillRect(clipped, &graphic.FillRectOpts{
+			Rect:  lineRect(cx, cx+1),
+			Color: textColor,
+		})
+	}
+}
+
+func colorOr(c, def color.Color) color.Color {
+	if c == nil {
+		return def
+	}
+	return c
+}
+
+// setAttrs sets the fields from the attributes of an <input> element.
+func (t *TextInput) setAttrs(attrs map[string]string) {
+	if p, ok := attrs["placeholder"]; ok {
+		t.Placeholder = p
+	}
+	if val, ok := attrs["value"]; ok {
+		t.SetValue(val)
+	}
+	if l, err := strconv.Atoi(attrs["maxlength"]); err == nil && l > 0 {
+		t.MaxLength = l
+		if r := []rune(t.Value); len(r) > l {
+			t.SetValue(string(r[:l]))
+		}
+	}
+}
+
+var _ HandlerProvider = (*TextInput)(nil)
@@ -0,0 +1,216 @@
+package furex
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextInputEditing(t *testing.T) {
+	var changes []string
+	input := &TextInput{
+		OnChange: func(value string) { changes = append(changes, value) },
+	}
+	key := func(k ebiten.Key, shift bool) {
+		require.True(t, input.key(KeyEvent{Key: k}, shift, false))
+	}
+
+	input.insert("héllo")
+	require.Equal(t, "héllo", input.Value)
+
+	key(ebiten.KeyArrowLeft, false)
+	key(ebiten.KeyArrowLeft, false)
+	key(ebiten.KeyBackspace, false)
+	require.Equal(t, "hélo", input.Value)
+	key(ebiten.KeyDelete, false)
+	require.Equal(t, "héo", input.Value)
+
+	key(ebiten.KeyHome, false)
+	input.insert("\tO")
+	require.Equal(t, "Ohéo", input.Value)
+
+	// Backspace and Delete do nothing at the ends.
+	key(ebiten.KeyEnd, false)
+	key(ebiten.KeyDelete, false)
+	key(ebiten.KeyHome, false)
+	key(ebiten.KeyBackspace, false)
+	require.Equal(t, []string{"héllo", "hélo", "héo", "Ohéo"}, changes)
+
+	// Tab and Escape are left to the ancestors.
+	require.False(t, input.key(KeyEvent{Key: ebiten.KeyTab}, false, false))
+	require.False(t, input.key(KeyEvent{Key: ebiten.KeyEscape}, false, false))
+}
+
+func TestTextInputSelection(t *testing.T) {
+	input := &TextInput{}
+	input.SetValue("hello world")
+
+	input.key(KeyEvent{Key: ebiten.KeyArrowLeft}, true, false)
+	input.key(KeyEvent{Key: ebiten.KeyArrowLeft}, true, false)
+	require.Equal(t, "ld", input.SelectedText())
+
+	input.insert("LD!")
+	require.Equal(t, "hello worLD!", input.Value)
+
+	input.key(KeyEvent{Key: ebiten.KeyHome}, true, false)
+	start, end := input.Selection()
+	require.Equal(t, 0, start)
+	require.Equal(t, 12, end)
+
+	// Moving the caret without Shift collapses the selection to its edge.
+	input.key(KeyEvent{Key: ebiten.KeyArrowRight}, false, false)
+	start, end = input.Selection()
+	require.Equal(t, 12, start)
+	require.Equal(t, 12, end)
+
+	input.Select(0, 5)
+	input.key(KeyEvent{Key: ebiten.KeyBackspace}, false, false)
+	require.Equal(t, " worLD!", input.Value)
+
+	// The selection is kept in the value when the value is changed from outside.
+	input.Select(3, 7)
+	input.Value = "abc"
+	require.Equal(t, "", input.SelectedText())
+}
+
+func TestTextInputCopyPaste(t *testing.T) {
+	input := &TextInput{}
+	input.SetValue("copy me")
+
+	input.key(KeyEvent{Key: ebiten.KeyA}, false, true)
+	require.Equal(t, "copy me", input.SelectedText())
+	input.key(KeyEvent{Key: ebiten.KeyC}, false, true)
+	input.key(KeyEvent{Key: ebiten.KeyEnd}, false, false)
+	input.key(KeyEvent{Key: ebiten.KeyV}, false, true)
+	require.Equal(t, "copy mecopy me", input.Value)
+
+	input.Select(0, 5)
+	input.key(KeyEvent{Key: ebiten.KeyX}, false, true)
+	require.Equal(t, "mecopy me", input.Value)
+	input.key(KeyEvent{Key: ebiten.KeyEnd}, false, false)
+	input.key(KeyEvent{Key: ebiten.KeyV}, false, true)
+	require.Equal(t, "mecopy mecopy ", input.Value)
+
+	// Without Ctrl, the letter keys only type characters.
+	input.key(KeyEvent{Key: ebiten.KeyV}, false, false)
+	require.Equal(t, "mecopy mecopy ", input.Value)
+}
+
+func TestTextInputMaxLength(t *testing.T) {
+	input := &TextInput{MaxLength: 5}
+
+	input.insert("abcdefg")
+	require.Equal(t, "abcde", input.Value)
+	input.insert("x")
+	require.Equal(t, "abcde", input.Value)
+
+	// Replacing the selection makes room for the characters.
+	input.Select(1, 3)
+	input.insert("xyz")
+	require.Equal(t, "axyde", input.Value)
+}
+
+func TestTextInputSubmit(t *testing.T) {
+	var submitted string
+	input := &TextInput{OnSubmit: func(value string) { submitted = value }}
+	view := NewTextInputView(input, Width(100), Height(24))
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(view)
+
+	require.True(t, view.Focus())
+	require.True(t, input.focused)
+	input.insert("name")
+	root.dispatchKey(KeyEvent{Key: ebiten.KeyEnter}, false)
+	require.Equal(t, "name", submitted)
+
+	view.Blur()
+	require.False(t, input.focused)
+}
+
+func TestTextInputClick(t *testing.T) {
+	input := &TextInput{}
+	input.SetValue("abcdef")
+	view := NewTextInputView(input, Width(100), Height(24))
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(view)
+	root.Update()
+	root.Draw(nil)
+
+	// The caret moves to the nearest boundary between the characters,
+	// which are 7 pixels wide in the default font.
+	root.handleMouseButtonLeftPressed(nil, textInputInset+2*7+2, 10)
+	start, end := input.Selection()
+	require.Equal(t, 2, start)
+	require.Equal(t, 2, end)
+
+	root.handleMouseButtonLeftPressed(nil, 90, 10)
+	start, _ = input.Selection()
+	require.Equal(t, 6, start)
+}
+
+func TestTextInputGlobalScale(t *testing.T) {
+	GlobalScale = 2
+	defer func() { GlobalScale = 1 }()
+	input := &TextInput{}
+	input.SetValue("abcdefghijklmnopqrstuvwxyz0123")
+	view := NewTextInputView(input, Width(100), Height(24))
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(view)
+	root.Update()
+	view.Focus()
+	screen := ebiten.NewImage(400, 200)
+
+	// The value is scrolled in unscaled pixels.
+	root.Draw(screen)
+	require.Equal(t, float64(30*7-92), input.scrollX)
+
+	input.key(KeyEvent{Key: ebiten.KeyHome}, false, false)
+	root.Draw(screen)
+	root.handleMouseButtonLeftPressed(nil, (textInputInset+2*7+2)*2, 20)
+	start, _ := input.Selection()
+	require.Equal(t, 2, start)
+}
+
+func TestTextInputMeasure(t *testing.T) {
+	view := NewTextInputView(&TextInput{})
+	root := &View{Attrs: ViewAttrs{Width: Px(400), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(view)
+	root.Update()
+
+	require.Equal(t, textInputSize*7+2*textInputInset, view.frame.Dx())
+	require.Equal(t, 13+2*textInputInset, view.frame.Dy())
+
+	// The field is sized to its font.
+	view.SetFontSize(Px(26))
+	root.Update()
+	require.Equal(t, textInputSize*14+2*textInputInset, view.frame.Dx())
+	require.Equal(t, 26+2*textInputInset, view.frame.Dy())
+
+	// An input given as the handler of a view is measured in the font of the view
+	// once it is updated.
+	view = NewView(Handler(&TextInput{}), FontSize(Px(26)))
+	root.AddChild(view)
+	root.Update()
+	root.Update()
+	require.Equal(t, textInputSize*14+2*textInputInset, view.frame.Dx())
+}
+
+func TestTextInputScroll(t *testing.T) {
+	input := &TextInput{}
+	input.SetValue("abcdefghijklmnopqrstuvwxyz0123")
+	view := NewTextInputView(input, Width(100), Height(24))
+	root := &View{Attrs: ViewAttrs{Width: Px(200), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(view)
+	root.Update()
+	view.Focus()
+	screen := ebiten.NewImage(200, 100)
+
+	// The value is scrolled to the caret at the end.
+	root.Draw(screen)
+	require.Equal(t, float64(30*7-92), input.scrollX)
+
+	input.key(KeyEvent{Key: ebiten.KeyHome}, false, false)
+	root.Draw(screen)
+	require.Zero(t, input.scrollX)
+}
@@ -0,0 +1,136 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// FlexTransform is a function of the 'transform' property.
+type FlexTransform uint8
+
+const (
+	// TransformTranslate moves the view by X and Y pixels.
+	TransformTranslate FlexTransform = iota
+	// TransformScale scales the view by X horizontally and Y vertically.
+	TransformScale
+	// TransformRotate rotates the view clockwise by X radians.
+	TransformRotate
+)
+
+func (t FlexTransform) String() string {
+	switch t {
+	case TransformTranslate:
+		return "translate"
+	case TransformScale:
+		return "scale"
+	case TransformRotate:
+		return "rotate"
+	}
+	return fmt.Sprintf("unknown transform: %d", t)
+}
+
+// TransformFunc is a function of the 'transform' property of a view.
+type TransformFunc struct {
+	Func FlexTransform
+	X, Y float64
+}
+
+// GeoM returns the matrix the view is drawn with on the screen, composed of the
+// transforms and the scales of the view and its ancestors. Draw handlers of
+// transformed views draw in their frame on an offscreen image, which is drawn
+// on the screen with the matrix.
+func (v *View) GeoM() ebiten.GeoM {
+	m, _ := v.transform()
+	return m
+}
+
+// transform returns the matrix the view is drawn with.
+// It returns false if neither the view nor its ancestors are transformed.
+// The transform of a cached view is applied when its cache is drawn, not on it.
+func (v *View) transform() (ebiten.GeoM, bool) {
+	var m ebiten.GeoM
+	transformed := false
+	for p := v; p != nil && !p.cache.rendering; p = p.parent {
+		if own, ok := p.ownTransform(); ok {
+			m.Concat(own)
+			transformed = true
+		}
+	}
+	return m, transformed
+}
+
+// ownTransform returns the matrix of the transform and the scale of the view
+// around its transform origin, not including its ancestors.
+func (v *View) ownTransform() (ebiten.GeoM, bool) {
+	a := &v.Attrs
+	scaled := a.Scale != nil && *a.Scale != 1
+	if !scaled && len(a.Transform) == 0 {
+		return ebiten.GeoM{}, false
+	}
+	frame := scaleFrame(v.frame)
+	ctx := v.lengthContext()
+	origin := func(l Length, ref float64) float64 {
+		if l.IsAuto() {
+			return ref / 2 * GlobalScale
+		}
+		n, _ := l.resolve(ref, ctx)
+		return n * GlobalScale
+	}
+	ox := float64(frame.Min.X) + origin(a.TransformOriginX, float64(v.frame.Dx()))
+	oy := float64(frame.Min.Y) + origin(a.TransformOriginY, float64(v.frame.Dy()))
+
+	var m ebiten.GeoM
+	m.Translate(-ox, -oy)
+	// The functions apply from right to left, like in CSS.
+	for i := len(a.Transform) - 1; i >= 0; i-- {
+		t := a.Transform[i]
+		switch t.Func {
+		case TransformTranslate:
+			m.Translate(t.X*GlobalScale, t.Y*GlobalScale)
+		case TransformScale:
+			m.Scale(t.X, t.Y)
+		case TransformRotate:
+			m.Rotate(t.X)
+		}
+	}
+	if scaled {
+		m.Scale(*a.Scale, *a.Scale)
+	}
+	m.Translate(ox, oy)
+	return m, true
+}
+
+// localPoint returns the point of the screen in the untransformed coordinates
+// of the view, which its frame and its handlers use.
+func (v *View) localPoint(x, y int) (int, int) {
+	m, ok := v.transform()
+	if !ok {
+		return x, y
+	}
+	if !m.IsInvertible() {
+		// The view is flattened to a line or a point and can't be hit.
+		return math.MinInt32, math.MinInt32
+	}
+	m.Invert()
+	lx, ly := m.Apply(float64(x), float64(y))
+	return int(math.Floor(lx)), int(math.Floor(ly))
+}
+
+// screenRect returns the bounding box of the rectangle of the view transformed on the screen.
+func (v *View) screenRect(r image.Rectangle) image.Rectangle {
+	m, ok := v.transform()
+	if !ok {
+		return r
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range []image.Point{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}} {
+		x, y := m.Apply(float64(p.X), float64(p.Y))
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
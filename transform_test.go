@@ -0,0 +1,107 @@
+package furex
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applyGeoM(m ebiten.GeoM, x, y float64) []float64 {
+	x, y = m.Apply(x, y)
+	return []float64{math.Round(x*1e6) / 1e6, math.Round(y*1e6) / 1e6}
+}
+
+func TestTransform(t *testing.T) {
+	child := &View{Attrs: ViewAttrs{Width: Px(20), Height: Px(20), MarginLeft: Px(10), MarginTop: Px(10)}}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), AlignItems: AlignItemStart}}
+	root.AddChild(child)
+	root.Update()
+	require.Equal(t, image.Rect(10, 10, 30, 30), child.frame)
+
+	_, ok := child.transform()
+	require.False(t, ok)
+
+	child.SetTransform(TransformFunc{Func: TransformTranslate, X: 5, Y: -5})
+	assert.Equal(t, []float64{15, 5}, applyGeoM(child.GeoM(), 10, 10))
+
+	// Rotations are clockwise around the center of the frame.
+	child.SetTransform(TransformFunc{Func: TransformRotate, X: math.Pi / 2})
+	assert.Equal(t, []float64{30, 10}, applyGeoM(child.GeoM(), 10, 10))
+
+	// The functions apply from right to left around the transform origin.
+	child.SetTransformOrigin(Pct(0), Px(0))
+	child.SetTransform(
+		TransformFunc{Func: TransformTranslate, X: 10},
+		TransformFunc{Func: TransformScale, X: 2, Y: 3},
+	)
+	assert.Equal(t, []float64{60, 70}, applyGeoM(child.GeoM(), 30, 30))
+
+	// The scale applies after the transform, and the transforms of
+	// the ancestors after the transform of the view.
+	child.SetScale(0.5)
+	assert.Equal(t, []float64{35, 40}, applyGeoM(child.GeoM(), 30, 30))
+	root.SetTransform(TransformFunc{Func: TransformTranslate, Y: 100})
+	assert.Equal(t, []float64{35, 140}, applyGeoM(child.GeoM(), 30, 30))
+	assert.Equal(t, []float64{0, 100}, applyGeoM(root.GeoM(), 0, 0))
+
+	root.Draw(ebiten.NewImage(100, 100))
+}
+
+func TestTransformHitTest(t *testing.T) {
+	h := NewMockHandler()
+	child := &View{
+		Attrs:   ViewAttrs{Width: Px(20), Height: Px(20), Transform: []TransformFunc{{Func: TransformScale, X: 2, Y: 2}}},
+		Handler: h.ViewHandler,
+	}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100), Direction: Column, Justify: JustifyCenter, AlignItems: AlignItemCenter}}
+	root.AddChild(child)
+	root.Update()
+	require.Equal(t, image.Rect(40, 40, 60, 60), child.frame)
+
+	// The scaled view covers (30, 30)-(70, 70).
+	require.Equal(t, child, root.viewAt(32, 68))
+	require.Equal(t, root, root.viewAt(28, 50))
+	require.Equal(t, []int{41, 59}, func() []int { x, y := child.localPoint(32, 68); return []int{x, y} }())
+
+	root.handleMouseButtonLeftPressed(nil, 32, 68)
+	require.True(t, h.IsClickPressed)
+	root.handleMouseButtonLeftReleased(nil, 32, 68)
+	require.True(t, h.IsClickReleased)
+
+	// A view rotated by 45 degrees covers the diamond around its center.
+	h.IsClickPressed = false
+	child.SetTransform(TransformFunc{Func: TransformRotate, X: math.Pi / 4})
+	root.handleMouseButtonLeftPressed(nil, 41, 41)
+	require.False(t, h.IsClickPressed)
+	root.handleMouseButtonLeftPressed(nil, 50, 36)
+	require.True(t, h.IsClickPressed)
+
+	// Views scaled to nothing can't be hit.
+	child.SetTransform(TransformFunc{Func: TransformScale, X: 0, Y: 1})
+	require.Equal(t, root, root.viewAt(50, 50))
+}
+
+func TestTransformClip(t *testing.T) {
+	inner := &View{Attrs: ViewAttrs{Width: Px(40), Height: Px(40)}}
+	clip := &View{Attrs: ViewAttrs{
+		Width:     Px(20),
+		Height:    Px(20),
+		Overflow:  OverflowHidden,
+		Transform: []TransformFunc{{Func: TransformTranslate, X: 50}},
+	}}
+	root := &View{Attrs: ViewAttrs{Width: Px(100), Height: Px(100)}}
+	root.AddChild(clip.AddChild(inner))
+	root.Update()
+
+	// The clip moves with the transformed view.
+	require.True(t, inner.isInsideClip(60, 10))
+	require.False(t, inner.isInsideClip(10, 10))
+	require.False(t, inner.isInsideClip(80, 10))
+	r, ok := inner.clipRect()
+	require.True(t, ok)
+	require.Equal(t, image.Rect(50, 0, 70, 20), r)
+}
@@ -3,6 +3,8 @@ package furex
 import (
 	"fmt"
 	"image"
+	"image/color"
+	"math"
 	"strings"
 	"sync"
 
@@ -18,34 +20,134 @@ func NewView(opts ...ViewOption) *View {
 }
 
 type ViewAttrs struct {
-	Left         int
-	Right        *int
-	Top          int
-	Bottom       *int
-	Width        int
-	WidthInPct   float64
-	Height       int
-	HeightInPct  float64
-	MarginLeft   int
-	MarginTop    int
-	MarginRight  int
-	MarginBottom int
-	Position     FlexPosition
-	Direction    FlexDirection
-	Wrap         FlexWrap
-	Justify      FlexJustify
-	AlignItems   FlexAlignItem
-	AlignContent FlexAlignContent
-	Grow         float64
-	Shrink       float64
-	Display      FlexDisplay
-
-	ID         string
-	Raw        string
-	TagName    string
-	Text       string
+	// Left, Right, Top and Bottom are the insets of an absolutely or relatively
+	// positioned view. Percentages are relative to the width or the height of
+	// the containing view. An inset is not set if it is auto.
+	Left   Length
+	Right  Length
+	Top    Length
+	Bottom Length
+	// Width and Height are the size of the view. Percentages are relative to the
+	// size of the containing view. The size is determined by the layout if it is
+	// auto or zero.
+	Width  Length
+	Height Length
+	// WidthInPct and HeightInPct are the size of the view in percent of the size
+	// of its container, limited to the space left by the other items on the main
+	// axis. They are used only when Width or Height is auto.
+	WidthInPct  float64
+	HeightInPct float64
+	MinWidth    Length
+	MaxWidth    Length
+	MinHeight   Length
+	MaxHeight   Length
+	// The margins and the paddings are zero if they are auto. Percentages are
+	// relative to the width of the containing view, as in CSS.
+	MarginLeft    Length
+	MarginTop     Length
+	MarginRight   Length
+	MarginBottom  Length
+	PaddingLeft   Length
+	PaddingTop    Length
+	PaddingRight  Length
+	PaddingBottom Length
+	RowGap        int
+	ColumnGap     int
+	Position      FlexPosition
+	Direction     FlexDirection
+	Wrap          FlexWrap
+	Justify       FlexJustify
+	AlignItems    FlexAlignItem
+	AlignContent  FlexAlignContent
+	AlignSelf     FlexAlignSelf
+	Order         int
+	ZIndex        int
+	Grow          float64
+	Shrink        float64
+	Basis         Length
+	Display       FlexDisplay
+	Overflow      FlexOverflow
+	// ScrollbarWidth is the width of the scrollbars of a scroll container.
+	// Scrollbars are not drawn when it is 0.
+	ScrollbarWidth int
+	// Color is the color of the text. It is inherited from the parent view if nil.
+	Color color.Color
+	// FontFamily is the name of a font registered with RegisterFont.
+	// It is inherited from the parent view if empty.
+	FontFamily string
+	// FontSize is the font size of the text. Em and percent sizes are relative
+	// to the font size of the parent view, rem sizes to that of the root view. It is
+	// inherited from the parent view if auto, BaseFontSize is used if no view sets it.
+	FontSize Length
+	// LineHeight is the height of the lines of text, em and percent lengths are
+	// relative to the font size. It is inherited from the parent view if auto,
+	// the height given by the font is used if no view sets it.
+	LineHeight    Length
+	TextAlign     FlexTextAlign
+	VerticalAlign FlexVerticalAlign
+	WhiteSpace    FlexWhiteSpace
+	TextOverflow  FlexTextOverflow
+	// BackgroundColor fills the frame of the view.
+	BackgroundColor color.Color
+	// BackgroundImage is the name of an image registered with RegisterImage,
+	// which is stretched over the frame of the view.
+	BackgroundImage string
+	// BorderWidth and BorderColor draw a border inside the frame of the view.
+	// The border doesn't take space in layout.
+	BorderWidth int
+	BorderColor color.Color
+	// BorderRadius rounds the corners of the background and the border.
+	BorderRadius float64
+	// BorderImage is an image drawn in nine slices over the frame of the view,
+	// in place of the border.
+	BorderImage *NineSlice
+	// Opacity is the opacity of the view and its descendants, from 0 to 1.
+	// The view is opaque if it is nil.
+	Opacity *float64
+	// BoxShadow is a shadow drawn under the view.
+	BoxShadow *Shadow
+	// Scale is the scale the view and its descendants are drawn in around its transform
+	// origin. It doesn't change the layout. The view is not scaled if it is nil.
+	Scale *float64
+	// Transform moves, scales and rotates the view and its descendants when they
+	// are drawn and hit-tested, around the transform origin. It doesn't change the layout.
+	Transform []TransformFunc
+	// TransformOriginX and TransformOriginY are the origin of the transform and the
+	// scale of the view from the top left corner of its frame. Percentages are
+	// relative to the size of the frame, the origin is at the center if they are auto.
+	TransformOriginX Length
+	TransformOriginY Length
+	// Transitions animate the properties changed by the setters of the view.
+	Transitions []Transition
+	// Animations are the keyframe animations played on the view.
+	Animations []Animation
+	// Cache draws the view and its descendants on an offscreen image once, which is
+	// drawn on the screen until the view is laid out again or invalidated.
+	Cache bool
+
+	ID      string
+	Raw     string
+	TagName string
+	Text    string
+	// Spans are the runs of rich text of the view, which are drawn in place of Text.
+	Spans []TextSpan
+	// DrawText draws and measures Text or Spans with the text properties of the view.
+	// It has no effect on views whose handler draws them.
+	DrawText   bool
 	ExtraAttrs map[string]string
 	Hidden     bool
+	// Focusable is true if the view can be focused to receive key events.
+	Focusable bool
+	// TabIndex is the order of the view in Tab navigation. Views with a positive
+	// tab index come first in ascending order, then the ones with zero in document
+	// order. Views with a negative tab index are skipped.
+	TabIndex int
+	// NavUp, NavDown, NavLeft and NavRight are the IDs of the views focused by
+	// gamepad navigation from this view, in place of the nearest ones.
+	NavUp    string
+	NavDown  string
+	NavLeft  string
+	NavRight string
 }
 
 // View represents a UI element.
@@ -61,13 +163,24 @@ type View struct {
 	lock      sync.Mutex
 	hasParent bool
 	parent    *View
+	used      usedAttrs
+	// focused is the focused view of the tree, held by the root view.
+	focused *View
+	// docKeyframes are the keyframes defined by @keyframes in the HTML
+	// the view is parsed from, held by the root view of the document.
+	docKeyframes map[string][]Keyframe
 }
 
 // Update updates the view
 func (v *View) Update() {
+	if !v.hasParent {
+		// The animations of the tree run before layout, so it lays out the animated values.
+		v.updateAnimations()
+	}
 	if v.isDirty {
 		v.startLayout()
 	}
+	v.updateScroll()
 	if !v.hasParent {
 		v.processHandler()
 	}
@@ -87,56 +200,121 @@ func (v *View) processHandler() {
 func (v *View) startLayout() {
 	v.lock.Lock()
 	defer v.lock.Unlock()
+	ctx := v.lengthContext()
 	if !v.hasParent {
-		v.bounds = image.Rect(0, 0, v.Attrs.Width, v.Attrs.Height)
-		v.frame = image.Rect(v.Attrs.Left, v.Attrs.Top, v.Attrs.Left+v.Attrs.Width, v.Attrs.Top+v.Attrs.Height)
+		v.resolveLengths(ctx.viewportWidth, ctx.viewportHeight, ctx)
+		var left, top int
+		if v.used.Left != nil {
+			left = *v.used.Left
+		}
+		if v.used.Top != nil {
+			top = *v.used.Top
+		}
+		v.bounds = image.Rect(0, 0, v.used.Width, v.used.Height)
+		v.frame = v.bounds.Add(image.Pt(left, top))
 	}
 
+	// Static children are laid out in the content box,
+	// absolutely positioned children in the padding box.
+	contentWidth := float64(v.bounds.Dx() - v.used.PaddingLeft - v.used.PaddingRight)
+	contentHeight := float64(v.bounds.Dy() - v.used.PaddingTop - v.used.PaddingBottom)
 	for _, child := range v.children {
-		if child.Attrs.Position == PositionStatic {
-			child.startLayout()
+		childCtx := ctx.child(child)
+		if child.IsAbsolute() {
+			// Absolutely positioned children are laid out by the layout of the view.
+			child.resolveLengths(float64(v.bounds.Dx()), float64(v.bounds.Dy()), childCtx)
+		} else {
+			child.resolveLengths(contentWidth, contentHeight, childCtx)
+			child.layoutIfNeeded(childCtx)
 		}
 	}
 
 	v.layout(v.bounds.Dx(), v.bounds.Dy(), &v.containerEmbed)
+	if v.isScrollContainer() {
+		v.layoutScroll()
+	}
 	v.isDirty = false
+	v.laidOut = layoutInput{valid: true, size: v.bounds.Size(), used: v.used, ctx: ctx}
+}
+
+// layoutInput is what the layout of the children of a view depends on,
+// besides the views themselves.
+type layoutInput struct {
+	valid bool
+	size  image.Point
+	used  usedAttrs
+	ctx   lengthContext
+}
+
+// layoutIfNeeded lays out the children of the view if it is dirty, or if its
+// size or its used attributes changed since its last layout. The layouts of
+// clean subtrees are kept, so only the changed parts of the tree are laid out.
+func (v *View) layoutIfNeeded(ctx lengthContext) {
+	l := &v.laidOut
+	if v.isDirty || !l.valid || l.size != v.bounds.Size() || l.ctx != ctx || !l.used.equal(&v.used) {
+		v.startLayout()
+	}
+}
+
+// isSizeFixed returns true if the size of the view doesn't depend on its content.
+func (v *View) isSizeFixed() bool {
+	return v.isWidthFixed() && v.isHeightFixed() && v.Attrs.Basis.Unit != LengthContent
 }
 
 // UpdateWithSize the view with modified height and width
 func (v *View) UpdateWithSize(width, height int) {
-	if !v.hasParent && (v.Attrs.Width != width || v.Attrs.Height != height) {
-		v.Attrs.Height = height
-		v.Attrs.Width = width
+	w, h := Px(float64(width)), Px(float64(height))
+	if !v.hasParent && (v.Attrs.Width != w || v.Attrs.Height != h) {
+		v.Attrs.Height = h
+		v.Attrs.Width = w
 		v.isDirty = true
 	}
 	v.Update()
 }
 
-// Layout marks the view as dirty
+// Layout marks the view as dirty, with its ancestors up to the nearest one
+// whose size doesn't depend on the view, which is laid out again with the
+// changed part of its subtree.
 func (v *View) Layout() {
 	v.isDirty = true
-	if v.hasParent {
-		v.parent.isDirty = true
+	v.Invalidate()
+	for c := v; c.hasParent; c = c.parent {
+		c.parent.isDirty = true
+		if c.IsAbsolute() || c.parent.isSizeFixed() {
+			break
+		}
 	}
 }
 
 // Draw draws the view
 func (v *View) Draw(screen *ebiten.Image) {
-	if v.isDirty {
-		v.startLayout()
-	}
-	if !v.hasParent {
-		// scale frame with GlobalScale
-		v.handleDrawRoot(screen, scaleFrame(v.frame))
-	}
-	if !v.Attrs.Hidden && v.Attrs.Display != DisplayNone {
-		v.childrenDraw(screen)
+	v.relayout()
+	if !v.hasParent && v.Attrs.Cache {
+		v.drawCached(screen)
+	} else {
+		if !v.hasParent {
+			// scale frame with GlobalScale
+			v.handleDrawRoot(screen, scaleFrame(v.frame))
+		}
+		if !v.Attrs.Hidden && v.Attrs.Display != DisplayNone {
+			v.childrenDraw(screen)
+		}
 	}
 	if Debug && !v.hasParent && v.Attrs.Display != DisplayNone {
 		debugBorders(screen, v.containerEmbed)
 	}
 }
 
+// relayout lays out the dirty views of the tree, parents first.
+func (v *View) relayout() {
+	if v.isDirty {
+		v.startLayout()
+	}
+	for _, c := range v.children {
+		c.relayout()
+	}
+}
+
 // AddTo add itself to a parent view
 func (v *View) AddTo(parent *View) *View {
 	if v.hasParent {
@@ -160,7 +338,8 @@ func (v *View) replaceChild(old, new *View) {
 			new.parent = v
 			new.hasParent = true
 			v.children[i] = new
-			v.isDirty = true
+			v.Layout()
+			v.invalidateHitOrder()
 			return
 		}
 	}
@@ -179,7 +358,8 @@ func (v *View) RemoveChild(cv *View) bool {
 	for i, child := range v.children {
 		if child == cv {
 			v.children = append(v.children[:i], v.children[i+1:]...)
-			v.isDirty = true
+			v.Layout()
+			v.invalidateHitOrder()
 			cv.hasParent = false
 			cv.parent = nil
 			return true
@@ -190,7 +370,8 @@ func (v *View) RemoveChild(cv *View) bool {
 
 // RemoveAll removes all children view
 func (v *View) RemoveAll() {
-	v.isDirty = true
+	v.Layout()
+	v.invalidateHitOrder()
 	for _, child := range v.children {
 		child.hasParent = false
 		child.parent = nil
@@ -205,7 +386,8 @@ func (v *View) PopChild() *View {
 	}
 	c := v.children[len(v.children)-1]
 	v.children = v.children[:len(v.children)-1]
-	v.isDirty = true
+	v.Layout()
+	v.invalidateHitOrder()
 	c.hasParent = false
 	c.parent = nil
 	return c
@@ -213,32 +395,83 @@ func (v *View) PopChild() *View {
 
 func (v *View) addChild(cv *View) *View {
 	v.children = append(v.children, cv)
-	v.isDirty = true
+	v.Layout()
+	v.invalidateHitOrder()
 	cv.hasParent = true
 	cv.parent = v
 	return v
 }
 
 func (v *View) isWidthFixed() bool {
-	return v.Attrs.Width != 0 || v.Attrs.WidthInPct != 0
+	return v.used.Width != 0 || v.used.WidthInPct != 0
 }
 
 func (v *View) width() int {
-	if v.Attrs.Width == 0 {
+	if v.used.Width == 0 {
 		return v.calculatedWidth
 	}
-	return v.Attrs.Width
+	return v.used.Width
+}
+
+// widthConstraints returns the minimum and maximum width of the view,
+// resolving percentages against the width of the containing view.
+// The maximum width is +Inf if it is not specified.
+func (v *View) widthConstraints(containerWidth float64, ctx lengthContext) (float64, float64) {
+	return lengthConstraints(v.Attrs.MinWidth, v.Attrs.MaxWidth, containerWidth, ctx)
+}
+
+// heightConstraints returns the minimum and maximum height of the view,
+// resolving percentages against the height of the containing view.
+// The maximum height is +Inf if it is not specified.
+func (v *View) heightConstraints(containerHeight float64, ctx lengthContext) (float64, float64) {
+	return lengthConstraints(v.Attrs.MinHeight, v.Attrs.MaxHeight, containerHeight, ctx)
+}
+
+func lengthConstraints(minLen, maxLen Length, ref float64, ctx lengthContext) (float64, float64) {
+	min, max := 0.0, math.Inf(1)
+	if v, ok := minLen.resolve(ref, ctx); ok && v > 0 {
+		min = v
+	}
+	if v, ok := maxLen.resolve(ref, ctx); ok {
+		max = v
+	}
+	return min, max
+}
+
+// measure sets the content size of the view to the size measured by its handler,
+// or to the size of its text. The available size includes the padding of the view.
+func (v *View) measure(availableW, availableH int, mode MeasureMode) {
+	paddingX := v.used.PaddingLeft + v.used.PaddingRight
+	paddingY := v.used.PaddingTop + v.used.PaddingBottom
+	availableW, availableH = availableW-paddingX, availableH-paddingY
+	if availableW < 0 {
+		availableW = 0
+	}
+	if availableH < 0 {
+		availableH = 0
+	}
+	var w, h int
+	if v.Handler.Measure != nil {
+		w, h = v.Handler.HandleMeasure(availableW, availableH, mode)
+	} else {
+		w, h = v.measureText(availableW, availableH, mode)
+	}
+	if mode == MeasureModeExactly {
+		w = availableW
+	}
+	v.calculatedWidth = w + paddingX
+	v.calculatedHeight = h + paddingY
 }
 
 func (v *View) isHeightFixed() bool {
-	return v.Attrs.Height != 0 || v.Attrs.HeightInPct != 0
+	return v.used.Height != 0 || v.used.HeightInPct != 0
 }
 
 func (v *View) height() int {
-	if v.Attrs.Height == 0 {
+	if v.used.Height == 0 {
 		return v.calculatedHeight
 	}
-	return v.Attrs.Height
+	return v.used.Height
 }
 
 func (v *View) GetChildren() []*View {
@@ -306,82 +539,186 @@ func (v *View) FilterByTagName(tagName string) []*View {
 	return views
 }
 
-// SetLeft sets the left position of the view.
+// SetLeft sets the left position of the view in pixels.
 func (v *View) SetLeft(left int) {
-	if left != v.Attrs.Left {
-		v.Attrs.Left = left
+	if v.transition(PropertyLeft, animValue{float64(left)}) {
+		return
+	}
+	if l := Px(float64(left)); l != v.Attrs.Left {
+		v.Attrs.Left = l
 		v.Layout()
 	}
 }
 
-// SetRight sets the right position of the view.
+// SetRight sets the right position of the view in pixels.
 func (v *View) SetRight(right int) {
-	if v.Attrs.Right == nil || *v.Attrs.Right != right {
-		v.Attrs.Right = Int(right)
+	if l := Px(float64(right)); l != v.Attrs.Right {
+		v.Attrs.Right = l
 		v.Layout()
 	}
 }
 
-// SetTop sets the top position of the view.
+// SetTop sets the top position of the view in pixels.
 func (v *View) SetTop(top int) {
-	if top != v.Attrs.Top {
-		v.Attrs.Top = top
+	if v.transition(PropertyTop, animValue{float64(top)}) {
+		return
+	}
+	if l := Px(float64(top)); l != v.Attrs.Top {
+		v.Attrs.Top = l
 		v.Layout()
 	}
 }
 
-// SetBottom sets the bottom position of the view.
+// SetBottom sets the bottom position of the view in pixels.
 func (v *View) SetBottom(bottom int) {
-	if v.Attrs.Bottom == nil || *v.Attrs.Bottom != bottom {
-		v.Attrs.Bottom = Int(bottom)
+	if l := Px(float64(bottom)); l != v.Attrs.Bottom {
+		v.Attrs.Bottom = l
 		v.Layout()
 	}
 }
 
-// SetWidth sets the width of the view.
+// SetWidth sets the width of the view in pixels.
 func (v *View) SetWidth(width int) {
-	if width != v.Attrs.Width {
-		v.Attrs.Width = width
+	if v.transition(PropertyWidth, animValue{float64(width)}) {
+		return
+	}
+	if l := Px(float64(width)); l != v.Attrs.Width {
+		v.Attrs.Width = l
 		v.Layout()
 	}
 }
 
-// SetHeight sets the height of the view.
+// SetHeight sets the height of the view in pixels.
 func (v *View) SetHeight(height int) {
-	if height != v.Attrs.Height {
-		v.Attrs.Height = height
+	if v.transition(PropertyHeight, animValue{float64(height)}) {
+		return
+	}
+	if l := Px(float64(height)); l != v.Attrs.Height {
+		v.Attrs.Height = l
+		v.Layout()
+	}
+}
+
+// SetMinWidth sets the minimum width of the view.
+func (v *View) SetMinWidth(minWidth Length) {
+	if minWidth != v.Attrs.MinWidth {
+		v.Attrs.MinWidth = minWidth
+		v.Layout()
+	}
+}
+
+// SetMaxWidth sets the maximum width of the view.
+func (v *View) SetMaxWidth(maxWidth Length) {
+	if maxWidth != v.Attrs.MaxWidth {
+		v.Attrs.MaxWidth = maxWidth
+		v.Layout()
+	}
+}
+
+// SetMinHeight sets the minimum height of the view.
+func (v *View) SetMinHeight(minHeight Length) {
+	if minHeight != v.Attrs.MinHeight {
+		v.Attrs.MinHeight = minHeight
 		v.Layout()
 	}
 }
 
-// SetMarginLeft sets the left margin of the view.
+// SetMaxHeight sets the maximum height of the view.
+func (v *View) SetMaxHeight(maxHeight Length) {
+	if maxHeight != v.Attrs.MaxHeight {
+		v.Attrs.MaxHeight = maxHeight
+		v.Layout()
+	}
+}
+
+// SetMarginLeft sets the left margin of the view in pixels.
 func (v *View) SetMarginLeft(marginLeft int) {
-	if marginLeft != v.Attrs.MarginLeft {
-		v.Attrs.MarginLeft = marginLeft
+	if v.transition(PropertyMarginLeft, animValue{float64(marginLeft)}) {
+		return
+	}
+	if l := Px(float64(marginLeft)); l != v.Attrs.MarginLeft {
+		v.Attrs.MarginLeft = l
 		v.Layout()
 	}
 }
 
-// SetMarginTop sets the top margin of the view.
+// SetMarginTop sets the top margin of the view in pixels.
 func (v *View) SetMarginTop(marginTop int) {
-	if marginTop != v.Attrs.MarginTop {
-		v.Attrs.MarginTop = marginTop
+	if v.transition(PropertyMarginTop, animValue{float64(marginTop)}) {
+		return
+	}
+	if l := Px(float64(marginTop)); l != v.Attrs.MarginTop {
+		v.Attrs.MarginTop = l
 		v.Layout()
 	}
 }
 
-// SetMarginRight sets the right margin of the view.
+// SetMarginRight sets the right margin of the view in pixels.
 func (v *View) SetMarginRight(marginRight int) {
-	if marginRight != v.Attrs.MarginRight {
-		v.Attrs.MarginRight = marginRight
+	if v.transition(PropertyMarginRight, animValue{float64(marginRight)}) {
+		return
+	}
+	if l := Px(float64(marginRight)); l != v.Attrs.MarginRight {
+		v.Attrs.MarginRight = l
 		v.Layout()
 	}
 }
 
-// SetMarginBottom sets the bottom margin of the view.
+// SetMarginBottom sets the bottom margin of the view in pixels.
 func (v *View) SetMarginBottom(marginBottom int) {
-	if marginBottom != v.Attrs.MarginBottom {
-		v.Attrs.MarginBottom = marginBottom
+	if v.transition(PropertyMarginBottom, animValue{float64(marginBottom)}) {
+		return
+	}
+	if l := Px(float64(marginBottom)); l != v.Attrs.MarginBottom {
+		v.Attrs.MarginBottom = l
+		v.Layout()
+	}
+}
+
+// SetPaddingLeft sets the left padding of the view in pixels.
+func (v *View) SetPaddingLeft(paddingLeft int) {
+	if l := Px(float64(paddingLeft)); l != v.Attrs.PaddingLeft {
+		v.Attrs.PaddingLeft = l
+		v.Layout()
+	}
+}
+
+// SetPaddingTop sets the top padding of the view in pixels.
+func (v *View) SetPaddingTop(paddingTop int) {
+	if l := Px(float64(paddingTop)); l != v.Attrs.PaddingTop {
+		v.Attrs.PaddingTop = l
+		v.Layout()
+	}
+}
+
+// SetPaddingRight sets the right padding of the view in pixels.
+func (v *View) SetPaddingRight(paddingRight int) {
+	if l := Px(float64(paddingRight)); l != v.Attrs.PaddingRight {
+		v.Attrs.PaddingRight = l
+		v.Layout()
+	}
+}
+
+// SetPaddingBottom sets the bottom padding of the view in pixels.
+func (v *View) SetPaddingBottom(paddingBottom int) {
+	if l := Px(float64(paddingBottom)); l != v.Attrs.PaddingBottom {
+		v.Attrs.PaddingBottom = l
+		v.Layout()
+	}
+}
+
+// SetRowGap sets the gap between rows of the view.
+func (v *View) SetRowGap(rowGap int) {
+	if rowGap != v.Attrs.RowGap {
+		v.Attrs.RowGap = rowGap
+		v.Layout()
+	}
+}
+
+// SetColumnGap sets the gap between columns of the view.
+func (v *View) SetColumnGap(columnGap int) {
+	if columnGap != v.Attrs.ColumnGap {
+		v.Attrs.ColumnGap = columnGap
 		v.Layout()
 	}
 }
@@ -398,6 +735,23 @@ func (v *View) IsAbsolute() bool {
 	return v.Attrs.Position == PositionAbsolute
 }
 
+// relativeOffset returns the offset of a relatively positioned view.
+// The left and top insets take precedence over the right and bottom ones.
+func (v *View) relativeOffset() image.Point {
+	var p image.Point
+	if v.used.Left != nil {
+		p.X = *v.used.Left
+	} else if v.used.Right != nil {
+		p.X = -*v.used.Right
+	}
+	if v.used.Top != nil {
+		p.Y = *v.used.Top
+	} else if v.used.Bottom != nil {
+		p.Y = -*v.used.Bottom
+	}
+	return p
+}
+
 // SetDirection sets the direction of the view.
 func (v *View) SetDirection(direction FlexDirection) {
 	if direction != v.Attrs.Direction {
@@ -438,6 +792,31 @@ func (v *View) SetAlignContent(alignContent FlexAlignContent) {
 	}
 }
 
+// SetAlignSelf sets the align self property of the view.
+func (v *View) SetAlignSelf(alignSelf FlexAlignSelf) {
+	if alignSelf != v.Attrs.AlignSelf {
+		v.Attrs.AlignSelf = alignSelf
+		v.Layout()
+	}
+}
+
+// SetOrder sets the order in which the view is laid out among its siblings.
+func (v *View) SetOrder(order int) {
+	if order != v.Attrs.Order {
+		v.Attrs.Order = order
+		v.Layout()
+	}
+}
+
+// SetZIndex sets the z-index of the view.
+// It only changes the order views are drawn and hit-tested in, so the layout is kept.
+func (v *View) SetZIndex(zIndex int) {
+	if zIndex != v.Attrs.ZIndex {
+		v.Attrs.ZIndex = zIndex
+		v.Invalidate()
+	}
+}
+
 // SetGrow sets the grow property of the view.
 func (v *View) SetGrow(grow float64) {
 	if grow != v.Attrs.Grow {
@@ -446,6 +825,14 @@ func (v *View) SetGrow(grow float64) {
 	}
 }
 
+// SetBasis sets the flex basis of the view.
+func (v *View) SetBasis(basis Length) {
+	if basis != v.Attrs.Basis {
+		v.Attrs.Basis = basis
+		v.Layout()
+	}
+}
+
 // SetShrink sets the shrink property of the view.
 func (v *View) SetShrink(shrink float64) {
 	if shrink != v.Attrs.Shrink {
@@ -462,6 +849,209 @@ func (v *View) SetDisplay(display FlexDisplay) {
 	}
 }
 
+// SetOverflow sets the overflow of the view.
+func (v *View) SetOverflow(overflow FlexOverflow) {
+	if overflow != v.Attrs.Overflow {
+		v.Attrs.Overflow = overflow
+		v.Layout()
+	}
+}
+
+// SetScrollbarWidth sets the width of the scrollbars of the view.
+func (v *View) SetScrollbarWidth(width int) {
+	if width != v.Attrs.ScrollbarWidth {
+		v.Attrs.ScrollbarWidth = width
+		v.Invalidate()
+	}
+}
+
+// SetText sets the text of the view, removing its spans.
+func (v *View) SetText(text string) {
+	if text != v.Attrs.Text || len(v.Attrs.Spans) > 0 {
+		v.Attrs.Text, v.Attrs.Spans = text, nil
+		v.Layout()
+	}
+}
+
+// SetDrawText sets whether the text of the view is drawn and measured by furex.
+func (v *View) SetDrawText(drawText bool) {
+	if drawText != v.Attrs.DrawText {
+		v.Attrs.DrawText = drawText
+		v.Layout()
+	}
+}
+
+// SetSpans sets the rich text of the view. Text is set to the text of the spans.
+func (v *View) SetSpans(spans ...TextSpan) {
+	v.Attrs.Text, v.Attrs.Spans = spansText(spans), spans
+	v.Layout()
+}
+
+// SetColor sets the color of the text of the view.
+func (v *View) SetColor(c color.Color) {
+	if c != nil && v.transition(PropertyColor, colorValue(c)) {
+		return
+	}
+	v.StopAnimation(PropertyColor)
+	v.Attrs.Color = c
+	v.invalidateTree()
+}
+
+// SetFontFamily sets the font family of the text of the view.
+func (v *View) SetFontFamily(family string) {
+	if family != v.Attrs.FontFamily {
+		v.Attrs.FontFamily = family
+		v.Layout()
+	}
+}
+
+// SetFontSize sets the font size of the text of the view.
+func (v *View) SetFontSize(size Length) {
+	if size != v.Attrs.FontSize {
+		v.Attrs.FontSize = size
+		v.Layout()
+	}
+}
+
+// SetLineHeight sets the line height of the text of the view.
+func (v *View) SetLineHeight(lineHeight Length) {
+	if lineHeight != v.Attrs.LineHeight {
+		v.Attrs.LineHeight = lineHeight
+		v.Layout()
+	}
+}
+
+// SetTextAlign sets the horizontal alignment of the text of the view.
+func (v *View) SetTextAlign(align FlexTextAlign) {
+	v.Attrs.TextAlign = align
+	v.invalidateTree()
+}
+
+// SetVerticalAlign sets the vertical alignment of the text of the view.
+func (v *View) SetVerticalAlign(align FlexVerticalAlign) {
+	v.Attrs.VerticalAlign = align
+	v.Invalidate()
+}
+
+// SetWhiteSpace sets how white spaces and line breaks of the text of the view are handled.
+func (v *View) SetWhiteSpace(whiteSpace FlexWhiteSpace) {
+	if whiteSpace != v.Attrs.WhiteSpace {
+		v.Attrs.WhiteSpace = whiteSpace
+		v.Layout()
+	}
+}
+
+// SetTextOverflow sets how the lines of text wider than the view are drawn.
+func (v *View) SetTextOverflow(overflow FlexTextOverflow) {
+	v.Attrs.TextOverflow = overflow
+	v.Invalidate()
+}
+
+// SetBackgroundColor sets the background color of the view.
+func (v *View) SetBackgroundColor(c color.Color) {
+	if c != nil && v.transition(PropertyBackgroundColor, colorValue(c)) {
+		return
+	}
+	v.StopAnimation(PropertyBackgroundColor)
+	v.Attrs.BackgroundColor = c
+	v.Invalidate()
+}
+
+// SetBackgroundImage sets the name of the background image of the view.
+func (v *View) SetBackgroundImage(name string) {
+	v.Attrs.BackgroundImage = name
+	v.Invalidate()
+}
+
+// SetBorderWidth sets the width of the border of the view.
+func (v *View) SetBorderWidth(width int) {
+	if width != v.Attrs.BorderWidth {
+		v.Attrs.BorderWidth = width
+		v.Invalidate()
+	}
+}
+
+// SetBorderColor sets the color of the border of the view.
+func (v *View) SetBorderColor(c color.Color) {
+	if c != nil && v.transition(PropertyBorderColor, colorValue(c)) {
+		return
+	}
+	v.StopAnimation(PropertyBorderColor)
+	v.Attrs.BorderColor = c
+	v.Invalidate()
+}
+
+// SetBorderRadius sets the radius of the corners of the view.
+func (v *View) SetBorderRadius(radius float64) {
+	if radius != v.Attrs.BorderRadius {
+		v.Attrs.BorderRadius = radius
+		v.Invalidate()
+	}
+}
+
+// SetBorderImage sets the nine slice image of the view, or removes it if nil.
+func (v *View) SetBorderImage(img *NineSlice) {
+	if img != v.Attrs.BorderImage {
+		v.Attrs.BorderImage = img
+		v.Invalidate()
+	}
+}
+
+// SetOpacity sets the opacity of the view.
+func (v *View) SetOpacity(opacity float64) {
+	if v.transition(PropertyOpacity, animValue{opacity}) {
+		return
+	}
+	v.Attrs.Opacity = &opacity
+	v.invalidateComposite()
+}
+
+// SetScale sets the scale the view and its descendants are drawn in around its transform origin.
+func (v *View) SetScale(scale float64) {
+	if v.transition(PropertyScale, animValue{scale}) {
+		return
+	}
+	v.Attrs.Scale = &scale
+	v.invalidateComposite()
+}
+
+// SetTransform sets the transform functions of the view.
+func (v *View) SetTransform(transform ...TransformFunc) {
+	v.Attrs.Transform = transform
+	v.invalidateComposite()
+}
+
+// SetTransformOrigin sets the origin of the transform of the view.
+func (v *View) SetTransformOrigin(x, y Length) {
+	v.Attrs.TransformOriginX, v.Attrs.TransformOriginY = x, y
+	v.invalidateComposite()
+}
+
+// SetTransitions sets the transitions of the properties of the view.
+func (v *View) SetTransitions(transitions ...Transition) {
+	v.Attrs.Transitions = transitions
+}
+
+// SetBoxShadow sets the shadow of the view, or removes it if nil.
+func (v *View) SetBoxShadow(shadow *Shadow) {
+	v.Attrs.BoxShadow = shadow
+	v.Invalidate()
+}
+
+// SetFocusable sets whether the view can be focused.
+func (v *View) SetFocusable(focusable bool) {
+	if focusable != v.Attrs.Focusable {
+		v.Attrs.Focusable = focusable
+	}
+}
+
+// SetTabIndex sets the order of the view in Tab navigation.
+func (v *View) SetTabIndex(tabIndex int) {
+	if tabIndex != v.Attrs.TabIndex {
+		v.Attrs.TabIndex = tabIndex
+	}
+}
+
 // SetHidden sets the hidden property of the view.
 func (v *View) SetHidden(hidden bool) {
 	if hidden == v.Attrs.Hidden {
@@ -472,27 +1062,68 @@ func (v *View) SetHidden(hidden bool) {
 
 func (v *View) Config() ViewConfig {
 	cfg := ViewConfig{
-		TagName:      v.Attrs.TagName,
-		ID:           v.Attrs.ID,
-		Left:         v.Attrs.Left,
-		Right:        v.Attrs.Right,
-		Top:          v.Attrs.Top,
-		Bottom:       v.Attrs.Bottom,
-		Width:        v.Attrs.Width,
-		Height:       v.Attrs.Height,
-		MarginLeft:   v.Attrs.MarginLeft,
-		MarginTop:    v.Attrs.MarginTop,
-		MarginRight:  v.Attrs.MarginRight,
-		MarginBottom: v.Attrs.MarginBottom,
-		Position:     v.Attrs.Position,
-		Direction:    v.Attrs.Direction,
-		Wrap:         v.Attrs.Wrap,
-		Justify:      v.Attrs.Justify,
-		AlignItems:   v.Attrs.AlignItems,
-		AlignContent: v.Attrs.AlignContent,
-		Grow:         v.Attrs.Grow,
-		Shrink:       v.Attrs.Shrink,
-		children:     []ViewConfig{},
+		TagName:          v.Attrs.TagName,
+		ID:               v.Attrs.ID,
+		Left:             v.Attrs.Left,
+		Right:            v.Attrs.Right,
+		Top:              v.Attrs.Top,
+		Bottom:           v.Attrs.Bottom,
+		Width:            v.Attrs.Width,
+		Height:           v.Attrs.Height,
+		MinWidth:         v.Attrs.MinWidth,
+		MaxWidth:         v.Attrs.MaxWidth,
+		MinHeight:        v.Attrs.MinHeight,
+		MaxHeight:        v.Attrs.MaxHeight,
+		MarginLeft:       v.Attrs.MarginLeft,
+		MarginTop:        v.Attrs.MarginTop,
+		MarginRight:      v.Attrs.MarginRight,
+		MarginBottom:     v.Attrs.MarginBottom,
+		PaddingLeft:      v.Attrs.PaddingLeft,
+		PaddingTop:       v.Attrs.PaddingTop,
+		PaddingRight:     v.Attrs.PaddingRight,
+		PaddingBottom:    v.Attrs.PaddingBottom,
+		RowGap:           v.Attrs.RowGap,
+		ColumnGap:        v.Attrs.ColumnGap,
+		Position:         v.Attrs.Position,
+		Direction:        v.Attrs.Direction,
+		Wrap:             v.Attrs.Wrap,
+		Justify:          v.Attrs.Justify,
+		AlignItems:       v.Attrs.AlignItems,
+		AlignContent:     v.Attrs.AlignContent,
+		AlignSelf:        v.Attrs.AlignSelf,
+		Order:            v.Attrs.Order,
+		ZIndex:           v.Attrs.ZIndex,
+		Grow:             v.Attrs.Grow,
+		Shrink:           v.Attrs.Shrink,
+		Basis:            v.Attrs.Basis,
+		Overflow:         v.Attrs.Overflow,
+		ScrollbarWidth:   v.Attrs.ScrollbarWidth,
+		Focusable:        v.Attrs.Focusable,
+		TabIndex:         v.Attrs.TabIndex,
+		Color:            v.Attrs.Color,
+		FontFamily:       v.Attrs.FontFamily,
+		FontSize:         v.Attrs.FontSize,
+		LineHeight:       v.Attrs.LineHeight,
+		TextAlign:        v.Attrs.TextAlign,
+		VerticalAlign:    v.Attrs.VerticalAlign,
+		WhiteSpace:       v.Attrs.WhiteSpace,
+		TextOverflow:     v.Attrs.TextOverflow,
+		BackgroundColor:  v.Attrs.BackgroundColor,
+		BackgroundImage:  v.Attrs.BackgroundImage,
+		BorderWidth:      v.Attrs.BorderWidth,
+		BorderColor:      v.Attrs.BorderColor,
+		BorderRadius:     v.Attrs.BorderRadius,
+		BorderImage:      v.Attrs.BorderImage,
+		Opacity:          v.Attrs.Opacity,
+		BoxShadow:        v.Attrs.BoxShadow,
+		Scale:            v.Attrs.Scale,
+		Transform:        v.Attrs.Transform,
+		TransformOriginX: v.Attrs.TransformOriginX,
+		TransformOriginY: v.Attrs.TransformOriginY,
+		Transitions:      v.Attrs.Transitions,
+		Animations:       v.Attrs.Animations,
+		Cache:            v.Attrs.Cache,
+		children:         []ViewConfig{},
 	}
 	for _, child := range v.GetChildren() {
 		cfg.children = append(cfg.children, child.Config())
@@ -501,32 +1132,77 @@ func (v *View) Config() ViewConfig {
 }
 
 func (v *View) handleDrawRoot(screen *ebiten.Image, b image.Rectangle) {
-	v.Handler.HandleDraw(screen, b, v)
+	if v.Attrs.Hidden || v.Attrs.Display == DisplayNone {
+		v.Handler.HandleDraw(screen, b, v)
+		return
+	}
+	v.drawContent(screen, b)
 }
 
 // This is for debugging and testing.
 type ViewConfig struct {
-	TagName      string
-	ID           string
-	Left         int
-	Right        *int
-	Top          int
-	Bottom       *int
-	Width        int
-	Height       int
-	MarginLeft   int
-	MarginTop    int
-	MarginRight  int
-	MarginBottom int
-	Position     FlexPosition
-	Direction    FlexDirection
-	Wrap         FlexWrap
-	Justify      FlexJustify
-	AlignItems   FlexAlignItem
-	AlignContent FlexAlignContent
-	Grow         float64
-	Shrink       float64
-	children     []ViewConfig
+	TagName          string
+	ID               string
+	Focusable        bool
+	TabIndex         int
+	Left             Length
+	Right            Length
+	Top              Length
+	Bottom           Length
+	Width            Length
+	Height           Length
+	MinWidth         Length
+	MaxWidth         Length
+	MinHeight        Length
+	MaxHeight        Length
+	MarginLeft       Length
+	MarginTop        Length
+	MarginRight      Length
+	MarginBottom     Length
+	PaddingLeft      Length
+	PaddingTop       Length
+	PaddingRight     Length
+	PaddingBottom    Length
+	RowGap           int
+	ColumnGap        int
+	Position         FlexPosition
+	Direction        FlexDirection
+	Wrap             FlexWrap
+	Justify          FlexJustify
+	AlignItems       FlexAlignItem
+	AlignContent     FlexAlignContent
+	AlignSelf        FlexAlignSelf
+	Order            int
+	ZIndex           int
+	Grow             float64
+	Shrink           float64
+	Basis            Length
+	Overflow         FlexOverflow
+	ScrollbarWidth   int
+	Color            color.Color
+	FontFamily       string
+	FontSize         Length
+	LineHeight       Length
+	TextAlign        FlexTextAlign
+	VerticalAlign    FlexVerticalAlign
+	WhiteSpace       FlexWhiteSpace
+	TextOverflow     FlexTextOverflow
+	BackgroundColor  color.Color
+	BackgroundImage  string
+	BorderWidth      int
+	BorderColor      color.Color
+	BorderRadius     float64
+	BorderImage      *NineSlice
+	Opacity          *float64
+	BoxShadow        *Shadow
+	Scale            *float64
+	Transform        []TransformFunc
+	TransformOriginX Length
+	TransformOriginY Length
+	Transitions      []Transition
+	Animations       []Animation
+	Cache            bool
+	children         []ViewConfig
 }
 
 func (cfg ViewConfig) Tree() string {
@@ -540,10 +1216,20 @@ func (cfg ViewConfig) tree(indent string) string {
 	if cfg.ID != "" {
 		sb.WriteString(fmt.Sprintf("id=\"%s\" ", cfg.ID))
 	}
+	if cfg.Focusable {
+		sb.WriteString(fmt.Sprintf("tabindex=\"%d\" ", cfg.TabIndex))
+	}
+	opacity, scale := 1.0, 1.0
+	if cfg.Opacity != nil {
+		opacity = *cfg.Opacity
+	}
+	if cfg.Scale != nil {
+		scale = *cfg.Scale
+	}
 	sb.WriteString("style=\"")
 	sb.WriteString(
-		fmt.Sprintf("left: %d, right: %d, top: %d, bottom: %d, width: %d, height: %d, marginLeft: %d, marginTop: %d, marginRight: %d, marginBottom: %d, position: %s, direction: %s, wrap: %s, justify: %s, alignItems: %s, alignContent: %s, grow: %f, shrink: %f",
-			cfg.Left, *cfg.Right, cfg.Top, *cfg.Bottom, cfg.Width, cfg.Height, cfg.MarginLeft, cfg.MarginTop, cfg.MarginRight, cfg.MarginBottom, cfg.Position, cfg.Direction, cfg.Wrap, cfg.Justify, cfg.AlignItems, cfg.AlignContent, cfg.Grow, cfg.Shrink))
+		fmt.Sprintf("left: %s, right: %s, top: %s, bottom: %s, width: %s, height: %s, minWidth: %s, maxWidth: %s, minHeight: %s, maxHeight: %s, marginLeft: %s, marginTop: %s, marginRight: %s, marginBottom: %s, paddingLeft: %s, paddingTop: %s, paddingRight: %s, paddingBottom: %s, rowGap: %d, columnGap: %d, position: %s, direction: %s, wrap: %s, justify: %s, alignItems: %s, alignContent: %s, alignSelf: %s, order: %d, zIndex: %d, grow: %f, shrink: %f, basis: %s, overflow: %s, scrollbarWidth: %d, color: %v, fontFamily: %s, fontSize: %s, lineHeight: %s, textAlign: %s, verticalAlign: %s, whiteSpace: %s, textOverflow: %s, backgroundColor: %v, backgroundImage: %s, borderWidth: %d, borderColor: %v, borderRadius: %f, borderImage: %v, opacity: %f, boxShadow: %v, scale: %f, transform: %v, transformOrigin: %s %s, transitions: %v, animations: %v, cache: %t",
+			cfg.Left, cfg.Right, cfg.Top, cfg.Bottom, cfg.Width, cfg.Height, cfg.MinWidth, cfg.MaxWidth, cfg.MinHeight, cfg.MaxHeight, cfg.MarginLeft, cfg.MarginTop, cfg.MarginRight, cfg.MarginBottom, cfg.PaddingLeft, cfg.PaddingTop, cfg.PaddingRight, cfg.PaddingBottom, cfg.RowGap, cfg.ColumnGap, cfg.Position, cfg.Direction, cfg.Wrap, cfg.Justify, cfg.AlignItems, cfg.AlignContent, cfg.AlignSelf, cfg.Order, cfg.ZIndex, cfg.Grow, cfg.Shrink, cfg.Basis, cfg.Overflow, cfg.ScrollbarWidth, cfg.Color, cfg.FontFamily, cfg.FontSize, cfg.LineHeight, cfg.TextAlign, cfg.VerticalAlign, cfg.WhiteSpace, cfg.TextOverflow, cfg.BackgroundColor, cfg.BackgroundImage, cfg.BorderWidth, cfg.BorderColor, cfg.BorderRadius, cfg.BorderImage, opacity, cfg.BoxShadow, scale, cfg.Transform, cfg.TransformOriginX, cfg.TransformOriginY, cfg.Transitions, cfg.Animations, cfg.Cache))
 	sb.WriteString("\">\n")
 	for _, child := range cfg.children {
 		sb.WriteString(child.tree(indent + "  "))
@@ -10,8 +10,8 @@ import (
 func TestAddChildUpdateRemove(t *testing.T) {
 	view := &View{
 		Attrs: ViewAttrs{
-			Width:      100,
-			Height:     100,
+			Width:      Px(100),
+			Height:     Px(100),
 			Direction:  Row,
 			Justify:    JustifyStart,
 			AlignItems: AlignItemStart,
@@ -20,8 +20,8 @@ func TestAddChildUpdateRemove(t *testing.T) {
 	mock := NewMockHandler()
 	child := &View{
 		Attrs: ViewAttrs{
-			Width:  10,
-			Height: 10,
+			Width:  Px(10),
+			Height: Px(10),
 		},
 		Handler: mock.ViewHandler,
 	}
@@ -41,8 +41,8 @@ func TestAddChildUpdateRemove(t *testing.T) {
 func TestUpdateWithSize(t *testing.T) {
 	view := &View{
 		Attrs: ViewAttrs{
-			Width:      100,
-			Height:     100,
+			Width:      Px(100),
+			Height:     Px(100),
 			Direction:  Row,
 			Justify:    JustifyCenter,
 			AlignItems: AlignItemCenter,
@@ -52,8 +52,8 @@ func TestUpdateWithSize(t *testing.T) {
 	mock := NewMockHandler()
 	child := &View{
 		Attrs: ViewAttrs{
-			Width:  10,
-			Height: 10,
+			Width:  Px(10),
+			Height: Px(10),
 		},
 		Handler: mock.ViewHandler,
 	}
@@ -70,8 +70,8 @@ func TestUpdateWithSize(t *testing.T) {
 func TestAddToParent(t *testing.T) {
 	root := &View{
 		Attrs: ViewAttrs{
-			Width:      100,
-			Height:     100,
+			Width:      Px(100),
+			Height:     Px(100),
 			Direction:  Row,
 			Justify:    JustifyStart,
 			AlignItems: AlignItemStart,
@@ -82,8 +82,8 @@ func TestAddToParent(t *testing.T) {
 
 	child := (&View{
 		Attrs: ViewAttrs{
-			Width:  10,
-			Height: 10,
+			Width:  Px(10),
+			Height: Px(10),
 		},
 		Handler: mock.ViewHandler,
 	})
@@ -98,8 +98,8 @@ func TestAddToParent(t *testing.T) {
 func TestAddChild(t *testing.T) {
 	view := &View{
 		Attrs: ViewAttrs{
-			Width:      100,
-			Height:     100,
+			Width:      Px(100),
+			Height:     Px(100),
 			Direction:  Row,
 			Justify:    JustifyStart,
 			AlignItems: AlignItemStart,
@@ -110,15 +110,15 @@ func TestAddChild(t *testing.T) {
 	require.Equal(t, view, view.AddChild(
 		&View{
 			Attrs: ViewAttrs{
-				Width:  10,
-				Height: 10,
+				Width:  Px(10),
+				Height: Px(10),
 			},
 			Handler: mocks[0].ViewHandler,
 		},
 		&View{
 			Attrs: ViewAttrs{
-				Width:  10,
-				Height: 10,
+				Width:  Px(10),
+				Height: Px(10),
 			},
 			Handler: mocks[1].ViewHandler,
 		},
@@ -1,64 +1,126 @@
 package furex
 
+import "image/color"
+
 type ViewOption func(v *View)
 
 func Height(h int) ViewOption {
 	return func(v *View) {
-		v.Attrs.Height = h
+		v.Attrs.Height = Px(float64(h))
 	}
 }
 
 func Width(w int) ViewOption {
 	return func(v *View) {
-		v.Attrs.Width = w
+		v.Attrs.Width = Px(float64(w))
+	}
+}
+
+func MinWidth(w Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.MinWidth = w
+	}
+}
+
+func MaxWidth(w Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.MaxWidth = w
+	}
+}
+
+func MinHeight(h Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.MinHeight = h
+	}
+}
+
+func MaxHeight(h Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.MaxHeight = h
 	}
 }
 
 func Left(l int) ViewOption {
 	return func(v *View) {
-		v.Attrs.Left = l
+		v.Attrs.Left = Px(float64(l))
 	}
 }
 
 func Top(t int) ViewOption {
 	return func(v *View) {
-		v.Attrs.Top = t
+		v.Attrs.Top = Px(float64(t))
 	}
 }
 
 func Right(r int) ViewOption {
 	return func(v *View) {
-		v.Attrs.Right = &r
+		v.Attrs.Right = Px(float64(r))
 	}
 }
 
 func Bottom(b int) ViewOption {
 	return func(v *View) {
-		v.Attrs.Bottom = &b
+		v.Attrs.Bottom = Px(float64(b))
 	}
 }
 
 func MarginLeft(ml int) ViewOption {
 	return func(v *View) {
-		v.Attrs.MarginLeft = ml
+		v.Attrs.MarginLeft = Px(float64(ml))
 	}
 }
 
 func MarginTop(mt int) ViewOption {
 	return func(v *View) {
-		v.Attrs.MarginTop = mt
+		v.Attrs.MarginTop = Px(float64(mt))
 	}
 }
 
 func MarginRight(mr int) ViewOption {
 	return func(v *View) {
-		v.Attrs.MarginRight = mr
+		v.Attrs.MarginRight = Px(float64(mr))
 	}
 }
 
 func MarginBottom(mb int) ViewOption {
 	return func(v *View) {
-		v.Attrs.MarginBottom = mb
+		v.Attrs.MarginBottom = Px(float64(mb))
+	}
+}
+
+func PaddingLeft(pl int) ViewOption {
+	return func(v *View) {
+		v.Attrs.PaddingLeft = Px(float64(pl))
+	}
+}
+
+func PaddingTop(pt int) ViewOption {
+	return func(v *View) {
+		v.Attrs.PaddingTop = Px(float64(pt))
+	}
+}
+
+func PaddingRight(pr int) ViewOption {
+	return func(v *View) {
+		v.Attrs.PaddingRight = Px(float64(pr))
+	}
+}
+
+func PaddingBottom(pb int) ViewOption {
+	return func(v *View) {
+		v.Attrs.PaddingBottom = Px(float64(pb))
+	}
+}
+
+func RowGap(rg int) ViewOption {
+	return func(v *View) {
+		v.Attrs.RowGap = rg
+	}
+}
+
+func ColumnGap(cg int) ViewOption {
+	return func(v *View) {
+		v.Attrs.ColumnGap = cg
 	}
 }
 
@@ -98,6 +160,192 @@ func AlignContent(ac FlexAlignContent) ViewOption {
 	}
 }
 
+func AlignSelf(as FlexAlignSelf) ViewOption {
+	return func(v *View) {
+		v.Attrs.AlignSelf = as
+	}
+}
+
+func Order(o int) ViewOption {
+	return func(v *View) {
+		v.Attrs.Order = o
+	}
+}
+
+func ZIndex(z int) ViewOption {
+	return func(v *View) {
+		v.Attrs.ZIndex = z
+	}
+}
+
+func Basis(b Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.Basis = b
+	}
+}
+
+func Overflow(o FlexOverflow) ViewOption {
+	return func(v *View) {
+		v.Attrs.Overflow = o
+	}
+}
+
+func ScrollbarWidth(w int) ViewOption {
+	return func(v *View) {
+		v.Attrs.ScrollbarWidth = w
+	}
+}
+
+func Text(t string) ViewOption {
+	return func(v *View) {
+		v.Attrs.Text = t
+	}
+}
+
+func Spans(spans ...TextSpan) ViewOption {
+	return func(v *View) {
+		v.Attrs.Text, v.Attrs.Spans = spansText(spans), spans
+	}
+}
+
+func Color(c color.Color) ViewOption {
+	return func(v *View) {
+		v.Attrs.Color = c
+	}
+}
+
+func FontFamily(f string) ViewOption {
+	return func(v *View) {
+		v.Attrs.FontFamily = f
+	}
+}
+
+func FontSize(s Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.FontSize = s
+	}
+}
+
+func LineHeight(l Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.LineHeight = l
+	}
+}
+
+func TextAlign(a FlexTextAlign) ViewOption {
+	return func(v *View) {
+		v.Attrs.TextAlign = a
+	}
+}
+
+func VerticalAlign(a FlexVerticalAlign) ViewOption {
+	return func(v *View) {
+		v.Attrs.VerticalAlign = a
+	}
+}
+
+func WhiteSpace(w FlexWhiteSpace) ViewOption {
+	return func(v *View) {
+		v.Attrs.WhiteSpace = w
+	}
+}
+
+func TextOverflow(o FlexTextOverflow) ViewOption {
+	return func(v *View) {
+		v.Attrs.TextOverflow = o
+	}
+}
+
+func BackgroundColor(c color.Color) ViewOption {
+	return func(v *View) {
+		v.Attrs.BackgroundColor = c
+	}
+}
+
+func BackgroundImage(name string) ViewOption {
+	return func(v *View) {
+		v.Attrs.BackgroundImage = name
+	}
+}
+
+func BorderWidth(w int) ViewOption {
+	return func(v *View) {
+		v.Attrs.BorderWidth = w
+	}
+}
+
+func BorderColor(c color.Color) ViewOption {
+	return func(v *View) {
+		v.Attrs.BorderColor = c
+	}
+}
+
+func BorderRadius(r float64) ViewOption {
+	return func(v *View) {
+		v.Attrs.BorderRadius = r
+	}
+}
+
+func BorderImage(img NineSlice) ViewOption {
+	return func(v *View) {
+		v.Attrs.BorderImage = &img
+	}
+}
+
+func Opacity(o float64) ViewOption {
+	return func(v *View) {
+		v.Attrs.Opacity = &o
+	}
+}
+
+func Scale(s float64) ViewOption {
+	return func(v *View) {
+		v.Attrs.Scale = &s
+	}
+}
+
+func Transform(transform ...TransformFunc) ViewOption {
+	return func(v *View) {
+		v.Attrs.Transform = transform
+	}
+}
+
+func TransformOrigin(x, y Length) ViewOption {
+	return func(v *View) {
+		v.Attrs.TransformOriginX, v.Attrs.TransformOriginY = x, y
+	}
+}
+
+func Transitions(transitions ...Transition) ViewOption {
+	return func(v *View) {
+		v.Attrs.Transitions = transitions
+	}
+}
+
+func Animations(animations ...Animation) ViewOption {
+	return func(v *View) {
+		v.Attrs.Animations = animations
+	}
+}
+
+func Cache(cache bool) ViewOption {
+	return func(v *View) {
+		v.Attrs.Cache = cache
+	}
+}
+
+func DrawText(drawText bool) ViewOption {
+	return func(v *View) {
+		v.Attrs.DrawText = drawText
+	}
+}
+
+func BoxShadow(s Shadow) ViewOption {
+	return func(v *View) {
+		v.Attrs.BoxShadow = &s
+	}
+}
+
 func Grow(g float64) ViewOption {
 	return func(v *View) {
 		v.Attrs.Grow = g
@@ -122,6 +370,34 @@ func Hidden(h bool) ViewOption {
 	}
 }
 
+func Focusable(f bool) ViewOption {
+	return func(v *View) {
+		v.Attrs.Focusable = f
+	}
+}
+
+func TabIndex(i int) ViewOption {
+	return func(v *View) {
+		v.Attrs.TabIndex = i
+	}
+}
+
+// Nav sets the ID of the view focused by gamepad navigation from the view in the direction.
+func Nav(dir NavAction, id string) ViewOption {
+	return func(v *View) {
+		switch dir {
+		case NavActionUp:
+			v.Attrs.NavUp = id
+		case NavActionDown:
+			v.Attrs.NavDown = id
+		case NavActionLeft:
+			v.Attrs.NavLeft = id
+		case NavActionRight:
+			v.Attrs.NavRight = id
+		}
+	}
+}
+
 func ID(id string) ViewOption {
 	return func(v *View) {
 		v.Attrs.ID = id